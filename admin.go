@@ -0,0 +1,255 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"sort"
+)
+
+// --- Admin API ---
+//
+// With ADMIN_TOKEN set, /admin/keys lets operators manage API keys at
+// runtime and /debug/pprof/ serves runtime profiles. Requests must send
+// "Authorization: Bearer <ADMIN_TOKEN>". Key changes are written back to
+// API_KEYS_FILE when it is configured; keys from the API_KEYS variable can be
+// changed but come back on restart. A config reload keeps the changes the
+// file does not record.
+
+// keyInfo is an API key as shown by the admin API, with the secret masked.
+type keyInfo struct {
+	Name     string `json:"name"`
+	Tier     string `json:"tier"`
+	Key      string `json:"key"`
+	FromEnv  bool   `json:"from_env,omitempty"`
+	Sessions int    `json:"sessions"`
+}
+
+func maskKey(key string) string {
+	if len(key) <= 8 {
+		return "****"
+	}
+	return key[:4] + "****" + key[len(key)-4:]
+}
+
+func (kr *keyRing) list() []keyInfo {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	sessions := make(map[string]int)
+	for _, k := range kr.sessions {
+		sessions[k.Key]++
+	}
+	out := make([]keyInfo, 0, len(kr.keys))
+	for _, k := range kr.keys {
+		out = append(out, keyInfo{Name: k.Name, Tier: k.Tier, Key: maskKey(k.Key), FromEnv: k.fromEnv, Sessions: sessions[k.Key]})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// byName must be called with kr.mu held.
+func (kr *keyRing) byName(name string) (apiKey, bool) {
+	for _, k := range kr.keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return apiKey{}, false
+}
+
+func (kr *keyRing) create(name, tier string) (apiKey, error) {
+	b := make([]byte, 24)
+	rand.Read(b)
+	k := apiKey{Key: "lsk_" + hex.EncodeToString(b), Name: name, Tier: tier, admin: true}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if _, exists := kr.byName(name); exists {
+		return apiKey{}, fmt.Errorf("a key named %q already exists", name)
+	}
+	kr.keys[k.Key] = k
+	kr.persist()
+	return k, nil
+}
+
+// revoke removes the named key and ends its hold on open sessions, which
+// fall back to anonymous.
+func (kr *keyRing) revoke(name string) bool {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	k, ok := kr.byName(name)
+	if !ok {
+		return false
+	}
+	delete(kr.keys, k.Key)
+	if k.fromEnv {
+		kr.revoked[k.Key] = true
+	}
+	for session, bound := range kr.sessions {
+		if bound.Key == k.Key {
+			delete(kr.sessions, session)
+		}
+	}
+	kr.persist()
+	return true
+}
+
+func (kr *keyRing) setTier(name, tier string) bool {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	k, ok := kr.byName(name)
+	if !ok {
+		return false
+	}
+	k.Tier, k.admin = tier, true
+	kr.keys[k.Key] = k
+	for session, bound := range kr.sessions {
+		if bound.Key == k.Key {
+			kr.sessions[session] = k
+		}
+	}
+	kr.persist()
+	return true
+}
+
+// persist must be called with kr.mu held.
+func (kr *keyRing) persist() {
+	if kr.path == "" {
+		return
+	}
+	keys := make([]apiKey, 0, len(kr.keys))
+	for _, k := range kr.keys {
+		if !k.fromEnv {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		log.Printf("API keys: encode error: %v", err)
+		return
+	}
+	tmp := kr.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		log.Printf("API keys: write error: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, kr.path); err != nil {
+		log.Printf("API keys: rename error: %v", err)
+	}
+}
+
+// adminOnly checks the admin token in constant time.
+func adminOnly(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(presentedKey(r)), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="livescore-mcp-admin"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "admin token required"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func registerAdminRoutes(mux *http.ServeMux, token string, kr *keyRing, tiers map[string]rateTier) {
+	mux.Handle("/debug/pprof/", adminOnly(token, pprofMux().ServeHTTP))
+
+	validTier := func(tier string) bool {
+		_, ok := tiers[tier]
+		return ok && tier != tierAnonymous
+	}
+
+	mux.HandleFunc("GET /admin/keys", adminOnly(token, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, kr.list())
+	}))
+
+	mux.HandleFunc("POST /admin/keys", adminOnly(token, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Name string `json:"name"`
+			Tier string `json:"tier"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&body); err != nil || body.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": `expected {"name": "...", "tier": "..."}`})
+			return
+		}
+		if reservedKeyName(body.Name) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("name %q is reserved: names may not start with oauth:, key: or key-", body.Name)})
+			return
+		}
+		if body.Tier == "" {
+			body.Tier = tierFree
+		}
+		if !validTier(body.Tier) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("unknown tier %q", body.Tier)})
+			return
+		}
+		k, err := kr.create(body.Name, body.Tier)
+		if err != nil {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+		log.Printf("Admin: created API key %s (%s)", k.Name, k.Tier)
+		// The only time the full key is returned.
+		writeJSON(w, http.StatusCreated, k)
+	}))
+
+	mux.HandleFunc("PATCH /admin/keys/{name}", adminOnly(token, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Tier string `json:"tier"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&body); err != nil || !validTier(body.Tier) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("unknown tier %q", body.Tier)})
+			return
+		}
+		name := r.PathValue("name")
+		if !kr.setTier(name, body.Tier) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("no key named %q", name)})
+			return
+		}
+		log.Printf("Admin: moved API key %s to %s", name, body.Tier)
+		writeJSON(w, http.StatusOK, map[string]string{"name": name, "tier": body.Tier})
+	}))
+
+	mux.HandleFunc("DELETE /admin/keys/{name}", adminOnly(token, func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if !kr.revoke(name) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("no key named %q", name)})
+			return
+		}
+		log.Printf("Admin: revoked API key %s", name)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+// pprofMux serves the net/http/pprof handlers under /debug/pprof/.
+func pprofMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// servePprof exposes profiles on a separate listener (PPROF_ADDR), meant to
+// be bound to localhost or a private interface.
+func servePprof(addr string) {
+	log.Printf("pprof listening on %s", addr)
+	if err := http.ListenAndServe(addr, pprofMux()); err != nil {
+		log.Printf("pprof: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Attendance ---
+//
+// Match pages carry the crowd for some matches, at the root or with the
+// venue. get_match and get_match_stats lift it to an attendance field, and
+// get_league_attendance adds it up per home team over a league's finished
+// matches, fetching the match pages where the feed has no crowd figure, the
+// most recent maxSeasonMatches of them.
+
+var attendanceKeys = []string{"attendance", "spectators", "crowd"}
+
+// attendance returns the crowd of a match, at its root or with its venue.
+// It looks no deeper, where the crowd would be another match's (h2h).
+func attendance(m map[string]interface{}) (int, bool) {
+	v, ok := lookup(m, attendanceKeys...)
+	if !ok {
+		venue, _ := lookup(m, "venue", "stadium")
+		vm, _ := venue.(map[string]interface{})
+		if v, ok = lookup(vm, attendanceKeys...); !ok {
+			return 0, false
+		}
+	}
+	n, ok := statValue(v)
+	return int(n), ok && n > 0
+}
+
+// withAttendance adds a top-level attendance field to a match payload when
+// upstream has the crowd with the venue.
+func withAttendance(data interface{}) interface{} {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+	if n, ok := attendance(m); ok {
+		if _, top := lookup(m, attendanceKeys...); !top {
+			m["attendance"] = n
+		}
+	}
+	return m
+}
+
+type clubAttendance struct {
+	TeamID  string `json:"team_id,omitempty"`
+	Team    string `json:"team"`
+	Matches int    `json:"home_matches"`
+	Total   int    `json:"total"`
+	Average int    `json:"average"`
+	Highest int    `json:"highest"`
+	Lowest  int    `json:"lowest"`
+}
+
+type leagueAttendance struct {
+	Matches int              `json:"matches"`
+	Total   int              `json:"total"`
+	Average int              `json:"average"`
+	Highest *crowdMatch      `json:"highest,omitempty"`
+	Teams   []clubAttendance `json:"teams"`
+}
+
+type crowdMatch struct {
+	MatchID    string `json:"match_id"`
+	Home       string `json:"home"`
+	Away       string `json:"away"`
+	Attendance int    `json:"attendance"`
+}
+
+// aggregateAttendance sums the crowds of matches per home team, highest
+// average first.
+func aggregateAttendance(matches []feedMatch) leagueAttendance {
+	la := leagueAttendance{Teams: []clubAttendance{}}
+	clubs := map[string]*clubAttendance{}
+	var order []string
+	for _, fm := range matches {
+		crowd, ok := attendance(fm.Raw)
+		if !ok {
+			continue
+		}
+		la.Matches++
+		la.Total += crowd
+		if la.Highest == nil || crowd > la.Highest.Attendance {
+			la.Highest = &crowdMatch{fm.ID, fm.HomeName, fm.AwayName, crowd}
+		}
+		key := fm.HomeID
+		if key == "" {
+			key = fm.HomeName
+		}
+		club := clubs[key]
+		if club == nil {
+			club = &clubAttendance{TeamID: fm.HomeID, Team: fm.HomeName, Lowest: crowd}
+			clubs[key] = club
+			order = append(order, key)
+		}
+		club.Matches++
+		club.Total += crowd
+		club.Highest = max(club.Highest, crowd)
+		club.Lowest = min(club.Lowest, crowd)
+	}
+	if la.Matches > 0 {
+		la.Average = la.Total / la.Matches
+	}
+	for _, key := range order {
+		club := clubs[key]
+		club.Average = club.Total / club.Matches
+		la.Teams = append(la.Teams, *club)
+	}
+	sort.SliceStable(la.Teams, func(i, j int) bool { return la.Teams[i].Average > la.Teams[j].Average })
+	return la
+}
+
+func registerAttendanceTools(s *server.MCPServer, catalog *competitionCatalog) {
+	s.AddTool(
+		mcp.NewTool("get_league_attendance",
+			mcp.WithDescription("Get a league's attendance for the season: total and average crowd, the best attended match, and per home team the matches, total, average, highest and lowest crowd"),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			matches, finished := finishedWith(ctx, req, data, maxSeasonMatches, func(fm feedMatch) bool {
+				_, ok := attendance(fm.Raw)
+				return ok
+			})
+			if len(matches) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("upstream has no attendance figures for %s", key)), nil
+			}
+			return jsonResult(fmt.Sprintf("Attendance for %s (%d of %d finished matches have a crowd figure)", key, len(matches), finished),
+				aggregateAttendance(matches)), nil
+		},
+	)
+}
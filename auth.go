@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// --- API Keys ---
+//
+// API keys are optional. A key can be presented on /sse or /message with
+// "Authorization: Bearer <key>" or the api_key query parameter; the session
+// opened on /sse stays bound to it. Requests without a key are served as
+// anonymous, while an unknown key is rejected.
+//
+// Rate limits and per-client state follow a key's id, a digest of the key
+// itself, so two keys never share them even if their names look alike. Names
+// must still be unique, as the admin API addresses keys by name, and may not
+// start with "oauth:", "key:" or "key-", which OAuth subjects and generated
+// names use.
+
+type apiKey struct {
+	Key     string `json:"key"`
+	Name    string `json:"name"`
+	Tier    string `json:"tier,omitempty"`
+	fromEnv bool   // set via API_KEYS or the config file, never written to API_KEYS_FILE
+	admin   bool   // created or changed through the admin API
+}
+
+// id identifies the client behind k: "key:" and a digest of the key, or
+// "oauth:" and the subject for OAuth tokens.
+func (k apiKey) id() string {
+	if k.Key == "" {
+		return k.Name
+	}
+	sum := sha256.Sum256([]byte(k.Key))
+	return "key:" + hex.EncodeToString(sum[:8])
+}
+
+// reservedKeyName reports whether name looks like an OAuth subject or a
+// generated key name, which configured and created keys may not use.
+func reservedKeyName(name string) bool {
+	return strings.HasPrefix(name, "oauth:") || strings.HasPrefix(name, "key:") || strings.HasPrefix(name, "key-")
+}
+
+type apiKeyContextKey struct{}
+
+type keyRing struct {
+	mu       sync.RWMutex
+	keys     map[string]apiKey
+	sessions map[string]apiKey // session ID -> key it was opened with
+	oauth    *oauthVerifier    // nil unless OAuth is configured
+	path     string            // API_KEYS_FILE, rewritten on admin changes
+	revoked  map[string]bool   // configured keys revoked through the admin API
+}
+
+func newKeyRing() *keyRing {
+	return &keyRing{
+		keys:     make(map[string]apiKey),
+		sessions: make(map[string]apiKey),
+		revoked:  make(map[string]bool),
+	}
+}
+
+// loadAPIKeys reads keys from API_KEYS_FILE (a JSON array of {"key", "name",
+// "tier"}) and from the config file and API_KEYS (comma-separated "name:key"
+// pairs or bare keys, in the free tier).
+func loadAPIKeys(cfg *serverConfig) *keyRing {
+	kr := newKeyRing()
+	kr.path = cfg.APIKeysFile
+	if kr.path != "" {
+		var keys []apiKey
+		data, err := os.ReadFile(kr.path)
+		if err == nil {
+			err = json.Unmarshal(data, &keys)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			log.Printf("API keys: cannot load %s: %v", kr.path, err)
+		}
+		for _, k := range keys {
+			kr.add(k)
+		}
+	}
+	for _, k := range cfg.APIKeys {
+		k.fromEnv = true
+		kr.add(k)
+	}
+	if n := len(kr.keys); n > 0 {
+		log.Printf("API keys: %d key(s) loaded", n)
+	}
+	return kr
+}
+
+// reload replaces the keys with those from cfg and API_KEYS_FILE. Admin API
+// changes that API_KEYS_FILE does not hold (every change without the file,
+// and changes to configured keys) are kept over the reloaded ones. Open
+// sessions keep their key, with any new tier, unless it was removed, in
+// which case they continue as anonymous.
+func (kr *keyRing) reload(cfg *serverConfig) {
+	fresh := loadAPIKeys(cfg)
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	for key, k := range kr.keys {
+		if k.admin && (k.fromEnv || fresh.path == "") {
+			fresh.keys[key] = k
+		}
+	}
+	for key := range kr.revoked {
+		delete(fresh.keys, key)
+	}
+	kr.keys, kr.path = fresh.keys, fresh.path
+	for session, bound := range kr.sessions {
+		if bound.Key == "" {
+			continue // OAuth subject, not a key
+		}
+		if k, ok := kr.keys[bound.Key]; ok {
+			kr.sessions[session] = k
+		} else {
+			delete(kr.sessions, session)
+		}
+	}
+}
+
+// add adds k, named after its id if it has no name. A key whose name is
+// already taken by another key is left out.
+func (kr *keyRing) add(k apiKey) {
+	if k.Key == "" {
+		return
+	}
+	generated := strings.Replace(k.id(), ":", "-", 1)
+	if k.Name == "" {
+		k.Name = generated
+	} else if reservedKeyName(k.Name) && k.Name != generated {
+		log.Printf("API keys: ignoring key with reserved name %q", k.Name)
+		return
+	}
+	if k.Tier == "" {
+		k.Tier = tierFree
+	}
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if other, ok := kr.byName(k.Name); ok && other.Key != k.Key {
+		log.Printf("API keys: ignoring a second key named %q", k.Name)
+		return
+	}
+	kr.keys[k.Key] = k
+}
+
+func (kr *keyRing) lookup(key string) (apiKey, bool) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	k, ok := kr.keys[key]
+	return k, ok
+}
+
+func (kr *keyRing) bind(session string, k apiKey) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	kr.sessions[session] = k
+}
+
+// bound returns the key session was opened with.
+func (kr *keyRing) bound(session string) (apiKey, bool) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	k, ok := kr.sessions[session]
+	return k, ok
+}
+
+func (kr *keyRing) unbind(session string) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	delete(kr.sessions, session)
+}
+
+// presentedKey returns the API key sent with r, if any.
+func presentedKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if scheme, token, ok := strings.Cut(auth, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return r.URL.Query().Get("api_key")
+}
+
+// client identifies the key behind r: the key presented with the request,
+// or the one its session was opened with.
+func (kr *keyRing) client(r *http.Request) (apiKey, bool) {
+	if k, ok := r.Context().Value(apiKeyContextKey{}).(apiKey); ok {
+		return k, true
+	}
+	if session := r.URL.Query().Get("sessionId"); session != "" {
+		return kr.bound(session)
+	}
+	return apiKey{}, false
+}
+
+func apiKeyFromContext(ctx context.Context) (apiKey, bool) {
+	k, ok := ctx.Value(apiKeyContextKey{}).(apiKey)
+	return k, ok
+}
+
+// middleware rejects unknown keys and records valid ones in the request
+// context; requests without a key pass through as anonymous unless OAuth is
+// configured as required.
+func (kr *keyRing) middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := presentedKey(r)
+		if key == "" {
+			if _, bound := kr.client(r); !bound && kr.oauth != nil && kr.oauth.required {
+				kr.unauthorized(w, "authentication required")
+				return
+			}
+			next(w, r)
+			return
+		}
+		k, ok := kr.lookup(key)
+		if !ok && kr.oauth != nil {
+			var err error
+			if k, err = kr.oauth.verify(key); err != nil {
+				log.Printf("OAuth: rejected token: %v", err)
+				kr.unauthorized(w, "invalid token")
+				return
+			}
+			ok = true
+		}
+		if !ok {
+			kr.unauthorized(w, "invalid API key")
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, k)))
+	}
+}
+
+func (kr *keyRing) unauthorized(w http.ResponseWriter, msg string) {
+	challenge := `Bearer realm="livescore-mcp"`
+	if kr.oauth != nil {
+		challenge = kr.oauth.challenge()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", challenge)
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
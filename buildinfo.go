@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// --- Build Info ---
+//
+// Release builds stamp the commit and date with ldflags:
+//
+//	go build -ldflags "-X main.gitCommit=$(git rev-parse --short HEAD) -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without them the VCS details Go embeds from a git checkout are used.
+
+var (
+	gitCommit string
+	buildDate string
+)
+
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date,omitempty"`
+	Modified  bool   `json:"modified,omitempty"` // built from a dirty tree
+	GoVersion string `json:"go_version"`
+}
+
+func currentBuild() buildInfo {
+	b := buildInfo{
+		Version:   serverVersion,
+		Commit:    gitCommit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+	}
+	if b.Commit == "" {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			for _, s := range info.Settings {
+				switch s.Key {
+				case "vcs.revision":
+					b.Commit = s.Value[:min(len(s.Value), 12)]
+				case "vcs.time":
+					if b.BuildDate == "" {
+						b.BuildDate = s.Value
+					}
+				case "vcs.modified":
+					b.Modified = s.Value == "true"
+				}
+			}
+		}
+	}
+	if b.Commit == "" {
+		b.Commit = "unknown"
+	}
+	return b
+}
+
+func (b buildInfo) String() string {
+	s := fmt.Sprintf("%s %s (commit %s", serverName, b.Version, b.Commit)
+	if b.Modified {
+		s += "-dirty"
+	}
+	if b.BuildDate != "" {
+		s += ", built " + b.BuildDate
+	}
+	return s + ", " + b.GoVersion + ")"
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Bulk Lookups ---
+//
+// get_teams and get_matches fetch several teams or matches in one tool call,
+// concurrently, so an agent comparing clubs makes one round trip through the
+// rate limiter instead of one per ID. At most bulkConcurrency upstream
+// requests run at once per call. Results are keyed by ID; an ID that fails is
+// reported under errors without failing the others.
+
+const (
+	maxBulkTeams    = 10
+	maxBulkMatches  = 25
+	bulkConcurrency = 5
+)
+
+type bulkResult struct {
+	Results map[string]interface{} `json:"results"`
+	Errors  map[string]string      `json:"errors,omitempty"`
+
+	codes map[string]string // error code per failed ID
+}
+
+// failureCode is the error code of a call in which every ID failed:
+// codeNotFound when upstream has none of them, else codeUpstreamError.
+func (r bulkResult) failureCode() string {
+	for _, code := range r.codes {
+		if code != codeNotFound {
+			return codeUpstreamError
+		}
+	}
+	return codeNotFound
+}
+
+// getList reads a list argument given either as an array or as a
+// comma-separated string, dropping blanks and duplicates.
+func getList(args any, key string) []string {
+	var raw []string
+	switch v := toMap(args)[key].(type) {
+	case []interface{}:
+		for _, item := range v {
+			raw = append(raw, scalarString(item))
+		}
+	case string:
+		raw = strings.Split(v, ",")
+	}
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range raw {
+		s = strings.TrimSpace(s)
+		if s != "" && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// fetchBulk fetches every ID, bulkConcurrency at a time.
+func fetchBulk(ctx context.Context, req mcp.CallToolRequest, ids []string, fetch sourceFetch) bulkResult {
+	res := bulkResult{Results: make(map[string]interface{}, len(ids)), Errors: make(map[string]string), codes: make(map[string]string)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, bulkConcurrency)
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			data, err := fetch(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				res.Errors[id] = err.Error()
+				res.codes[id] = errorCode(err)
+			} else {
+				res.Results[id] = data
+			}
+			done := len(res.Results) + len(res.Errors)
+			reportProgress(ctx, req, done, len(ids), fmt.Sprintf("fetched %d/%d", done, len(ids)))
+		}()
+	}
+	wg.Wait()
+	return res
+}
+
+// bulkIDs validates the ids argument of a bulk tool.
+func bulkIDs(args any, limit int) ([]string, error) {
+	ids := getList(args, "ids")
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("ids is required")
+	}
+	if len(ids) > limit {
+		return nil, fmt.Errorf("at most %d ids per call, got %d", limit, len(ids))
+	}
+	for _, id := range ids {
+		if !imageIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid id %q", id)
+		}
+	}
+	return ids, nil
+}
+
+func registerBulkTools(s *server.MCPServer) {
+	// Several teams at once
+	s.AddTool(
+		mcp.NewTool("get_teams",
+			mcp.WithDescription(fmt.Sprintf("Get detailed information for up to %d teams in one call, fetched concurrently. Returns results keyed by team ID, with per-ID errors for any that failed.", maxBulkTeams)),
+			mcp.WithArray("ids", mcp.Required(), mcp.WithStringItems(), mcp.Description("Team IDs from search results (e.g. [\"13183\", \"13184\"])")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ids, err := bulkIDs(req.Params.Arguments, maxBulkTeams)
+			if err != nil {
+				return toolErrorResult(codeInvalidArgument, err.Error()), nil
+			}
+			q := queryOf(req.Params.Arguments)
+			res := fetchBulk(ctx, req, ids, func(ctx context.Context, id string) (interface{}, error) {
+				return source.Team(ctx, id, q)
+			})
+			if len(res.Results) == 0 {
+				return toolErrorResult(res.failureCode(), fmt.Sprintf("no teams could be fetched: %v", res.Errors)), nil
+			}
+			return jsonResult(fmt.Sprintf("Team info for %d of %d teams", len(res.Results), len(ids)), res), nil
+		},
+	)
+
+	// Several matches at once
+	s.AddTool(
+		mcp.NewTool("get_matches",
+			mcp.WithDescription(fmt.Sprintf("Get details for up to %d matches in one call, fetched concurrently. Returns the matches that could be fetched keyed by match ID, plus per-ID errors for the rest.", maxBulkMatches)),
+			mcp.WithArray("ids", mcp.Required(), mcp.WithStringItems(), mcp.Description("Match IDs from live scores or fixtures")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+			mcp.WithNumber("h2h", mcp.Description("Include head-to-head data: 1=yes, 0=no. Default: 0")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ids, err := bulkIDs(req.Params.Arguments, maxBulkMatches)
+			if err != nil {
+				return toolErrorResult(codeInvalidArgument, err.Error()), nil
+			}
+			h2h := getInt(req.Params.Arguments, "h2h", 0) != 0
+			q := queryOf(req.Params.Arguments)
+			res := fetchBulk(ctx, req, ids, func(ctx context.Context, id string) (interface{}, error) {
+				return source.Match(ctx, id, h2h, q)
+			})
+			if len(res.Results) == 0 {
+				return toolErrorResult(res.failureCode(), fmt.Sprintf("no matches could be fetched: %v", res.Errors)), nil
+			}
+			return jsonResult(fmt.Sprintf("Match info for %d of %d matches", len(res.Results), len(ids)), res), nil
+		},
+	)
+}
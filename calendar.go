@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Calendar Export ---
+//
+// /calendar/team/{id}.ics serves a team's fixtures as an iCalendar feed that
+// Google Calendar, Apple Calendar and Outlook can subscribe to. Events carry
+// the kickoff in UTC and a stable UID per match, so when a calendar refreshes
+// the feed, rescheduled matches move and final scores appear in place rather
+// than being added twice.
+
+const (
+	matchDuration   = 2 * time.Hour
+	calendarRefresh = "PT6H"
+	icsTimeLayout   = "20060102T150405Z"
+)
+
+func registerCalendarRoutes(mux *http.ServeMux, publicURL string, limit func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("GET /calendar/team/{file}", limit(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := strings.CutSuffix(r.PathValue("file"), ".ics")
+		if !ok || !imageIDPattern.MatchString(id) {
+			http.NotFound(w, r)
+			return
+		}
+		args := map[string]interface{}{"language": r.URL.Query().Get("language")}
+		ics, err := teamCalendar(r.Context(), id, args, publicURL)
+		if err != nil {
+			if code := errorCode(err); code == codeNotFound {
+				http.Error(w, fmt.Sprintf("team %s not found", id), http.StatusNotFound)
+			} else {
+				log.Printf("Calendar: team %s: %v", id, err)
+				http.Error(w, "calendar unavailable", errorStatus[code])
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", "team-"+id+".ics"))
+		w.Header().Set("Cache-Control", "public, max-age=900")
+		w.Write([]byte(ics))
+	}))
+}
+
+func registerCalendarTools(s *server.MCPServer, publicURL string) {
+	// Team fixtures as iCalendar
+	s.AddTool(
+		mcp.NewTool("get_team_calendar",
+			mcp.WithDescription(fmt.Sprintf("Get a team's fixtures as iCalendar (ICS) text with kickoff times in UTC. Calendars can subscribe to the same feed at %s/calendar/team/{id}.ics", publicURL)),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Team ID from search results (e.g. 13183 for Ajax)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "id", "")
+			if !imageIDPattern.MatchString(id) {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("invalid team id %q", id)), nil
+			}
+			ics, err := teamCalendar(ctx, id, req.Params.Arguments, publicURL)
+			if err != nil {
+				return errorResult(err), nil
+			}
+			return mcp.NewToolResultText(ics), nil
+		},
+	)
+}
+
+// teamCalendar fetches the team and renders its fixtures that have a known
+// kickoff time, in kickoff order.
+func teamCalendar(ctx context.Context, id string, args any, publicURL string) (string, error) {
+	data, err := source.Team(ctx, id, queryOf(args))
+	if err != nil {
+		return "", err
+	}
+	name := entityName(data, "team")
+	if name == "" {
+		name = "Team " + id
+	}
+
+	type fixture struct {
+		match   feedMatch
+		kickoff time.Time
+	}
+	var fixtures []fixture
+	seen := map[string]bool{}
+	for _, fm := range extractMatches(data) {
+		if !fm.involvesTeam(id, name) || seen[fm.ID] {
+			continue
+		}
+		kickoff, ok := fm.kickoff()
+		if !ok {
+			continue
+		}
+		seen[fm.ID] = true
+		fixtures = append(fixtures, fixture{fm, kickoff})
+	}
+	sort.Slice(fixtures, func(i, j int) bool { return fixtures[i].kickoff.Before(fixtures[j].kickoff) })
+
+	now := time.Now().UTC().Format(icsTimeLayout)
+	var b strings.Builder
+	line := func(s string) { b.WriteString(foldICS(s)) }
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//" + serverName + "//" + serverVersion + "//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICS(name+" fixtures"))
+	line("NAME:" + escapeICS(name+" fixtures"))
+	line("X-WR-TIMEZONE:UTC")
+	line("REFRESH-INTERVAL;VALUE=DURATION:" + calendarRefresh)
+	line("X-PUBLISHED-TTL:" + calendarRefresh)
+	line("SOURCE;VALUE=URI:" + publicURL + "/calendar/team/" + id + ".ics")
+	for _, f := range fixtures {
+		fm := f.match
+		summary := fm.HomeName + " vs " + fm.AwayName
+		if fm.finished() && fm.HasScore {
+			summary = fmt.Sprintf("%s %d-%d %s", fm.HomeName, fm.HomeGoals, fm.AwayGoals, fm.AwayName)
+		}
+		var details []string
+		if fm.LeagueName != "" {
+			details = append(details, fm.LeagueName)
+		}
+		if fm.Round != "" {
+			details = append(details, "Round "+fm.Round)
+		}
+		if fm.Status != "" {
+			details = append(details, "Status: "+fm.Status)
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:match-" + fm.ID + "@" + serverName)
+		line("DTSTAMP:" + now)
+		line("DTSTART:" + f.kickoff.Format(icsTimeLayout))
+		line("DTEND:" + f.kickoff.Add(matchDuration).Format(icsTimeLayout))
+		line("SUMMARY:" + escapeICS(summary))
+		if len(details) > 0 {
+			line("DESCRIPTION:" + escapeICS(strings.Join(details, "\n")))
+		}
+		if venue := matchVenue(fm, data, id); venue != "" {
+			line("LOCATION:" + escapeICS(venue))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String(), nil
+}
+
+// matchVenue returns the match's own venue, or the team's ground for its home
+// matches.
+func matchVenue(fm feedMatch, team interface{}, id string) string {
+	if v, ok := findKey(fm.Raw, "venue", "stadium"); ok {
+		if m, ok := v.(map[string]interface{}); ok {
+			return lookupStr(m, "name")
+		}
+		return scalarString(v)
+	}
+	if fm.HomeID != id {
+		return ""
+	}
+	if v, ok := findKey(team, "venue", "stadium"); ok {
+		if m, ok := v.(map[string]interface{}); ok {
+			return lookupStr(m, "name")
+		}
+		return scalarString(v)
+	}
+	return ""
+}
+
+// escapeICS escapes a TEXT value (RFC 5545 section 3.3.11).
+func escapeICS(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldICS terminates a content line with CRLF, folding it so no line is
+// longer than 75 octets without splitting a UTF-8 sequence.
+func foldICS(s string) string {
+	var b strings.Builder
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74 // the leading space counts
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- CLI ---
+//
+// The binary runs as a hosted server by default. The other commands reuse
+// the same configuration to serve MCP to a local client over stdio or to
+// diagnose a deployment.
+
+const usage = `Usage: livescore-mcp [command] [-config file]
+
+Commands:
+  serve           Run the HTTP server with the SSE transport (default)
+  stdio           Serve MCP over stdin/stdout, for use as a local subprocess
+  check-upstream  Probe the upstream API; exits non-zero if it is unreachable
+  list-tools      Print the tools this server provides
+  build-index     Regenerate the offline index of leagues and teams (-o file)
+  version         Print the version, commit and build date
+
+Flags:
+`
+
+func main() {
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", "path to a YAML config file")
+	output := fs.String("o", "data/index.json", "file build-index writes to")
+	fs.Parse(args)
+
+	switch cmd {
+	case "version":
+		fmt.Println(currentBuild())
+		return
+	case "help":
+		fs.SetOutput(os.Stdout)
+		fs.Usage()
+		return
+	case "serve", "stdio", "check-upstream", "list-tools", "build-index":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", cmd)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Config: %v", err)
+	}
+	if cfg.UpstreamURL != "" {
+		baseURL = strings.TrimRight(cfg.UpstreamURL, "/")
+	}
+	setupFailover(cfg.UpstreamFallbackURL)
+	setupMarketValues(cfg.MarketValueURL)
+	setupWeather(cfg.WeatherURL, cfg.WeatherAPIKey)
+	if err := setUpstreamMode(cfg.Mode, cfg.RecordDir); err != nil {
+		log.Fatalf("Config: %v", err)
+	}
+
+	switch cmd {
+	case "serve":
+		serve(cfg, *configPath)
+	case "stdio":
+		serveStdio(cfg, *configPath)
+	case "check-upstream":
+		os.Exit(checkUpstream())
+	case "list-tools":
+		listTools(cfg)
+	case "build-index":
+		os.Exit(writeIndex(*output))
+	}
+}
+
+// serveStdio runs the server as a local MCP subprocess. The log package
+// writes to stderr, so logs stay out of the protocol stream.
+func serveStdio(cfg *serverConfig, configPath string) {
+	a := newApp(cfg, cfg.PublicURL)
+	a.start()
+	go watchConfig(configPath, a.reload)
+	if err := server.ServeStdio(a.mcp); err != nil {
+		log.Fatalf("Stdio: %v", err)
+	}
+}
+
+func checkUpstream() int {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	p := probeUpstream(ctx)
+	if !p.OK {
+		fmt.Printf("upstream %s unreachable after %d ms: %s\n", baseURL, p.LatencyMs, p.Error)
+		return 1
+	}
+	fmt.Printf("upstream %s ok in %d ms\n", baseURL, p.LatencyMs)
+	return 0
+}
+
+func listTools(cfg *serverConfig) {
+	tools := newApp(cfg, cfg.PublicURL).mcp.ListTools()
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		desc, _, _ := strings.Cut(tools[name].Tool.Description, "\n")
+		fmt.Fprintf(tw, "%s\t%s\n", name, desc)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Competitions Catalog ---
+//
+// Upstream has no endpoint listing every competition, so the catalog starts
+// from the offline index and adds what it finds in the day feeds around
+// today, refreshed in the background. Competitions seen once are kept, so
+// the catalog only grows while the server runs.
+
+const (
+	catalogRefresh   = 6 * time.Hour
+	catalogPastDays  = 7
+	catalogAheadDays = 14
+)
+
+type competition struct {
+	Key     string `json:"key"`
+	Name    string `json:"name"`
+	Country string `json:"country,omitempty"`
+	Womens  bool   `json:"womens,omitempty"` // women's competition, see isWomensLeague
+	Youth   string `json:"youth,omitempty"`  // U19, U21, reserve...; see youthLevel
+}
+
+// classify sets the labels derived from the key and name.
+func (c *competition) classify() {
+	c.Womens = isWomensLeague(c.Key, c.Name)
+	c.Youth = youthLevel(c.Key, c.Name)
+}
+
+type competitionCatalog struct {
+	mu       sync.RWMutex
+	entries  map[string]competition // lowercased key -> competition
+	updated  time.Time
+	interval atomic.Int64 // refresh interval, a time.Duration
+}
+
+func newCompetitionCatalog(interval time.Duration) *competitionCatalog {
+	cc := &competitionCatalog{entries: make(map[string]competition)}
+	for _, c := range builtinIndex.Competitions {
+		c.classify()
+		cc.entries[strings.ToLower(c.Key)] = c
+	}
+	cc.setInterval(interval)
+	return cc
+}
+
+func (cc *competitionCatalog) setInterval(d time.Duration) { cc.interval.Store(int64(d)) }
+
+func (cc *competitionCatalog) run() {
+	for {
+		cc.refresh(time.Now().UTC())
+		time.Sleep(time.Duration(cc.interval.Load()))
+	}
+}
+
+func (cc *competitionCatalog) refresh(now time.Time) {
+	found := 0
+	for d := -catalogPastDays; d <= catalogAheadDays; d++ {
+		day := now.AddDate(0, 0, d).Format("02/01/2006")
+		data, err := source.Fixtures(context.Background(), day, 0, queryOf(nil))
+		if err != nil {
+			log.Printf("Competitions: %s: %v", day, err)
+			continue
+		}
+		found += cc.add(extractMatches(data))
+	}
+	cc.mu.Lock()
+	cc.updated = now
+	total := len(cc.entries)
+	cc.mu.Unlock()
+	log.Printf("Competitions: catalog refreshed, %d new, %d total", found, total)
+}
+
+// add records the competitions of matches and returns how many were new.
+func (cc *competitionCatalog) add(matches []feedMatch) int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	added := 0
+	for _, m := range matches {
+		if m.LeagueKey == "" {
+			continue
+		}
+		id := strings.ToLower(m.LeagueKey)
+		c, seen := cc.entries[id]
+		if !seen {
+			added++
+			c.Key = m.LeagueKey
+		}
+		if m.LeagueName != "" {
+			c.Name = m.LeagueName
+		}
+		if m.Country != "" {
+			c.Country = m.Country
+		}
+		c.classify()
+		cc.entries[id] = c
+	}
+	return added
+}
+
+// lookup finds a league key regardless of case.
+func (cc *competitionCatalog) lookup(key string) (competition, bool) {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	c, ok := cc.entries[strings.ToLower(key)]
+	return c, ok
+}
+
+// canonicalKey returns key in the catalog's spelling, or key itself when the
+// catalog does not know it. Unknown keys are still tried upstream: the
+// catalog only has the competitions played around today.
+func (cc *competitionCatalog) canonicalKey(key string) string {
+	if c, ok := cc.lookup(key); ok {
+		return c.Key
+	}
+	return key
+}
+
+// suggest returns up to n league keys resembling key: keys containing it or
+// whose name is it first ("Eredivisie" -> NetherlandsEredivisie), then the
+// closest by edit distance, ignoring any too different to be a typo.
+func (cc *competitionCatalog) suggest(key string, n int) []string {
+	query := strings.ToLower(strings.Join(strings.Fields(key), ""))
+	if query == "" {
+		return nil
+	}
+	type candidate struct {
+		key  string
+		dist int
+	}
+	var found []candidate
+	cc.mu.RLock()
+	for id, c := range cc.entries {
+		name := strings.ToLower(strings.Join(strings.Fields(c.Name), ""))
+		switch {
+		case strings.Contains(id, query) || name == query:
+			found = append(found, candidate{c.Key, 0})
+		default:
+			if d := editDistance(query, id); d <= max(2, len(id)/3) {
+				found = append(found, candidate{c.Key, d})
+			}
+		}
+	}
+	cc.mu.RUnlock()
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].dist != found[j].dist {
+			return found[i].dist < found[j].dist
+		}
+		return found[i].key < found[j].key
+	})
+	var out []string
+	for _, c := range found[:min(n, len(found))] {
+		out = append(out, c.key)
+	}
+	return out
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// list returns the catalog sorted by country, then name.
+func (cc *competitionCatalog) list() ([]competition, time.Time) {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	out := make([]competition, 0, len(cc.entries))
+	for _, c := range cc.entries {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Country != out[j].Country {
+			return out[i].Country < out[j].Country
+		}
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out, cc.updated
+}
+
+func registerCompetitionResources(s *server.MCPServer, cc *competitionCatalog) {
+	s.AddResource(
+		mcp.NewResource("livescore://competitions", "Competitions catalog",
+			mcp.WithResourceDescription("Machine-readable list of known league keys with display names and countries, women's, youth and reserve competitions labelled, for use as league_key arguments. Safe to cache; refreshed every 6 hours"),
+			mcp.WithMIMEType("application/json"),
+		),
+		func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			entries, updated := cc.list()
+			catalog := map[string]interface{}{
+				"count":        len(entries),
+				"competitions": entries,
+			}
+			if !updated.IsZero() {
+				catalog["updated"] = updated.Format(time.RFC3339)
+			}
+			data, err := json.MarshalIndent(catalog, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "application/json", Text: string(data)},
+			}, nil
+		},
+	)
+}
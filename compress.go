@@ -0,0 +1,128 @@
+package main
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// --- Compression ---
+//
+// compress gzips (or deflates) text responses when the client accepts it:
+// the landing page, static text assets and the SSE stream that carries tool
+// results. Each Flush also flushes the compressor so SSE events are not held
+// back in its buffer.
+
+var compressibleTypes = []string{
+	"application/json", "text/", "application/javascript", "application/xml", "image/svg+xml",
+}
+
+var gzipPool = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+
+type flushWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string // negotiated encoding, "" if none
+	enc      flushWriter
+	decided  bool
+}
+
+func acceptedEncoding(r *http.Request) string {
+	accept := r.Header.Get("Accept-Encoding")
+	for _, enc := range []string{"gzip", "deflate"} {
+		for _, part := range strings.Split(accept, ",") {
+			name, q, _ := strings.Cut(strings.TrimSpace(part), ";")
+			if strings.EqualFold(name, enc) && strings.ReplaceAll(q, " ", "") != "q=0" {
+				return enc
+			}
+		}
+	}
+	return ""
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if !cw.decided {
+		cw.decided = true
+		h := cw.Header()
+		ct := h.Get("Content-Type")
+		compressible := false
+		for _, t := range compressibleTypes {
+			if strings.HasPrefix(ct, t) {
+				compressible = true
+				break
+			}
+		}
+		if compressible && h.Get("Content-Encoding") == "" &&
+			status != http.StatusNoContent && status != http.StatusNotModified {
+			h.Del("Content-Length")
+			h.Set("Content-Encoding", cw.encoding)
+			if cw.encoding == "gzip" {
+				gz := gzipPool.Get().(*gzip.Writer)
+				gz.Reset(cw.ResponseWriter)
+				cw.enc = gz
+			} else {
+				cw.enc = zlib.NewWriter(cw.ResponseWriter)
+			}
+		}
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.decided {
+		if cw.Header().Get("Content-Type") == "" {
+			cw.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.enc != nil {
+		cw.enc.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (cw *compressWriter) Unwrap() http.ResponseWriter { return cw.ResponseWriter }
+
+func (cw *compressWriter) close() {
+	if cw.enc == nil {
+		return
+	}
+	cw.enc.Close()
+	if gz, ok := cw.enc.(*gzip.Writer); ok {
+		gz.Reset(io.Discard)
+		gzipPool.Put(gz)
+	}
+}
+
+func compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := acceptedEncoding(r)
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- Configuration ---
+//
+// The -config flag loads server settings from a YAML file. Every setting can
+// also be given as an environment variable, which takes precedence over the
+// file, so deployments configured through the environment keep working.
+// Settings left out of both fall back to the built-in defaults.
+//
+// The file is read again on SIGHUP, or when it changes on disk. Rate limits,
+// API keys, cache TTLs and SSE settings are applied to the running server
+// without dropping sessions, as are the allowed, denied and trusted proxy
+// networks; the port, listeners, public URL, upstream URLs, mode, admin
+// token, TLS, OAuth and session state file need a restart.
+//
+//	port: "8080"
+//	public_url: https://mcp.example.com
+//	upstream_url: https://uitslagen.live/footapi
+//	upstream_fallback_url: https://mirror.example.com/footapi
+//	market_value_url: https://values.example.com/players/{id}.json
+//	weather_api_key: your-openweathermap-key
+//	mode: live
+//	sports:
+//	  basketball: https://uitslagen.live/basketapi
+//	rate_limits:
+//	  anonymous: {per_minute: 30, burst: 10}
+//	  commercial: {per_minute: 1200}
+//	rate_limit_visitor_ttl: 10m
+//	cache:
+//	  live_poll: 30s
+//	  prefetch: 15s
+//	  competitions: 6h
+//	  session_idle: 24h
+//	sse:
+//	  keepalive: 25s
+//	  max_per_ip: 10
+//	  idle_timeout: 30m
+//	session_state_file: /data/sessions.json
+//	api_keys_file: /data/keys.json
+//	api_keys:
+//	  - {name: acme, key: secret, tier: commercial}
+//	admin_token: change-me
+//	trusted_proxies: [127.0.0.1, unix]
+//	allow_cidrs: [10.0.0.0/8]
+//	deny_cidrs: [192.0.2.7]
+//	listen: [":8080", "unix:/run/livescore.sock"]
+//	socket_mode: "0660"
+//	h2c: true
+//	pprof_addr: 127.0.0.1:6060
+//	tls:
+//	  domains: [mcp.example.com]
+//	  cache_dir: certs
+//	  email: ops@example.com
+//	oauth:
+//	  issuer: https://auth.example.com
+//	  required_scope: livescore
+//	  required: true
+
+type tierLimit struct {
+	PerMinute float64 `yaml:"per_minute"`
+	Burst     int     `yaml:"burst"`
+}
+
+type cacheConfig struct {
+	LivePoll     time.Duration `yaml:"live_poll"`    // live feed poll interval
+	Prefetch     time.Duration `yaml:"prefetch"`     // hot feed refresh interval
+	Competitions time.Duration `yaml:"competitions"` // competitions catalog refresh
+	SessionIdle  time.Duration `yaml:"session_idle"` // per-session state expiry
+}
+
+type sseConfig struct {
+	KeepAlive   time.Duration `yaml:"keepalive"`    // heartbeat on idle streams
+	MaxPerIP    int           `yaml:"max_per_ip"`   // concurrent streams per client IP
+	IdleTimeout time.Duration `yaml:"idle_timeout"` // close streams without messages for this long
+}
+
+type tlsConfig struct {
+	Domains  []string `yaml:"domains"`   // enables automatic Let's Encrypt certificates
+	CacheDir string   `yaml:"cache_dir"` // certificate cache, default "certs"
+	Email    string   `yaml:"email"`     // ACME contact address
+}
+
+type oauthConfig struct {
+	Issuer        string `yaml:"issuer"` // enables OAuth
+	Audience      string `yaml:"audience"`
+	JWKSURL       string `yaml:"jwks_url"`
+	RequiredScope string `yaml:"required_scope"`
+	Required      bool   `yaml:"required"` // reject anonymous clients
+}
+
+type serverConfig struct {
+	Port                string               `yaml:"port"`
+	PublicURL           string               `yaml:"public_url"`
+	UpstreamURL         string               `yaml:"upstream_url"`
+	UpstreamFallbackURL string               `yaml:"upstream_fallback_url"` // mirror used while upstream_url is down
+	MarketValueURL      string               `yaml:"market_value_url"`      // player valuations, {id} is the player ID
+	WeatherAPIKey       string               `yaml:"weather_api_key"`       // enables weather at kickoff in get_match
+	WeatherURL          string               `yaml:"weather_url"`           // OpenWeatherMap-compatible API root
+	Mode                string               `yaml:"mode"`                  // live (default), sandbox, record or replay
+	RecordDir           string               `yaml:"record_dir"`
+	RateLimits          map[string]tierLimit `yaml:"rate_limits"`
+	RateLimitCleanup    time.Duration        `yaml:"rate_limit_cleanup_interval"`
+	RateLimitVisitorTTL time.Duration        `yaml:"rate_limit_visitor_ttl"`
+	Cache               cacheConfig          `yaml:"cache"`
+	SSE                 sseConfig            `yaml:"sse"`
+	APIKeysFile         string               `yaml:"api_keys_file"`
+	APIKeys             []apiKey             `yaml:"api_keys"`
+	Sports              map[string]string    `yaml:"sports"`             // optional sport modules: name to upstream root
+	SessionStateFile    string               `yaml:"session_state_file"` // persisted favorites and languages
+	AdminToken          string               `yaml:"admin_token"`        // enables /admin/keys and /debug/pprof
+	PprofAddr           string               `yaml:"pprof_addr"`         // separate unauthenticated pprof listener
+	TLS                 tlsConfig            `yaml:"tls"`
+	OAuth               oauthConfig          `yaml:"oauth"`
+	TrustedProxies      []string             `yaml:"trusted_proxies"` // reverse proxies whose X-Forwarded-For is believed
+	AllowCIDRs          []string             `yaml:"allow_cidrs"`     // networks exempt from rate limits
+	DenyCIDRs           []string             `yaml:"deny_cidrs"`      // networks refused on every endpoint
+	Listen              []string             `yaml:"listen"`          // TCP addresses or "unix:" sockets, instead of port
+	SocketMode          string               `yaml:"socket_mode"`     // octal permissions of unix sockets
+	H2C                 bool                 `yaml:"h2c"`             // accept cleartext HTTP/2
+}
+
+// loadConfig reads path (if set) and applies the environment on top.
+func loadConfig(path string) (*serverConfig, error) {
+	cfg := &serverConfig{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		for tier := range cfg.RateLimits {
+			if _, ok := defaultRateTiers[tier]; !ok {
+				return nil, fmt.Errorf("%s: unknown rate limit tier %q", path, tier)
+			}
+		}
+	}
+	cfg.applyEnv()
+	return cfg, nil
+}
+
+func (c *serverConfig) applyEnv() {
+	envString("PORT", &c.Port)
+	envString("PUBLIC_URL", &c.PublicURL)
+	envString("UPSTREAM_URL", &c.UpstreamURL)
+	envString("UPSTREAM_FALLBACK_URL", &c.UpstreamFallbackURL)
+	envString("MARKET_VALUE_URL", &c.MarketValueURL)
+	envString("WEATHER_API_KEY", &c.WeatherAPIKey)
+	envString("WEATHER_URL", &c.WeatherURL)
+	envString("MODE", &c.Mode)
+	envString("RECORD_DIR", &c.RecordDir)
+	envString("API_KEYS_FILE", &c.APIKeysFile)
+	envSports("SPORTS", &c.Sports)
+	envString("FAVORITES_FILE", &c.SessionStateFile) // older name
+	envString("SESSION_STATE_FILE", &c.SessionStateFile)
+	envString("ADMIN_TOKEN", &c.AdminToken)
+	envString("PPROF_ADDR", &c.PprofAddr)
+	envList("TRUSTED_PROXIES", &c.TrustedProxies)
+	envList("ALLOW_CIDRS", &c.AllowCIDRs)
+	envList("DENY_CIDRS", &c.DenyCIDRs)
+	envList("LISTEN", &c.Listen)
+	envString("SOCKET_MODE", &c.SocketMode)
+	if v := os.Getenv("H2C"); v != "" {
+		c.H2C = v == "true"
+	}
+	envList("TLS_DOMAINS", &c.TLS.Domains)
+	envString("TLS_CACHE_DIR", &c.TLS.CacheDir)
+	envString("TLS_EMAIL", &c.TLS.Email)
+	envString("OAUTH_ISSUER", &c.OAuth.Issuer)
+	envString("OAUTH_AUDIENCE", &c.OAuth.Audience)
+	envString("OAUTH_JWKS_URL", &c.OAuth.JWKSURL)
+	envString("OAUTH_REQUIRED_SCOPE", &c.OAuth.RequiredScope)
+	if v := os.Getenv("OAUTH_REQUIRED"); v != "" {
+		c.OAuth.Required = v == "true"
+	}
+
+	for name := range defaultRateTiers {
+		suffix := strings.ToUpper(name)
+		t := c.RateLimits[name]
+		if v := os.Getenv("RATE_LIMIT_" + suffix); v != "" {
+			if perMin, err := strconv.ParseFloat(v, 64); err == nil && perMin > 0 {
+				t.PerMinute = perMin
+			} else {
+				log.Printf("Config: invalid RATE_LIMIT_%s %q", suffix, v)
+			}
+		}
+		if v := os.Getenv("RATE_BURST_" + suffix); v != "" {
+			if burst, err := strconv.Atoi(v); err == nil && burst > 0 {
+				t.Burst = burst
+			} else {
+				log.Printf("Config: invalid RATE_BURST_%s %q", suffix, v)
+			}
+		}
+		if t != (tierLimit{}) {
+			if c.RateLimits == nil {
+				c.RateLimits = make(map[string]tierLimit)
+			}
+			c.RateLimits[name] = t
+		}
+	}
+
+	envDuration("RATE_LIMIT_CLEANUP_INTERVAL", &c.RateLimitCleanup)
+	envDuration("RATE_LIMIT_VISITOR_TTL", &c.RateLimitVisitorTTL)
+	envDuration("LIVE_POLL_INTERVAL", &c.Cache.LivePoll)
+	envDuration("PREFETCH_INTERVAL", &c.Cache.Prefetch)
+	envDuration("SSE_KEEPALIVE", &c.SSE.KeepAlive)
+	envDuration("SSE_IDLE_TIMEOUT", &c.SSE.IdleTimeout)
+	if v := os.Getenv("SSE_MAX_PER_IP"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			c.SSE.MaxPerIP = n
+		} else {
+			log.Printf("Config: invalid SSE_MAX_PER_IP %q", v)
+		}
+	}
+	envDuration("COMPETITIONS_REFRESH", &c.Cache.Competitions)
+	envDuration("SESSION_IDLE_TTL", &c.Cache.SessionIdle)
+
+	for _, entry := range strings.Split(os.Getenv("API_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		k := apiKey{Key: entry}
+		if name, key, ok := strings.Cut(entry, ":"); ok {
+			k = apiKey{Key: key, Name: name}
+		}
+		c.APIKeys = append(c.APIKeys, k)
+	}
+}
+
+const configWatchInterval = 10 * time.Second
+
+// watchConfig calls apply with the reloaded configuration whenever the
+// process gets SIGHUP or the file at path is modified. A file that fails to
+// load is reported and the current settings are kept.
+func watchConfig(path string, apply func(*serverConfig)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+
+	modTime := func() time.Time {
+		if path == "" {
+			return time.Time{}
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}
+		}
+		return fi.ModTime()
+	}
+	last := modTime()
+	for {
+		select {
+		case <-hup:
+			log.Printf("Config: SIGHUP received, reloading")
+		case <-ticker.C:
+			mt := modTime()
+			if mt.Equal(last) {
+				continue
+			}
+			last = mt
+			log.Printf("Config: %s changed, reloading", path)
+		}
+		cfg, err := loadConfig(path)
+		if err != nil {
+			log.Printf("Config: reload failed, keeping current settings: %v", err)
+			continue
+		}
+		apply(cfg)
+	}
+}
+
+func envString(name string, dst *string) {
+	if v := os.Getenv(name); v != "" {
+		*dst = v
+	}
+}
+
+// envList reads a comma-separated list, skipping empty entries.
+func envList(name string, dst *[]string) {
+	v := os.Getenv(name)
+	if v == "" {
+		return
+	}
+	var list []string
+	for _, entry := range strings.Split(v, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	*dst = list
+}
+
+// envSports reads sport modules as "basketball=https://...,tennis=https://...".
+func envSports(name string, dst *map[string]string) {
+	v := os.Getenv(name)
+	if v == "" {
+		return
+	}
+	sports := make(map[string]string)
+	for _, entry := range strings.Split(v, ",") {
+		sport, root, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || sport == "" {
+			log.Printf("Config: invalid %s entry %q", name, entry)
+			continue
+		}
+		sports[sport] = root
+	}
+	*dst = sports
+}
+
+func envDuration(name string, dst *time.Duration) {
+	if v := os.Getenv(name); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			*dst = d
+		} else {
+			log.Printf("Config: invalid %s %q", name, v)
+		}
+	}
+}
+
+// orDefault returns d, or def when d is not set.
+func orDefault(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
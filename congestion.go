@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Fixture Congestion ---
+//
+// get_fixture_congestion lists a team's matches over the next
+// congestionWindow from its team page, with the rest before each one since
+// the team's previous match, for rotation and availability questions. Rest
+// is counted kickoff to kickoff; a gap under shortRest is flagged. Matches
+// are grouped by competition, so a league run interrupted by cup and
+// European nights shows as such.
+
+const (
+	congestionWindow = 30 * 24 * time.Hour
+	shortRest        = 72 * time.Hour
+)
+
+type congestedMatch struct {
+	MatchID     string   `json:"match_id"`
+	Kickoff     string   `json:"kickoff"`
+	Opponent    string   `json:"opponent"`
+	Home        bool     `json:"home"`
+	Competition string   `json:"competition,omitempty"`
+	RestDays    *float64 `json:"rest_days"` // since the previous match; nil when unknown
+	ShortRest   bool     `json:"short_rest,omitempty"`
+}
+
+type fixtureCongestion struct {
+	TeamID       string           `json:"team_id"`
+	Team         string           `json:"team"`
+	From         string           `json:"from"`
+	Until        string           `json:"until"`
+	Matches      int              `json:"matches"`
+	ShortRests   int              `json:"short_rests"`
+	MinRest      *float64         `json:"min_rest_days"`
+	AverageRest  *float64         `json:"average_rest_days"`
+	Competitions map[string]int   `json:"competitions"`
+	Fixtures     []congestedMatch `json:"fixtures"`
+}
+
+// congestion returns a team's matches kicking off between now and the end
+// of the window, with the rest before each.
+func congestion(data interface{}, teamID string, now time.Time) fixtureCongestion {
+	type dated struct {
+		fm      feedMatch
+		kickoff time.Time
+	}
+	var all []dated
+	seen := map[string]bool{}
+	for _, fm := range extractMatches(data) {
+		kickoff, ok := fm.kickoff()
+		if !ok || (fm.HomeID != teamID && fm.AwayID != teamID) || seen[fm.ID] {
+			continue
+		}
+		seen[fm.ID] = true
+		all = append(all, dated{fm, kickoff})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].kickoff.Before(all[j].kickoff) })
+
+	until := now.Add(congestionWindow)
+	fc := fixtureCongestion{
+		TeamID:       teamID,
+		Team:         entityName(data, "team"),
+		From:         now.Format(time.RFC3339),
+		Until:        until.Format(time.RFC3339),
+		Competitions: map[string]int{},
+		Fixtures:     []congestedMatch{},
+	}
+	var rests []float64
+	for i, d := range all {
+		if d.kickoff.Before(now) || d.kickoff.After(until) || d.fm.finished() {
+			continue
+		}
+		cm := congestedMatch{MatchID: d.fm.ID, Kickoff: d.kickoff.Format(time.RFC3339), Home: d.fm.HomeID == teamID, Opponent: d.fm.AwayName, Competition: d.fm.LeagueName}
+		if !cm.Home {
+			cm.Opponent = d.fm.HomeName
+		}
+		if cm.Competition == "" {
+			cm.Competition = d.fm.LeagueKey
+		}
+		if i > 0 {
+			gap := d.kickoff.Sub(all[i-1].kickoff)
+			days := math.Round(gap.Hours()/24*10) / 10
+			cm.RestDays, cm.ShortRest = &days, gap < shortRest
+			rests = append(rests, days)
+			if cm.ShortRest {
+				fc.ShortRests++
+			}
+		}
+		if cm.Competition != "" {
+			fc.Competitions[cm.Competition]++
+		}
+		fc.Fixtures = append(fc.Fixtures, cm)
+	}
+	fc.Matches = len(fc.Fixtures)
+	if len(rests) > 0 {
+		least, sum := slices.Min(rests), 0.0
+		for _, r := range rests {
+			sum += r
+		}
+		average := math.Round(sum/float64(len(rests))*10) / 10
+		fc.MinRest, fc.AverageRest = &least, &average
+	}
+	return fc
+}
+
+func registerCongestionTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_fixture_congestion",
+			mcp.WithDescription("Get a team's fixture congestion over the next 30 days: its matches with the rest days before each, short turnarounds (under 3 days), minimum and average rest, and how many matches fall in each competition, for rotation and availability questions. All timestamps are GMT/UTC."),
+			mcp.WithString("team_id", mcp.Required(), mcp.Description("Team ID from search results (e.g. 13183 for Ajax)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "team_id", "")
+			if id == "" {
+				return toolErrorResult(codeInvalidArgument, "team_id is required"), nil
+			}
+			data, err := source.Team(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			fc := congestion(data, id, time.Now().UTC())
+			return jsonResult(fmt.Sprintf("Fixture congestion of team %s: %d matches in the next 30 days, %d on short rest", id, fc.Matches, fc.ShortRests), fc), nil
+		},
+	)
+}
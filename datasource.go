@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// --- Data Source ---
+//
+// Tools, resources and prompts get their football data through a DataSource
+// instead of building upstream URLs themselves, so another provider can be
+// plugged in, and tools can be run against a fake. Results are decoded JSON
+// in the footapi's shapes (see sandbox/ for examples), which the feed
+// helpers and tools expect; a provider with another format converts to them.
+//
+// Errors follow the upstream helpers: an *upstreamError with status 404
+// means not found, see errorCode.
+
+type DataSource interface {
+	// LiveScores is the feed of matches in play, grouped by league.
+	LiveScores(ctx context.Context, q sourceQuery) (interface{}, error)
+	// Fixtures is every match on date (DD/MM/YYYY), grouped by league, with
+	// times shifted by tzOffset minutes.
+	Fixtures(ctx context.Context, date string, tzOffset int, q sourceQuery) (interface{}, error)
+	// Competition is a competition's rounds and table; id is a league key,
+	// optionally with a variant suffix such as "_small".
+	Competition(ctx context.Context, id string, q sourceQuery) (interface{}, error)
+	Team(ctx context.Context, id string, q sourceQuery) (interface{}, error)
+	Player(ctx context.Context, id string, q sourceQuery) (interface{}, error)
+	// Match is a match's details, with head-to-head history if h2h is set.
+	Match(ctx context.Context, id string, h2h bool, q sourceQuery) (interface{}, error)
+	Search(ctx context.Context, query, country string, q sourceQuery) (interface{}, error)
+}
+
+// sourceQuery holds the options every DataSource call takes.
+type sourceQuery struct {
+	Language string
+	Version  int
+	Season   string // Competition only; empty for the current season
+}
+
+// queryOf reads the language and version arguments of a tool call; nil args
+// give the defaults.
+func queryOf(args any) sourceQuery {
+	return sourceQuery{
+		Language: getStr(args, "language", defaultLang),
+		Version:  getInt(args, "version", defaultVersion),
+	}
+}
+
+// leagueFeed is the Competition id of a league's compact feed, which the
+// fixture and standings tools use.
+func leagueFeed(key string) string { return key + "_small" }
+
+// source is the DataSource every tool uses, like upstreamClient.
+var source DataSource = footAPI{}
+
+// footAPI is the default DataSource, the upstream footapi at base, or at
+// baseURL if base is empty.
+type footAPI struct {
+	base string
+}
+
+func (f footAPI) root() string {
+	if f.base == "" {
+		return baseURL
+	}
+	return f.base
+}
+
+func (f footAPI) url(path string, q sourceQuery, extra ...string) string {
+	return buildURLAt(f.root(), path, map[string]interface{}{"language": q.Language, "version": float64(q.Version)}, extra...)
+}
+
+func (f footAPI) LiveScores(ctx context.Context, q sourceQuery) (interface{}, error) {
+	return fetchJSON(ctx, f.url("fixtures/feed_livenow.json", q))
+}
+
+func (f footAPI) Fixtures(ctx context.Context, date string, tzOffset int, q sourceQuery) (interface{}, error) {
+	return fetchJSON(ctx, f.url("fixtures/feed_matches_aggregated.json", q, "date", date, "tzoffset", strconv.Itoa(tzOffset)))
+}
+
+func (f footAPI) Competition(ctx context.Context, id string, q sourceQuery) (interface{}, error) {
+	var extra []string
+	if q.Season != "" {
+		extra = []string{"season", q.Season}
+	}
+	return fetchJSON(ctx, f.url(fmt.Sprintf("fixtures_v2/%s.json", url.PathEscape(id)), q, extra...))
+}
+
+func (f footAPI) Team(ctx context.Context, id string, q sourceQuery) (interface{}, error) {
+	return fetchJSON(ctx, f.url(fmt.Sprintf("team_gs/%s.json", url.PathEscape(id)), q))
+}
+
+func (f footAPI) Player(ctx context.Context, id string, q sourceQuery) (interface{}, error) {
+	return fetchJSON(ctx, f.url(fmt.Sprintf("players/%s.json", url.PathEscape(id)), q))
+}
+
+func (f footAPI) Match(ctx context.Context, id string, h2h bool, q sourceQuery) (interface{}, error) {
+	flag := "0"
+	if h2h {
+		flag = "1"
+	}
+	return fetchJSON(ctx, f.url(fmt.Sprintf("matches/%s.json", url.PathEscape(id)), q, "h2h", flag))
+}
+
+func (f footAPI) Search(ctx context.Context, query, country string, q sourceQuery) (interface{}, error) {
+	extra := []string{"q", query}
+	if country != "" {
+		extra = append(extra, "country", country)
+	}
+	return fetchJSON(ctx, f.url("search_v3", q, extra...))
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Defensive Stats ---
+//
+// get_defensive_stats ranks a league's teams by clean sheets, with goals
+// conceded per match, counted from the finished results in the league feed.
+// Saves are added where the feed carries match stats. When upstream sends
+// goalkeeper rankings with the league (clean sheets or saves per keeper),
+// they are passed through as they are.
+
+type defenceRow struct {
+	TeamID            string  `json:"team_id,omitempty"`
+	Team              string  `json:"team"`
+	Matches           int     `json:"matches"`
+	CleanSheets       int     `json:"clean_sheets"`
+	GoalsConceded     int     `json:"goals_conceded"`
+	ConcededPerMatch  float64 `json:"conceded_per_match"`
+	Saves             *int    `json:"saves,omitempty"`
+	MatchesWithSaves  int     `json:"matches_with_saves,omitempty"`
+	CleanSheetPercent float64 `json:"clean_sheet_percentage"`
+}
+
+// sideStat reads a per-side match stat such as {"saves": {"home": 3,
+// "away": 5}} from a match's stats.
+func (fm feedMatch) sideStat(keys ...string) (home, away int, ok bool) {
+	stats, found := lookup(fm.Raw, "stats", "statistics")
+	if !found {
+		return 0, 0, false
+	}
+	m, _ := stats.(map[string]interface{})
+	v, found := lookup(m, keys...)
+	if !found {
+		return 0, 0, false
+	}
+	sides, _ := v.(map[string]interface{})
+	home, okHome := lookupInt(sides, "home")
+	away, okAway := lookupInt(sides, "away")
+	return home, away, okHome && okAway
+}
+
+func defenceTable(matches []feedMatch) []defenceRow {
+	teams := map[string]*defenceRow{}
+	var order []string
+	add := func(id, name string, conceded int, saves *int) {
+		key := id
+		if key == "" {
+			key = name
+		}
+		row := teams[key]
+		if row == nil {
+			row = &defenceRow{TeamID: id, Team: name}
+			teams[key] = row
+			order = append(order, key)
+		}
+		row.Matches++
+		row.GoalsConceded += conceded
+		if conceded == 0 {
+			row.CleanSheets++
+		}
+		if saves != nil {
+			if row.Saves == nil {
+				row.Saves = new(int)
+			}
+			*row.Saves += *saves
+			row.MatchesWithSaves++
+		}
+	}
+	seen := map[string]bool{}
+	for _, fm := range matches {
+		if !fm.finished() || !fm.HasScore || (fm.ID != "" && seen[fm.ID]) {
+			continue
+		}
+		seen[fm.ID] = true
+		var homeSaves, awaySaves *int
+		if h, a, ok := fm.sideStat("saves", "goalkeepersaves"); ok {
+			homeSaves, awaySaves = &h, &a
+		}
+		add(fm.HomeID, fm.HomeName, fm.AwayGoals, homeSaves)
+		add(fm.AwayID, fm.AwayName, fm.HomeGoals, awaySaves)
+	}
+	table := make([]defenceRow, 0, len(order))
+	for _, key := range order {
+		row := teams[key]
+		row.ConcededPerMatch = math.Round(float64(row.GoalsConceded)/float64(row.Matches)*100) / 100
+		row.CleanSheetPercent = math.Round(float64(row.CleanSheets)/float64(row.Matches)*1000) / 10
+		table = append(table, *row)
+	}
+	sort.SliceStable(table, func(i, j int) bool {
+		if table[i].CleanSheets != table[j].CleanSheets {
+			return table[i].CleanSheets > table[j].CleanSheets
+		}
+		return table[i].ConcededPerMatch < table[j].ConcededPerMatch
+	})
+	return table
+}
+
+func registerDefenceTools(s *server.MCPServer, catalog *competitionCatalog) {
+	s.AddTool(
+		mcp.NewTool("get_defensive_stats",
+			mcp.WithDescription("Get a league's defensive stats per team: clean sheets, goals conceded per match and saves where available, ranked by clean sheets, plus upstream goalkeeper rankings when provided"),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			teams := defenceTable(extractMatches(data))
+			keepers, hasKeepers := findKey(data, "goalkeepers", "cleansheets", "keepers")
+			if len(teams) == 0 && !hasKeepers {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no finished matches found for %s", key)), nil
+			}
+			stats := map[string]interface{}{"teams": teams}
+			if hasKeepers {
+				stats["goalkeepers"] = keepers
+			}
+			return jsonResult(fmt.Sprintf("Defensive stats for %s (from the finished matches in the league feed)", key), stats), nil
+		},
+	)
+}
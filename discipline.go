@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Discipline ---
+//
+// get_discipline_table counts the yellow and red cards of a league's season
+// per team and per player, from the events of its finished matches. Compact
+// league feeds mostly come without events, so the match pages are fetched
+// for those, the most recent maxSeasonMatches of them; the title says
+// how many matches the table covers. Finished match pages are kept in
+// seasonMatches for an hour, so the season tools share them and a repeated
+// call does not fetch them again. Teams are ranked by discipline points,
+// one per yellow and three per red card, the usual fair play scoring.
+
+const (
+	maxSeasonMatches = 50
+	maxSeasonPages   = 2000 // finished match pages kept in seasonMatches
+	redCardPoints    = 3
+	playersListed    = 20
+)
+
+type disciplineRow struct {
+	TeamID        string  `json:"team_id,omitempty"`
+	Team          string  `json:"team"`
+	Matches       int     `json:"matches"`
+	Yellow        int     `json:"yellow_cards"`
+	Red           int     `json:"red_cards"`
+	Points        int     `json:"discipline_points"`
+	CardsPerMatch float64 `json:"cards_per_match"`
+}
+
+type playerCards struct {
+	PlayerID string `json:"player_id,omitempty"`
+	Player   string `json:"player"`
+	Team     string `json:"team,omitempty"`
+	Yellow   int    `json:"yellow_cards"`
+	Red      int    `json:"red_cards"`
+}
+
+// eventTeam resolves the side an event names ("home", "1", a team ID or
+// name) to the team's ID and name.
+func (fm feedMatch) eventTeam(e feedEvent) (id, name string) {
+	side := strings.ToLower(e.Team)
+	if side == "" {
+		return "", ""
+	}
+	switch side {
+	case "home", "h", "1", strings.ToLower(fm.HomeID), strings.ToLower(fm.HomeName):
+		return fm.HomeID, fm.HomeName
+	case "away", "a", "2", strings.ToLower(fm.AwayID), strings.ToLower(fm.AwayName):
+		return fm.AwayID, fm.AwayName
+	}
+	return "", e.Team
+}
+
+// disciplineTable tallies the cards of matches; every match counts as
+// played for both teams, with or without cards.
+func disciplineTable(matches []feedMatch) ([]disciplineRow, []playerCards) {
+	teams := map[string]*disciplineRow{}
+	players := map[string]*playerCards{}
+	team := func(id, name string) *disciplineRow {
+		key := id
+		if key == "" {
+			key = name
+		}
+		row := teams[key]
+		if row == nil {
+			row = &disciplineRow{TeamID: id, Team: name}
+			teams[key] = row
+		}
+		return row
+	}
+	for _, fm := range matches {
+		team(fm.HomeID, fm.HomeName).Matches++
+		team(fm.AwayID, fm.AwayName).Matches++
+		for _, e := range fm.events() {
+			red := e.redCard()
+			if !red && !strings.Contains(e.Type, "yellow") {
+				continue
+			}
+			id, name := fm.eventTeam(e)
+			row := team(id, name)
+			if red {
+				row.Red++
+			} else {
+				row.Yellow++
+			}
+			if e.Player == "" {
+				continue
+			}
+			key := e.PlayerID
+			if key == "" {
+				key = e.Player
+			}
+			p := players[key]
+			if p == nil {
+				p = &playerCards{PlayerID: e.PlayerID, Player: e.Player, Team: name}
+				players[key] = p
+			}
+			if red {
+				p.Red++
+			} else {
+				p.Yellow++
+			}
+		}
+	}
+
+	table := make([]disciplineRow, 0, len(teams))
+	for _, row := range teams {
+		if row.Team == "" {
+			continue
+		}
+		row.Points = row.Yellow + redCardPoints*row.Red
+		if row.Matches > 0 {
+			row.CardsPerMatch = math.Round(float64(row.Yellow+row.Red)/float64(row.Matches)*100) / 100
+		}
+		table = append(table, *row)
+	}
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].Points != table[j].Points {
+			return table[i].Points > table[j].Points
+		}
+		return table[i].Team < table[j].Team
+	})
+	booked := make([]playerCards, 0, len(players))
+	for _, p := range players {
+		booked = append(booked, *p)
+	}
+	sort.Slice(booked, func(i, j int) bool {
+		pi, pj := booked[i].Yellow+redCardPoints*booked[i].Red, booked[j].Yellow+redCardPoints*booked[j].Red
+		if pi != pj {
+			return pi > pj
+		}
+		return booked[i].Player < booked[j].Player
+	})
+	return table, booked[:min(playersListed, len(booked))]
+}
+
+var seasonMatches = newMatchPageCache(maxSeasonPages)
+
+// finishedWith returns the finished matches of a league feed, with the
+// match page in place of the latest limit matches that lack what complete
+// looks for (events, attendance). Matches whose page cannot be fetched are
+// left out; finished counts all of them.
+func finishedWith(ctx context.Context, req mcp.CallToolRequest, data interface{}, limit int, complete func(feedMatch) bool) (matches []feedMatch, finished int) {
+	var missing []feedMatch
+	seen := map[string]bool{}
+	for _, fm := range extractMatches(data) {
+		if !fm.finished() || (fm.ID != "" && seen[fm.ID]) {
+			continue
+		}
+		seen[fm.ID] = true
+		finished++
+		if complete(fm) {
+			matches = append(matches, fm)
+		} else if fm.ID != "" {
+			missing = append(missing, fm)
+		}
+	}
+	sort.SliceStable(missing, func(i, j int) bool {
+		ti, _ := missing[i].kickoff()
+		tj, _ := missing[j].kickoff()
+		return ti.After(tj)
+	})
+	missing = missing[:min(max(0, limit-len(matches)), len(missing))]
+	ids := make([]string, len(missing))
+	for i, fm := range missing {
+		ids[i] = fm.ID
+	}
+	res := fetchBulk(ctx, req, ids, seasonMatches.fetch)
+	for _, id := range ids {
+		if detail, ok := primaryMatch(res.Results[id]); ok && complete(detail) {
+			matches = append(matches, detail)
+		}
+	}
+	return matches, finished
+}
+
+func registerDisciplineTools(s *server.MCPServer, catalog *competitionCatalog) {
+	s.AddTool(
+		mcp.NewTool("get_discipline_table",
+			mcp.WithDescription("Get a league's discipline table for the season: yellow and red cards per team, ranked by discipline points (yellow 1, red 3), and the most booked players"),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			matches, finished := finishedWith(ctx, req, data, maxSeasonMatches, func(fm feedMatch) bool {
+				_, ok := lookup(fm.Raw, "events", "incidents", "timeline")
+				return ok
+			})
+			if len(matches) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no finished matches with events found for %s", key)), nil
+			}
+			teams, players := disciplineTable(matches)
+			table := map[string]interface{}{"teams": teams, "players": players}
+			return jsonResult(fmt.Sprintf("Discipline table for %s (cards from %d of %d finished matches)", key, len(matches), finished), table), nil
+		},
+	)
+}
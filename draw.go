@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Draw Simulator ---
+//
+// simulate_draw pairs a knockout round the way UEFA draws do: each seeded
+// team meets an unseeded one, and with country protection two teams of the
+// same country are kept apart. The teams are given in seeding order, or
+// taken from the top of the competition's table with their countries from
+// their team pages; the first half is seeded. It returns a few random draws
+// that satisfy the constraints and, from drawSimulations more, how likely
+// each seeded team is to meet each opponent. Every valid draw is equally
+// likely: pairings are shuffled until they satisfy the constraints. UEFA's
+// ball-by-ball procedure is close to that, but not exactly the same.
+
+const (
+	defaultQualified = 16
+	maxDrawTeams     = 32
+	maxDrawsListed   = 10
+	drawSimulations  = 2000
+	drawAttempts     = 1000
+)
+
+type drawTeam struct {
+	TeamID  string `json:"team_id,omitempty"`
+	Team    string `json:"team"`
+	Country string `json:"country,omitempty"`
+	Seeded  bool   `json:"seeded"`
+}
+
+type drawTie struct {
+	Seeded   string `json:"seeded"`
+	Unseeded string `json:"unseeded"`
+}
+
+// parseDrawTeam reads "Name" or "Name (Country)".
+func parseDrawTeam(s string) drawTeam {
+	name, country, ok := strings.Cut(s, "(")
+	if !ok {
+		return drawTeam{Team: strings.TrimSpace(s)}
+	}
+	return drawTeam{Team: strings.TrimSpace(name), Country: strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(country), ")"))}
+}
+
+// drawOnce returns a random draw, or false when drawAttempts shuffles found
+// none.
+func drawOnce(rng *rand.Rand, seeded, unseeded []drawTeam, protect bool) ([]drawTie, bool) {
+	allowed := func(a, b drawTeam) bool {
+		return !protect || a.Country == "" || !strings.EqualFold(a.Country, b.Country)
+	}
+shuffle:
+	for range drawAttempts {
+		ties := make([]drawTie, len(seeded))
+		for i, u := range rng.Perm(len(unseeded)) {
+			if !allowed(seeded[i], unseeded[u]) {
+				continue shuffle
+			}
+			ties[i] = drawTie{seeded[i].Team, unseeded[u].Team}
+		}
+		return ties, true
+	}
+	return nil, false
+}
+
+// qualifiedTeams takes the top n teams of a competition's table, with their
+// countries from their team pages.
+func qualifiedTeams(ctx context.Context, req mcp.CallToolRequest, data interface{}, n int) []drawTeam {
+	rows := extractStandings(data)
+	rows = rows[:min(n, len(rows))]
+	var ids []string
+	for _, row := range rows {
+		if row.TeamID != "" {
+			ids = append(ids, row.TeamID)
+		}
+	}
+	pages := fetchBulk(ctx, req, ids, fetchTeam)
+	teams := make([]drawTeam, len(rows))
+	for i, row := range rows {
+		teams[i] = drawTeam{TeamID: row.TeamID, Team: row.TeamName}
+		if page, ok := pages.Results[row.TeamID]; ok {
+			if team, ok := findKey(page, "team"); ok {
+				tm, _ := team.(map[string]interface{})
+				teams[i].Country = lookupStr(tm, "country")
+			}
+		}
+	}
+	return teams
+}
+
+func registerDrawTools(s *server.MCPServer, catalog *competitionCatalog) {
+	s.AddTool(
+		mcp.NewTool("simulate_draw",
+			mcp.WithDescription("Simulate a knockout draw (e.g. the Champions League round of 16): seeded teams meet unseeded ones, with country protection keeping teams of the same country apart. Returns random valid draws and each seeded team's chance of meeting each opponent"),
+			mcp.WithString("competition", mcp.Required(), mcp.Description("League key of the competition, e.g. EurocupsUEFAChampionsLeague")),
+			mcp.WithArray("teams", mcp.WithStringItems(), mcp.Description("Qualified teams in seeding order, seeded half first, as \"Name (Country)\". Default: the top of the competition's table")),
+			mcp.WithNumber("qualified", mcp.Description(fmt.Sprintf("Number of teams taken from the table when teams is not given, even, up to %d. Default: %d", maxDrawTeams, defaultQualified))),
+			mcp.WithBoolean("country_protection", mcp.Description("Keep teams of the same country apart. Default: true")),
+			mcp.WithNumber("draws", mcp.Description(fmt.Sprintf("Number of random draws listed, 1 to %d. Default: 1", maxDrawsListed))),
+			mcp.WithNumber("seed", mcp.Description("Random seed, to repeat a draw. Default: random")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args := req.Params.Arguments
+			key := catalog.canonicalKey(getStr(args, "competition", ""))
+			listed := getInt(args, "draws", 1)
+			if listed < 1 || listed > maxDrawsListed {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("draws must be between 1 and %d", maxDrawsListed)), nil
+			}
+			var teams []drawTeam
+			for _, t := range getList(args, "teams") {
+				teams = append(teams, parseDrawTeam(t))
+			}
+			if len(teams) == 0 {
+				n := getInt(args, "qualified", defaultQualified)
+				if n < 2 || n > maxDrawTeams || n%2 != 0 {
+					return toolErrorResult(codeInvalidArgument, fmt.Sprintf("qualified must be an even number between 2 and %d", maxDrawTeams)), nil
+				}
+				data, err := source.Competition(ctx, leagueFeed(key), queryOf(args))
+				if err != nil {
+					return leagueErrorResult(err, key, catalog), nil
+				}
+				teams = qualifiedTeams(ctx, req, data, n)
+				if len(teams) < n {
+					return toolErrorResult(codeNotFound, fmt.Sprintf("the table of %s has %d teams, not %d; give the qualified teams instead", key, len(teams), n)), nil
+				}
+			}
+			if len(teams) < 2 || len(teams) > maxDrawTeams || len(teams)%2 != 0 {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("a draw needs an even number of teams, 2 to %d", maxDrawTeams)), nil
+			}
+			half := len(teams) / 2
+			for i := range teams[:half] {
+				teams[i].Seeded = true
+			}
+			seeded, unseeded := teams[:half], teams[half:]
+			protect := toMap(args)["country_protection"] != false
+			seed := uint64(getInt(args, "seed", int(rand.Int32())))
+			rng := rand.New(rand.NewPCG(seed, seed))
+
+			draws := make([][]drawTie, 0, listed)
+			for range listed {
+				ties, ok := drawOnce(rng, seeded, unseeded, protect)
+				if !ok {
+					return toolErrorResult(codeInvalidArgument, "no draw satisfies country protection for these teams"), nil
+				}
+				draws = append(draws, ties)
+			}
+			met := map[string]map[string]int{}
+			for range drawSimulations {
+				ties, _ := drawOnce(rng, seeded, unseeded, protect)
+				for _, t := range ties {
+					if met[t.Seeded] == nil {
+						met[t.Seeded] = map[string]int{}
+					}
+					met[t.Seeded][t.Unseeded]++
+				}
+			}
+			chances := map[string]map[string]float64{}
+			for team, opponents := range met {
+				chances[team] = map[string]float64{}
+				for opponent, n := range opponents {
+					chances[team][opponent] = math.Round(float64(n)*1000/drawSimulations) / 10
+				}
+			}
+			result := map[string]interface{}{
+				"competition":        key,
+				"teams":              teams,
+				"country_protection": protect,
+				"seed":               seed,
+				"draws":              draws,
+				"opponent_chances":   chances,
+			}
+			return jsonResult(fmt.Sprintf("%d simulated draw(s) of %d teams for %s (chances in percent from %d more)", listed, len(teams), key, drawSimulations), result), nil
+		},
+	)
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"log"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// --- Payload Drift ---
+//
+// The upstream API is undocumented and changes without notice. Every decoded
+// payload is compared with the fields the tools expect for its endpoint, and
+// each field that goes missing or appears for the first time is logged once
+// and counted in the deep health report, so a format change shows up in the
+// logs before users report broken tools.
+//
+// Schemas list the expected keys of the objects at a path: "" is the root,
+// "leagues[]" the elements of the leagues array, "home" a nested object. A
+// key ending in "?" is optional: it is never reported missing, nor as new.
+// A key is missing only when none of the objects at its path has it, since
+// most fields are left out for matches that have not started.
+
+type payloadSchema map[string][]string
+
+var payloadSchemas = map[string]payloadSchema{
+	"feed_livenow": {
+		"":                         {"leagues"},
+		"leagues[]":                {"league_key", "league_name", "country", "round?", "matches"},
+		"leagues[].matches[]":      {"id", "start_time", "status", "home", "away", "yellow_cards?", "red_cards?"},
+		"leagues[].matches[].home": {"id", "name", "goals?"},
+		"leagues[].matches[].away": {"id", "name", "goals?"},
+	},
+	"feed_matches_aggregated": {
+		"":                         {"date?", "leagues"},
+		"leagues[]":                {"league_key", "league_name", "country", "round?", "matches"},
+		"leagues[].matches[]":      {"id", "start_time", "status", "home", "away", "yellow_cards?", "red_cards?"},
+		"leagues[].matches[].home": {"id", "name", "goals?"},
+		"leagues[].matches[].away": {"id", "name", "goals?"},
+	},
+	"fixtures_v2": {
+		"":                   {"league_key", "league_name", "country", "season", "rounds", "standings?", "goalkeepers?"},
+		"rounds[]":           {"round", "matches"},
+		"rounds[].matches[]": {"id", "start_time", "status", "home", "away"},
+		"standings[]":        {"position", "team", "played", "won", "drawn", "lost", "goals_for", "goals_against", "points"},
+		"standings[].team":   {"id", "name"},
+	},
+	"matches": {
+		"":         {"id", "league_key", "league_name", "country", "round", "start_time", "status", "home", "away", "venue", "referee?", "attendance?", "events", "stats?", "lineups?", "h2h?"},
+		"home":     {"id", "name", "goals?"},
+		"away":     {"id", "name", "goals?"},
+		"venue":    {"name", "city", "attendance?", "latitude?", "longitude?"},
+		"events[]": {"minute", "type", "team", "player", "assist?", "detail?"},
+		"lineups":  {"home", "away"},
+	},
+	"players": {
+		"":              {"player", "team", "season", "career", "transfers?"},
+		"player":        {"id", "name", "position", "nationality", "birth_date", "height_cm?", "foot?", "market_value?"},
+		"season":        {"season", "appearances", "goals", "assists", "minutes", "yellow_cards", "red_cards"},
+		"career[]":      {"season", "team", "appearances", "goals"},
+		"career[].team": {"id", "name"},
+	},
+	"team_gs": {
+		"":           {"team", "league", "squad", "fixtures", "form", "injuries", "honours?", "manager?", "managers?"},
+		"team":       {"id", "name", "country", "founded?", "venue"},
+		"league":     {"league_key", "league_name", "position", "points"},
+		"squad[]":    {"id", "name", "position", "number?", "age?", "goals?", "contract_until?", "nationality?", "market_value?", "caps?"},
+		"injuries[]": {"id", "name", "position", "reason", "expected_return?"},
+		"fixtures[]": {"id", "start_time", "status", "home", "away"},
+	},
+	"search_v3": {
+		"":               {"teams", "players", "competitions"},
+		"teams[]":        {"id", "name", "country"},
+		"players[]":      {"id", "name", "team"},
+		"competitions[]": {"league_key", "league_name", "country"},
+	},
+}
+
+type driftDetector struct {
+	checked  atomic.Int64
+	mu       sync.Mutex
+	warnings map[string]bool // "team_gs: missing squad[].age"
+}
+
+// payloadDrift is shared by every upstream call, like hotFeeds.
+var payloadDrift = &driftDetector{warnings: make(map[string]bool)}
+
+// endpointOf returns the schema name of an upstream URL: the directory for
+// per-ID endpoints (team_gs/101.json), the file name otherwise.
+func endpointOf(apiURL string) string {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return ""
+	}
+	rel := upstreamPath(u)
+	if dir := path.Dir(rel); dir != "." && dir != "fixtures" {
+		return dir
+	}
+	return strings.TrimSuffix(path.Base(rel), ".json")
+}
+
+// check compares a decoded payload with its endpoint's schema.
+func (d *driftDetector) check(apiURL string, data interface{}) {
+	endpoint := endpointOf(apiURL)
+	schema, ok := payloadSchemas[endpoint]
+	if !ok {
+		return
+	}
+	d.checked.Add(1)
+	for at, keys := range schema {
+		objects := objectsAt(data, at)
+		if len(objects) == 0 {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, obj := range objects {
+			for k := range obj {
+				seen[k] = true
+			}
+		}
+		known := map[string]bool{}
+		for _, k := range keys {
+			name, optional := strings.CutSuffix(k, "?")
+			known[name] = true
+			if !optional && !seen[name] {
+				d.warn(endpoint, "missing", at, name)
+			}
+		}
+		for k := range seen {
+			if !known[k] {
+				d.warn(endpoint, "new", at, k)
+			}
+		}
+	}
+}
+
+func (d *driftDetector) warn(endpoint, kind, at, key string) {
+	field := key
+	if at != "" {
+		field = at + "." + key
+	}
+	w := endpoint + ": " + kind + " " + field
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.warnings[w] {
+		return
+	}
+	d.warnings[w] = true
+	log.Printf("Drift: %s field %s in %s payload", kind, field, endpoint)
+}
+
+// objectsAt returns the objects at a schema path.
+func objectsAt(data interface{}, at string) []map[string]interface{} {
+	values := []interface{}{data}
+	if at != "" {
+		for _, seg := range strings.Split(at, ".") {
+			name, isList := strings.CutSuffix(seg, "[]")
+			var next []interface{}
+			for _, v := range values {
+				obj, ok := v.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if !isList {
+					if child, ok := obj[name]; ok {
+						next = append(next, child)
+					}
+					continue
+				}
+				if items, ok := obj[name].([]interface{}); ok {
+					next = append(next, items...)
+				}
+			}
+			values = next
+		}
+	}
+	var objects []map[string]interface{}
+	for _, v := range values {
+		if obj, ok := v.(map[string]interface{}); ok {
+			objects = append(objects, obj)
+		}
+	}
+	return objects
+}
+
+// stats reports how many payloads were checked and the drift seen so far.
+func (d *driftDetector) stats() (checked int64, warnings []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for w := range d.warnings {
+		warnings = append(warnings, w)
+	}
+	sort.Strings(warnings)
+	return d.checked.Load(), warnings
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Tool Errors ---
+//
+// Failed tool calls carry a stable code and a suggested remediation in
+// structuredContent, next to the usual error text, so agents can branch on
+// the kind of failure instead of parsing messages:
+//
+//	{"error": {"code": "NOT_FOUND", "message": "...", "remediation": "..."}}
+//
+// Upstream failures are classified by errorResult. Errors a tool raises
+// itself are argument problems unless the tool says otherwise with
+// toolErrorResult.
+//
+// Only failures the caller can act on stay tool results with isError set.
+// Server-side failures (upstream 5xx, timeouts, internal errors) are turned
+// into JSON-RPC errors by structuredErrors, with the code leading the
+// message, so clients treat them as a broken call rather than an answer.
+
+const (
+	codeInvalidArgument  = "INVALID_ARGUMENT"
+	codeInvalidLeagueKey = "INVALID_LEAGUE_KEY"
+	codeNotFound         = "NOT_FOUND"
+	codeSessionRequired  = "SESSION_REQUIRED"
+	codeRateLimited      = "RATE_LIMITED"
+	codeUpstreamTimeout  = "UPSTREAM_TIMEOUT"
+	codeUpstreamError    = "UPSTREAM_ERROR"
+	codeInternal         = "INTERNAL_ERROR"
+)
+
+// serverFaults are the codes reported as JSON-RPC errors.
+var serverFaults = map[string]bool{
+	codeUpstreamTimeout: true,
+	codeUpstreamError:   true,
+	codeInternal:        true,
+}
+
+// errorStatus is the REST API's HTTP status for each code.
+var errorStatus = map[string]int{
+	codeInvalidArgument:  http.StatusBadRequest,
+	codeInvalidLeagueKey: http.StatusBadRequest,
+	codeNotFound:         http.StatusNotFound,
+	codeSessionRequired:  http.StatusBadRequest,
+	codeRateLimited:      http.StatusTooManyRequests,
+	codeUpstreamTimeout:  http.StatusGatewayTimeout,
+	codeUpstreamError:    http.StatusBadGateway,
+	codeInternal:         http.StatusInternalServerError,
+}
+
+var remediations = map[string]string{
+	codeInvalidArgument:  "Check the tool's input schema and correct the arguments.",
+	codeInvalidLeagueKey: "Look up the league key with search or the livescore://competitions resource; keys are CamelCase country + competition, e.g. NetherlandsEredivisie.",
+	codeNotFound:         "Check the ID; resolve names to IDs with search first.",
+	codeSessionRequired:  "Connect over SSE or stdio; this tool keeps per-session state.",
+	codeRateLimited:      "Wait before retrying, or use an API key with a higher tier.",
+	codeUpstreamTimeout:  "The data provider did not answer in time; retry shortly.",
+	codeUpstreamError:    "The data provider failed; retry later.",
+	codeInternal:         "Retry; report the problem if it persists.",
+}
+
+type toolError struct {
+	Code        string   `json:"code"`
+	Message     string   `json:"message"`
+	Remediation string   `json:"remediation,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// upstreamError is an upstream request that failed or answered with a
+// status other than 200.
+type upstreamError struct {
+	Status int // 0 when no response was received
+	Body   string
+	Err    error
+}
+
+func (e *upstreamError) Error() string {
+	if e.Status == 0 {
+		return fmt.Sprintf("request failed: %v", e.Err)
+	}
+	return fmt.Sprintf("API error (status %d): %s", e.Status, e.Body)
+}
+
+func (e *upstreamError) Unwrap() error { return e.Err }
+
+// notFound is an error for something upstream does not have.
+func notFound(format string, args ...interface{}) error {
+	return &toolError{Code: codeNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *toolError) Error() string { return e.Message }
+
+// errorCode classifies an error returned by the upstream helpers.
+func errorCode(err error) string {
+	var te *toolError
+	if errors.As(err, &te) {
+		return te.Code
+	}
+	var ue *upstreamError
+	if !errors.As(err, &ue) {
+		return codeInternal
+	}
+	var ne net.Error
+	switch {
+	case ue.Status == 404:
+		return codeNotFound
+	case ue.Status == 429:
+		return codeRateLimited
+	case ue.Status >= 400 && ue.Status < 500:
+		return codeInvalidArgument
+	case errors.Is(ue.Err, context.DeadlineExceeded) || (errors.As(ue.Err, &ne) && ne.Timeout()):
+		return codeUpstreamTimeout
+	}
+	return codeUpstreamError
+}
+
+// toolErrorResult is an error result with the given code.
+func toolErrorResult(code, message string) *mcp.CallToolResult {
+	return toolError{Code: code, Message: message, Remediation: remediations[code]}.result()
+}
+
+func (te toolError) result() *mcp.CallToolResult {
+	res := mcp.NewToolResultError(te.Message)
+	res.StructuredContent = map[string]interface{}{"error": te}
+	return res
+}
+
+// errorResult reports a failure returned by the upstream helpers.
+func errorResult(err error) *mcp.CallToolResult {
+	return toolErrorResult(errorCode(err), err.Error())
+}
+
+// leagueErrorResult reports a failure to fetch a league's feed; upstream
+// answers unknown league keys with 404. The closest keys in the catalog are
+// suggested instead. While upstream is down, a key the catalog does not know
+// but has close matches for is taken to be a typo.
+func leagueErrorResult(err error, key string, cc *competitionCatalog) *mcp.CallToolResult {
+	code := errorCode(err)
+	suggestions := cc.suggest(key, 3)
+	_, known := cc.lookup(key)
+	if code != codeNotFound && (!serverFaults[code] || known || len(suggestions) == 0) {
+		return errorResult(err)
+	}
+	te := toolError{
+		Code:        codeInvalidLeagueKey,
+		Message:     fmt.Sprintf("unknown league key %q", key),
+		Remediation: remediations[codeInvalidLeagueKey],
+		Suggestions: suggestions,
+	}
+	if len(te.Suggestions) > 0 {
+		te.Message += fmt.Sprintf("; did you mean %s?", strings.Join(te.Suggestions, ", "))
+	}
+	return te.result()
+}
+
+// resultError returns the structured error of an error result, treating
+// results without one as argument errors.
+func resultError(res *mcp.CallToolResult) toolError {
+	if m, ok := res.StructuredContent.(map[string]interface{}); ok {
+		if te, ok := m["error"].(toolError); ok {
+			return te
+		}
+	}
+	var text string
+	for _, c := range res.Content {
+		if tc, ok := mcp.AsTextContent(c); ok {
+			text += tc.Text
+		}
+	}
+	return toolError{Code: codeInvalidArgument, Message: text, Remediation: remediations[codeInvalidArgument]}
+}
+
+// structuredErrors adds the structured error to error results that lack one,
+// and turns server faults into JSON-RPC errors.
+func structuredErrors(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		res, err := next(ctx, req)
+		if err != nil || res == nil || !res.IsError {
+			return res, err
+		}
+		te := resultError(res)
+		if serverFaults[te.Code] {
+			return nil, fmt.Errorf("%s: %s", te.Code, te.Message)
+		}
+		res.StructuredContent = map[string]interface{}{"error": te}
+		return res, nil
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// --- Upstream Failover ---
+//
+// With upstream_fallback_url set (a mirror of the footapi), the primary
+// upstream is probed in the background and the data source switches to the
+// mirror after failoverThreshold failed probes in a row, and back once the
+// primary answers again. While the primary is still considered healthy, a
+// call it fails with a server-side error is retried on the mirror, so the
+// first failures of an outage do not reach users either.
+
+const (
+	failoverCheckInterval = 30 * time.Second
+	failoverProbeTimeout  = 10 * time.Second
+	failoverThreshold     = 2
+)
+
+type failoverSource struct {
+	primary    footAPI
+	fallback   footAPI
+	failures   atomic.Int64 // consecutive failed probes of the primary
+	onFallback atomic.Bool
+	switches   atomic.Int64
+	retried    atomic.Int64 // calls retried on the fallback
+}
+
+// setupFailover puts the mirror behind source when one is configured.
+func setupFailover(fallbackURL string) {
+	if fallbackURL == "" {
+		return
+	}
+	source = &failoverSource{primary: footAPI{}, fallback: footAPI{base: strings.TrimRight(fallbackURL, "/")}}
+	log.Printf("Failover: %s is the fallback upstream", fallbackURL)
+}
+
+func (fs *failoverSource) run() {
+	for {
+		fs.check()
+		time.Sleep(failoverCheckInterval)
+	}
+}
+
+// check probes the primary and switches over after failoverThreshold
+// failures in a row, or back on the first success.
+func (fs *failoverSource) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), failoverProbeTimeout)
+	defer cancel()
+	_, err := requestUpstream(ctx, fs.primary.url("fixtures/feed_livenow.json", queryOf(nil)))
+	if err == nil {
+		fs.failures.Store(0)
+		if fs.onFallback.CompareAndSwap(true, false) {
+			fs.switches.Add(1)
+			log.Printf("Failover: primary upstream is back, switching to %s", fs.primary.root())
+		}
+		return
+	}
+	if fs.failures.Add(1) >= failoverThreshold && fs.onFallback.CompareAndSwap(false, true) {
+		fs.switches.Add(1)
+		log.Printf("Failover: primary upstream failing (%v), switching to %s", err, fs.fallback.root())
+	}
+}
+
+// do runs fetch against the active upstream, retrying server-side failures
+// of the primary on the fallback.
+func (fs *failoverSource) do(fetch func(DataSource) (interface{}, error)) (interface{}, error) {
+	if fs.onFallback.Load() {
+		return fetch(fs.fallback)
+	}
+	data, err := fetch(fs.primary)
+	if err != nil && serverFaults[errorCode(err)] {
+		fs.retried.Add(1)
+		if fbData, fbErr := fetch(fs.fallback); fbErr == nil {
+			return fbData, nil
+		}
+	}
+	return data, err
+}
+
+// status reports the failover state for the deep health report.
+func (fs *failoverSource) status() map[string]interface{} {
+	active := fs.primary.root()
+	if fs.onFallback.Load() {
+		active = fs.fallback.root()
+	}
+	return map[string]interface{}{
+		"active":        active,
+		"fallback":      fs.fallback.root(),
+		"on_fallback":   fs.onFallback.Load(),
+		"failed_probes": fs.failures.Load(),
+		"switches":      fs.switches.Load(),
+		"retried_calls": fs.retried.Load(),
+	}
+}
+
+func (fs *failoverSource) LiveScores(ctx context.Context, q sourceQuery) (interface{}, error) {
+	return fs.do(func(s DataSource) (interface{}, error) { return s.LiveScores(ctx, q) })
+}
+
+func (fs *failoverSource) Fixtures(ctx context.Context, date string, tzOffset int, q sourceQuery) (interface{}, error) {
+	return fs.do(func(s DataSource) (interface{}, error) { return s.Fixtures(ctx, date, tzOffset, q) })
+}
+
+func (fs *failoverSource) Competition(ctx context.Context, id string, q sourceQuery) (interface{}, error) {
+	return fs.do(func(s DataSource) (interface{}, error) { return s.Competition(ctx, id, q) })
+}
+
+func (fs *failoverSource) Team(ctx context.Context, id string, q sourceQuery) (interface{}, error) {
+	return fs.do(func(s DataSource) (interface{}, error) { return s.Team(ctx, id, q) })
+}
+
+func (fs *failoverSource) Player(ctx context.Context, id string, q sourceQuery) (interface{}, error) {
+	return fs.do(func(s DataSource) (interface{}, error) { return s.Player(ctx, id, q) })
+}
+
+func (fs *failoverSource) Match(ctx context.Context, id string, h2h bool, q sourceQuery) (interface{}, error) {
+	return fs.do(func(s DataSource) (interface{}, error) { return s.Match(ctx, id, h2h, q) })
+}
+
+func (fs *failoverSource) Search(ctx context.Context, query, country string, q sourceQuery) (interface{}, error) {
+	return fs.do(func(s DataSource) (interface{}, error) { return s.Search(ctx, query, country, q) })
+}
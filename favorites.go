@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Favorites ---
+
+type favoriteTeam struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+func (ss *sessionStore) addFavorite(owner string, team favoriteTeam) {
+	ss.update(owner, func(st *sessionState) bool {
+		if st.Favorites == nil {
+			st.Favorites = make(map[string]favoriteTeam)
+		}
+		st.Favorites[team.ID] = team
+		return true
+	})
+}
+
+func (ss *sessionStore) removeFavorite(owner, id string) bool {
+	removed := false
+	ss.update(owner, func(st *sessionState) bool {
+		if _, ok := st.Favorites[id]; !ok {
+			return false
+		}
+		delete(st.Favorites, id)
+		removed = true
+		return true
+	})
+	return removed
+}
+
+func (ss *sessionStore) favorites(owner string) []favoriteTeam {
+	var teams []favoriteTeam
+	ss.view(owner, func(st *sessionState) {
+		teams = make([]favoriteTeam, 0, len(st.Favorites))
+		for _, t := range st.Favorites {
+			teams = append(teams, t)
+		}
+	})
+	sort.Slice(teams, func(i, j int) bool { return teams[i].ID < teams[j].ID })
+	return teams
+}
+
+func sessionIDFromContext(ctx context.Context) string {
+	if cs := server.ClientSessionFromContext(ctx); cs != nil {
+		return cs.SessionID()
+	}
+	return ""
+}
+
+func registerFavoriteTools(s *server.MCPServer, ss *sessionStore) {
+	// Add favorite
+	s.AddTool(
+		mcp.NewTool("add_favorite_team",
+			mcp.WithDescription("Add a team to this session's favorites so get_my_live_scores can track it"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Team ID from search results (e.g. 13183 for Ajax)")),
+			mcp.WithString("name", mcp.Description("Team name, used for display and for matching in live feeds")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner := ss.owner(ctx)
+			if owner == "" {
+				return toolErrorResult(codeSessionRequired, "favorites require an MCP session"), nil
+			}
+			id := getStr(req.Params.Arguments, "id", "")
+			if id == "" {
+				return toolErrorResult(codeInvalidArgument, "id is required"), nil
+			}
+			team := favoriteTeam{ID: id, Name: getStr(req.Params.Arguments, "name", "")}
+			ss.addFavorite(owner, team)
+			return mcp.NewToolResultText(fmt.Sprintf("Added team %s to favorites", id)), nil
+		},
+	)
+
+	// Remove favorite
+	s.AddTool(
+		mcp.NewTool("remove_favorite_team",
+			mcp.WithDescription("Remove a team from this session's favorites"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Team ID to remove")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner := ss.owner(ctx)
+			if owner == "" {
+				return toolErrorResult(codeSessionRequired, "favorites require an MCP session"), nil
+			}
+			id := getStr(req.Params.Arguments, "id", "")
+			if !ss.removeFavorite(owner, id) {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("team %s is not a favorite", id)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Removed team %s from favorites", id)), nil
+		},
+	)
+
+	// List favorites
+	s.AddTool(
+		mcp.NewTool("list_favorites",
+			mcp.WithDescription("List the favorite teams stored for this session"),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner := ss.owner(ctx)
+			if owner == "" {
+				return toolErrorResult(codeSessionRequired, "favorites require an MCP session"), nil
+			}
+			return jsonResult("Favorite teams", ss.favorites(owner)), nil
+		},
+	)
+
+	// Live scores for favorites
+	s.AddTool(
+		mcp.NewTool("get_my_live_scores",
+			mcp.WithDescription("Get live matches involving this session's favorite teams. All timestamps are GMT/UTC."),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.). Default: en")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner := ss.owner(ctx)
+			if owner == "" {
+				return toolErrorResult(codeSessionRequired, "favorites require an MCP session"), nil
+			}
+			teams := ss.favorites(owner)
+			if len(teams) == 0 {
+				return mcp.NewToolResultText("No favorite teams yet - add one with add_favorite_team"), nil
+			}
+
+			data, err := source.LiveScores(ctx, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			filtered := filterFeed(data, func(m feedMatch) bool {
+				for _, t := range teams {
+					if m.involvesTeam(t.ID, t.Name) {
+						return true
+					}
+				}
+				return false
+			})
+			return jsonResult("Live scores for favorite teams", filtered), nil
+		},
+	)
+}
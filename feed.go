@@ -0,0 +1,640 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// --- Feed Parsing ---
+//
+// The upstream feeds are deeply nested and not uniform across endpoints, so
+// matches are located by shape rather than by a fixed schema: any object that
+// names both a home and an away side is treated as a match. Keys are compared
+// after normalization (lowercase, no "@", "_" or "-") so both plain and
+// attribute-style feeds are understood.
+
+type feedMatch struct {
+	ID         string
+	HomeID     string
+	HomeName   string
+	AwayID     string
+	AwayName   string
+	HomeGoals  int
+	AwayGoals  int
+	HasScore   bool
+	Status     string
+	Round      string
+	LeagueKey  string
+	LeagueName string
+	Country    string
+	Raw        map[string]interface{}
+}
+
+type leagueContext struct {
+	Key     string
+	Name    string
+	Country string
+	Round   string
+}
+
+var (
+	matchIDKeys    = []string{"id", "matchid", "fixtureid", "staticid", "fixid"}
+	homeKeys       = []string{"home", "hometeam", "localteam", "team1", "homename", "hometeamname", "localteamname"}
+	awayKeys       = []string{"away", "awayteam", "visitorteam", "team2", "awayname", "awayteamname", "visitorteamname"}
+	homeIDKeys     = []string{"homeid", "hometeamid", "localteamid", "team1id"}
+	awayIDKeys     = []string{"awayid", "awayteamid", "visitorteamid", "team2id"}
+	homeScoreKeys  = []string{"homescore", "homegoals", "localteamscore", "localteamgoals", "score1", "hg"}
+	awayScoreKeys  = []string{"awayscore", "awaygoals", "visitorteamscore", "visitorteamgoals", "score2", "ag"}
+	scoreKeys      = []string{"score", "result", "ftscore", "ft"}
+	statusKeys     = []string{"status", "state", "matchstatus", "statusshort"}
+	roundKeys      = []string{"round", "matchweek", "gameweek", "week", "roundnumber", "matchday"}
+	leagueKeyKeys  = []string{"leaguekey", "competitionkey", "fileid", "league"}
+	leagueNameKeys = []string{"leaguename", "competitionname", "league", "competition"}
+	countryKeys    = []string{"country", "countryname", "area", "areaname"}
+	teamIDKeys     = []string{"id", "teamid"}
+	teamNameKeys   = []string{"name", "teamname", "shortname"}
+	teamGoalKeys   = []string{"goals", "score"}
+)
+
+func normKey(k string) string {
+	k = strings.ToLower(k)
+	return strings.NewReplacer("@", "", "_", "", "-", "").Replace(k)
+}
+
+// lookup returns the first value in m whose normalized key is one of keys.
+func lookup(m map[string]interface{}, keys ...string) (interface{}, bool) {
+	for _, want := range keys {
+		for k, v := range m {
+			if normKey(k) == want && v != nil {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func scalarString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	return ""
+}
+
+func lookupStr(m map[string]interface{}, keys ...string) string {
+	for _, want := range keys {
+		for k, v := range m {
+			if normKey(k) == want {
+				if s := scalarString(v); s != "" {
+					return s
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func lookupInt(m map[string]interface{}, keys ...string) (int, bool) {
+	s := lookupStr(m, keys...)
+	if s == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// teamRef resolves a side given either as a nested team object or a plain name.
+func teamRef(m map[string]interface{}, keys, idKeys []string) (id, name string, goals int, hasGoals bool) {
+	if v, ok := lookup(m, keys...); ok {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			id = lookupStr(t, teamIDKeys...)
+			name = lookupStr(t, teamNameKeys...)
+			goals, hasGoals = lookupInt(t, teamGoalKeys...)
+		default:
+			name = scalarString(t)
+		}
+	}
+	if id == "" {
+		id = lookupStr(m, idKeys...)
+	}
+	return id, name, goals, hasGoals
+}
+
+// parseScore understands "2-1", "2 - 1", "[2-1]" and "2:1".
+func parseScore(s string) (int, int, bool) {
+	s = strings.Trim(s, "[]() ")
+	for _, sep := range []string{"-", ":"} {
+		if parts := strings.SplitN(s, sep, 2); len(parts) == 2 {
+			h, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+			a, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+			if err1 == nil && err2 == nil {
+				return h, a, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// asMatch reports whether m looks like a match and parses it if so.
+func asMatch(m map[string]interface{}, ctx leagueContext) (feedMatch, bool) {
+	fm := feedMatch{Raw: m}
+	var hg, ag bool
+	fm.HomeID, fm.HomeName, fm.HomeGoals, hg = teamRef(m, homeKeys, homeIDKeys)
+	fm.AwayID, fm.AwayName, fm.AwayGoals, ag = teamRef(m, awayKeys, awayIDKeys)
+	if (fm.HomeID == "" && fm.HomeName == "") || (fm.AwayID == "" && fm.AwayName == "") {
+		return fm, false
+	}
+
+	fm.ID = lookupStr(m, matchIDKeys...)
+	fm.Status = lookupStr(m, statusKeys...)
+
+	fm.HasScore = hg && ag
+	if h, ok := lookupInt(m, homeScoreKeys...); ok {
+		if a, ok := lookupInt(m, awayScoreKeys...); ok {
+			fm.HomeGoals, fm.AwayGoals, fm.HasScore = h, a, true
+		}
+	}
+	if !fm.HasScore {
+		fm.HomeGoals, fm.AwayGoals, fm.HasScore = parseScore(lookupStr(m, scoreKeys...))
+	}
+
+	fm.Round = lookupStr(m, roundKeys...)
+	if fm.Round == "" {
+		fm.Round = ctx.Round
+	}
+
+	fm.LeagueKey = lookupStr(m, "leaguekey", "competitionkey")
+	if fm.LeagueKey == "" {
+		fm.LeagueKey = ctx.Key
+	}
+	fm.LeagueName = lookupStr(m, "leaguename", "competitionname")
+	if fm.LeagueName == "" {
+		fm.LeagueName = ctx.Name
+	}
+	fm.Country = lookupStr(m, countryKeys...)
+	if fm.Country == "" {
+		fm.Country = ctx.Country
+	}
+	return fm, true
+}
+
+// withLeague derives the league context for the children of a non-match object.
+func withLeague(m map[string]interface{}, ctx leagueContext) leagueContext {
+	if round := lookupStr(m, roundKeys...); round != "" {
+		ctx.Round = round
+	}
+	if country := lookupStr(m, countryKeys...); country != "" {
+		ctx.Country = country
+	}
+	key := lookupStr(m, leagueKeyKeys...)
+	name := lookupStr(m, leagueNameKeys...)
+	if key == "" && name == "" {
+		return ctx
+	}
+	if key != "" {
+		ctx.Key = key
+	}
+	if name != "" {
+		ctx.Name = name
+	}
+	return ctx
+}
+
+// extractMatches returns every match found anywhere in a decoded feed.
+func extractMatches(data interface{}) []feedMatch {
+	var out []feedMatch
+	var walk func(node interface{}, ctx leagueContext)
+	walk = func(node interface{}, ctx leagueContext) {
+		switch t := node.(type) {
+		case map[string]interface{}:
+			if fm, ok := asMatch(t, ctx); ok {
+				out = append(out, fm)
+				return
+			}
+			ctx = withLeague(t, ctx)
+			for _, v := range t {
+				walk(v, ctx)
+			}
+		case []interface{}:
+			for _, v := range t {
+				walk(v, ctx)
+			}
+		}
+	}
+	walk(data, leagueContext{})
+	return out
+}
+
+// filterFeed prunes a decoded feed down to the matches accepted by keep,
+// dropping any grouping (league, country) that is left without matches.
+func filterFeed(data interface{}, keep func(feedMatch) bool) interface{} {
+	out, _, _ := pruneFeed(data, leagueContext{}, keep)
+	return out
+}
+
+func pruneFeed(node interface{}, ctx leagueContext, keep func(feedMatch) bool) (out interface{}, hadMatches, kept bool) {
+	switch t := node.(type) {
+	case map[string]interface{}:
+		if fm, ok := asMatch(t, ctx); ok {
+			return t, true, keep(fm)
+		}
+		ctx = withLeague(t, ctx)
+		pruned := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			child, had, k2 := pruneFeed(v, ctx, keep)
+			hadMatches = hadMatches || had
+			kept = kept || k2
+			if had && !k2 {
+				continue
+			}
+			pruned[k] = child
+		}
+		return pruned, hadMatches, kept
+	case []interface{}:
+		pruned := make([]interface{}, 0, len(t))
+		for _, v := range t {
+			child, had, k2 := pruneFeed(v, ctx, keep)
+			hadMatches = hadMatches || had
+			kept = kept || k2
+			if had && !k2 {
+				continue
+			}
+			pruned = append(pruned, child)
+		}
+		return pruned, hadMatches, kept
+	}
+	return node, false, false
+}
+
+// involvesTeam matches on team ID exactly or on a case-insensitive name fragment.
+func (fm feedMatch) involvesTeam(id, name string) bool {
+	if id != "" && (fm.HomeID == id || fm.AwayID == id) {
+		return true
+	}
+	if name != "" {
+		name = strings.ToLower(name)
+		return strings.Contains(strings.ToLower(fm.HomeName), name) ||
+			strings.Contains(strings.ToLower(fm.AwayName), name)
+	}
+	return false
+}
+
+// inLeague matches a league key exactly, ignoring case and a "_small"
+// variant suffix, or a league name as a whole: "Premier League" matches
+// "premier-league" but neither "Premier" nor "England" does.
+func (fm feedMatch) inLeague(key string) bool {
+	if key == "" {
+		return false
+	}
+	if strings.EqualFold(strings.TrimSuffix(fm.LeagueKey, "_small"), strings.TrimSuffix(key, "_small")) {
+		return true
+	}
+	return fm.LeagueName != "" && leagueNameKey(fm.LeagueName) == leagueNameKey(key)
+}
+
+// leagueNameKey reduces a league name to its letters and digits, lowercased.
+func leagueNameKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+type feedEvent struct {
+	Type     string
+	Minute   string
+	PlayerID string
+	Player   string
+	Team     string
+	VAR      string // the review decision, see varDecision
+}
+
+var finishedStatuses = map[string]bool{
+	"ft": true, "aet": true, "pen": true, "pens": true, "ap": true,
+	"finished": true, "ended": true, "fulltime": true, "afterpenalties": true,
+}
+
+// goal reports whether the event is a goal that stands, own goals and
+// penalties included.
+func (e feedEvent) goal() bool {
+	if !strings.Contains(e.Type, "goal") || e.VAR == varGoalDisallowed {
+		return false
+	}
+	for _, not := range []string{"disallowed", "cancelled", "canceled", "missed", "kick"} {
+		if strings.Contains(e.Type, not) {
+			return false
+		}
+	}
+	return true
+}
+
+// minute parses the event minute, "45+2'" giving 45 and 2 added.
+func (e feedEvent) minute() (minute, added int, ok bool) {
+	base, extra, _ := strings.Cut(strings.Trim(e.Minute, "' "), "+")
+	minute, err := strconv.Atoi(strings.TrimSpace(base))
+	if err != nil {
+		return 0, 0, false
+	}
+	added, _ = strconv.Atoi(strings.Trim(extra, "' "))
+	return minute, added, true
+}
+
+// finished reports whether the match status denotes a completed match.
+func (fm feedMatch) finished() bool {
+	return finishedStatuses[normKey(strings.Trim(fm.Status, ". "))]
+}
+
+// events returns the match events (goals, cards, substitutions) if the feed
+// includes them, either as a list or wrapped in an {"event": [...]} object.
+func (fm feedMatch) events() []feedEvent {
+	v, ok := lookup(fm.Raw, "events", "incidents", "timeline")
+	if !ok {
+		return nil
+	}
+	if m, ok := v.(map[string]interface{}); ok {
+		if inner, ok := lookup(m, "event", "events"); ok {
+			v = inner
+		}
+	}
+	var items []interface{}
+	switch t := v.(type) {
+	case []interface{}:
+		items = t
+	case map[string]interface{}:
+		items = []interface{}{t}
+	}
+
+	var out []feedEvent
+	for _, item := range items {
+		e, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		playerID, player := nameOrString(e, "player", "playername", "name")
+		out = append(out, feedEvent{
+			Type:     strings.ToLower(lookupStr(e, "type", "event", "kind", "eventtype")),
+			Minute:   lookupStr(e, "minute", "min", "elapsed", "time"),
+			PlayerID: playerID,
+			Player:   player,
+			Team:     lookupStr(e, "team", "side", "teamname"),
+			VAR:      varDecision(e),
+		})
+	}
+	return out
+}
+
+// redCardText reports whether an event type or description names a
+// sending-off: "red_card", "Red card", "redcard", "yellowred" or the word
+// "red", but not "scored" or "injured".
+func redCardText(s string) bool {
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		switch w {
+		case "red", "redcard", "yellowred", "yellowredcard":
+			return true
+		}
+	}
+	return false
+}
+
+// redCard reports whether the event is a sending-off, straight or for a
+// second yellow.
+func (e feedEvent) redCard() bool {
+	return redCardText(e.Type)
+}
+
+// cardCounts tallies yellow and red cards from the match events, leaving
+// out those cancelled after a video review.
+func (fm feedMatch) cardCounts() (yellow, red int) {
+	for _, e := range fm.events() {
+		switch {
+		case e.VAR == varRedCardCancelled:
+		case e.redCard():
+			red++
+		case strings.Contains(e.Type, "yellow"):
+			yellow++
+		}
+	}
+	return yellow, red
+}
+
+// primaryMatch returns the shallowest match in a decoded match-detail
+// response, so embedded head-to-head history is not mistaken for it.
+func primaryMatch(data interface{}) (feedMatch, bool) {
+	queue := []interface{}{data}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		switch t := node.(type) {
+		case map[string]interface{}:
+			if fm, ok := asMatch(t, leagueContext{}); ok {
+				return fm, true
+			}
+			for _, v := range t {
+				queue = append(queue, v)
+			}
+		case []interface{}:
+			queue = append(queue, t...)
+		}
+	}
+	return feedMatch{}, false
+}
+
+// inRound matches a round given as a number ("24") against feed values such
+// as "24", "Round 24" or "Matchweek 24".
+func (fm feedMatch) inRound(round string) bool {
+	if strings.EqualFold(fm.Round, round) {
+		return true
+	}
+	fields := strings.FieldsFunc(fm.Round, func(r rune) bool { return r < '0' || r > '9' })
+	return len(fields) == 1 && strings.TrimLeft(fields[0], "0") == strings.TrimLeft(round, "0")
+}
+
+// findKey returns the shallowest value stored under one of keys anywhere in data.
+func findKey(data interface{}, keys ...string) (interface{}, bool) {
+	queue := []interface{}{data}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		switch t := node.(type) {
+		case map[string]interface{}:
+			if v, ok := lookup(t, keys...); ok {
+				return v, true
+			}
+			for _, v := range t {
+				queue = append(queue, v)
+			}
+		case []interface{}:
+			queue = append(queue, t...)
+		}
+	}
+	return nil, false
+}
+
+// standingRow is one line of a league table.
+type standingRow struct {
+	Position     int    `json:"position"`
+	TeamID       string `json:"team_id,omitempty"`
+	TeamName     string `json:"team"`
+	Played       int    `json:"played"`
+	Won          int    `json:"won"`
+	Drawn        int    `json:"drawn"`
+	Lost         int    `json:"lost"`
+	GoalsFor     int    `json:"goals_for"`
+	GoalsAgainst int    `json:"goals_against"`
+	Points       int    `json:"points"`
+}
+
+var (
+	positionKeys     = []string{"position", "pos", "rank", "place"}
+	pointsKeys       = []string{"points", "pts"}
+	playedKeys       = []string{"played", "p", "gp", "matches", "matchesplayed"}
+	wonKeys          = []string{"won", "w", "wins"}
+	drawnKeys        = []string{"drawn", "d", "draws", "draw"}
+	lostKeys         = []string{"lost", "l", "losses"}
+	goalsForKeys     = []string{"goalsfor", "gf", "scored", "for"}
+	goalsAgainstKeys = []string{"goalsagainst", "ga", "conceded", "against"}
+)
+
+func (r standingRow) GoalDifference() int { return r.GoalsFor - r.GoalsAgainst }
+
+// asStanding reports whether m looks like a table row: a position, points
+// and a team given as a nested object or by name.
+func asStanding(m map[string]interface{}) (standingRow, bool) {
+	pos, ok := lookupInt(m, positionKeys...)
+	if !ok {
+		return standingRow{}, false
+	}
+	pts, ok := lookupInt(m, pointsKeys...)
+	if !ok {
+		return standingRow{}, false
+	}
+	row := standingRow{Position: pos, Points: pts}
+	row.TeamID, row.TeamName, _, _ = teamRef(m, []string{"team", "club"}, []string{"teamid", "id"})
+	if row.TeamName == "" {
+		row.TeamName = lookupStr(m, "teamname", "name")
+	}
+	if row.TeamID == "" && row.TeamName == "" {
+		return standingRow{}, false
+	}
+	row.Played, _ = lookupInt(m, playedKeys...)
+	row.Won, _ = lookupInt(m, wonKeys...)
+	row.Drawn, _ = lookupInt(m, drawnKeys...)
+	row.Lost, _ = lookupInt(m, lostKeys...)
+	row.GoalsFor, _ = lookupInt(m, goalsForKeys...)
+	row.GoalsAgainst, _ = lookupInt(m, goalsAgainstKeys...)
+	return row, true
+}
+
+// extractStandings returns the first league table found in a decoded feed,
+// ordered by position.
+func extractStandings(data interface{}) []standingRow {
+	var rows []standingRow
+	var walk func(node interface{}) bool
+	walk = func(node interface{}) bool {
+		switch t := node.(type) {
+		case map[string]interface{}:
+			for _, v := range t {
+				if walk(v) {
+					return true
+				}
+			}
+		case []interface{}:
+			for _, v := range t {
+				if m, ok := v.(map[string]interface{}); ok {
+					if row, ok := asStanding(m); ok {
+						rows = append(rows, row)
+					}
+				}
+			}
+			if len(rows) > 0 {
+				return true
+			}
+			for _, v := range t {
+				if walk(v) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if table, ok := findKey(data, "standings", "table", "tables", "leaguetable"); ok {
+		walk(table)
+	}
+	if len(rows) == 0 {
+		walk(data)
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Position < rows[j].Position })
+	return rows
+}
+
+var (
+	kickoffKeys = []string{"starttime", "kickoff", "kickofftime", "datetime", "startdate", "timestamp", "starttimestamp", "date"}
+	clockKeys   = []string{"time", "kickofftime", "starttime"}
+)
+
+var kickoffLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"20060102150405",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02.01.2006 15:04",
+}
+
+var dateLayouts = []string{"2006-01-02", "02/01/2006", "02.01.2006", "20060102"}
+
+// kickoff returns the scheduled start in UTC. It understands RFC 3339 and
+// similar date-times, Unix timestamps in seconds or milliseconds, and a date
+// with the clock time in a separate field.
+func (fm feedMatch) kickoff() (time.Time, bool) {
+	for _, key := range kickoffKeys {
+		v, ok := lookup(fm.Raw, key)
+		if !ok {
+			continue
+		}
+		if n, ok := v.(float64); ok && n > 1e9 {
+			if n > 1e12 {
+				return time.UnixMilli(int64(n)).UTC(), true
+			}
+			return time.Unix(int64(n), 0).UTC(), true
+		}
+		s := scalarString(v)
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil && n > 1e9 && len(s) != 14 {
+			if n > 1e12 {
+				return time.UnixMilli(n).UTC(), true
+			}
+			return time.Unix(n, 0).UTC(), true
+		}
+		for _, layout := range kickoffLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t.UTC(), true
+			}
+		}
+		for _, layout := range dateLayouts {
+			day, err := time.Parse(layout, s)
+			if err != nil {
+				continue
+			}
+			if clock, err := time.Parse("15:04", lookupStr(fm.Raw, clockKeys...)); err == nil {
+				day = day.Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+			}
+			return day, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Result Feeds ---
+//
+// /feeds/{league_key}.xml (RSS 2.0) and /feeds/{league_key}.atom list a
+// league's recent full-time results for feed readers. A league is seeded
+// from its fixtures the first time it is requested and re-seeded hourly; in
+// between, full-time events from the live poller add results as soon as
+// matches end. A key that fails to seed is answered from the failure for a
+// few minutes, and the route is rate limited per IP like the other public
+// pages.
+
+const (
+	feedMaxResults  = 30
+	feedMaxAge      = 7 * 24 * time.Hour
+	feedSeedRefresh = time.Hour
+	feedTTLMinutes  = 15 // how often readers are asked to poll
+	feedFailedTTL   = 5 * time.Minute
+	maxFailedSeeds  = 1000
+)
+
+type feedResult struct {
+	MatchID string
+	Home    string
+	Away    string
+	Score   string
+	Round   string
+	Time    time.Time // kickoff, or when the poller saw the match end
+}
+
+type leagueResults struct {
+	name    string
+	results map[string]feedResult // by match ID
+	seeded  time.Time
+}
+
+type failedSeed struct {
+	err   error
+	until time.Time
+}
+
+type resultFeeds struct {
+	mu      sync.Mutex
+	leagues map[string]*leagueResults // by lower-case league key
+	failed  map[string]failedSeed     // keys that could not be seeded, by lower-case key
+}
+
+func newResultFeeds() *resultFeeds {
+	return &resultFeeds{leagues: make(map[string]*leagueResults), failed: make(map[string]failedSeed)}
+}
+
+// failure returns the recent seeding failure of key, or nil. It must be
+// called with rf.mu held.
+func (rf *resultFeeds) failure(key string, now time.Time) error {
+	f, ok := rf.failed[strings.ToLower(key)]
+	if !ok || now.After(f.until) {
+		return nil
+	}
+	return f.err
+}
+
+// fail records that key could not be seeded, dropping expired failures to
+// stay under maxFailedSeeds. It must be called with rf.mu held.
+func (rf *resultFeeds) fail(key string, err error, now time.Time) {
+	if len(rf.failed) >= maxFailedSeeds {
+		for k, f := range rf.failed {
+			if now.After(f.until) {
+				delete(rf.failed, k)
+			}
+		}
+	}
+	if len(rf.failed) < maxFailedSeeds {
+		rf.failed[strings.ToLower(key)] = failedSeed{err: err, until: now.Add(feedFailedTTL)}
+	}
+}
+
+func (rf *resultFeeds) league(key string) *leagueResults {
+	lr, ok := rf.leagues[strings.ToLower(key)]
+	if !ok {
+		lr = &leagueResults{results: make(map[string]feedResult)}
+		rf.leagues[strings.ToLower(key)] = lr
+	}
+	return lr
+}
+
+// handle records the full-time events of a live poll.
+func (rf *resultFeeds) handle(events []matchEvent) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	for _, e := range events {
+		if e.Type != "full_time" || e.LeagueKey == "" || e.MatchID == "" {
+			continue
+		}
+		rf.league(e.LeagueKey).results[e.MatchID] = feedResult{
+			MatchID: e.MatchID,
+			Home:    e.Home,
+			Away:    e.Away,
+			Score:   e.Score,
+			Time:    e.Time,
+		}
+	}
+}
+
+// results returns the league name and its results, newest first, seeding
+// the league from its fixtures when needed.
+func (rf *resultFeeds) results(ctx context.Context, key string) (string, []feedResult, error) {
+	now := time.Now()
+	rf.mu.Lock()
+	lr := rf.leagues[strings.ToLower(key)]
+	stale := lr == nil || now.Sub(lr.seeded) > feedSeedRefresh
+	if lr == nil {
+		if err := rf.failure(key, now); err != nil {
+			rf.mu.Unlock()
+			return "", nil, err
+		}
+	}
+	rf.mu.Unlock()
+
+	if stale {
+		data, err := source.Competition(ctx, leagueFeed(key), queryOf(nil))
+		if err != nil {
+			if lr == nil {
+				rf.mu.Lock()
+				rf.fail(key, err, now)
+				rf.mu.Unlock()
+				return "", nil, err
+			}
+		} else {
+			rf.seed(key, data, time.Now())
+		}
+	}
+
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	lr = rf.league(key)
+	cutoff := time.Now().Add(-feedMaxAge)
+	out := make([]feedResult, 0, len(lr.results))
+	for id, r := range lr.results {
+		if r.Time.Before(cutoff) {
+			delete(lr.results, id)
+			continue
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Time.After(out[j].Time) })
+	if len(out) > feedMaxResults {
+		out = out[:feedMaxResults]
+	}
+	name := lr.name
+	if name == "" {
+		name = key
+	}
+	return name, out, nil
+}
+
+// seed merges the finished matches in a league's fixtures. Results the
+// poller already recorded keep their time.
+func (rf *resultFeeds) seed(key string, data interface{}, now time.Time) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	lr := rf.league(key)
+	lr.seeded = now
+	if name := entityName(data, "league", "competition"); name != "" {
+		lr.name = name
+	}
+	for _, fm := range extractMatches(data) {
+		if fm.ID == "" || !fm.finished() || !fm.HasScore {
+			continue
+		}
+		if lr.name == "" && fm.LeagueName != "" {
+			lr.name = fm.LeagueName
+		}
+		r := feedResult{
+			MatchID: fm.ID,
+			Home:    fm.HomeName,
+			Away:    fm.AwayName,
+			Score:   fmt.Sprintf("%d-%d", fm.HomeGoals, fm.AwayGoals),
+			Round:   fm.Round,
+		}
+		if prev, ok := lr.results[fm.ID]; ok {
+			r.Time = prev.Time
+		} else if kickoff, ok := fm.kickoff(); ok {
+			r.Time = kickoff
+		} else {
+			continue
+		}
+		lr.results[fm.ID] = r
+	}
+}
+
+func (r feedResult) title() string {
+	return fmt.Sprintf("%s %s %s", r.Home, r.Score, r.Away)
+}
+
+func (r feedResult) summary(league string) string {
+	if r.Round != "" {
+		return fmt.Sprintf("Full time in %s round %s: %s", league, r.Round, r.title())
+	}
+	return fmt.Sprintf("Full time in %s: %s", league, r.title())
+}
+
+func (r feedResult) guid() string {
+	return "urn:" + serverName + ":match:" + r.MatchID
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Self          atomLink  `xml:"atom:link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	TTL           int       `xml:"ttl"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID      string `xml:"id"`
+	Title   string `xml:"title"`
+	Updated string `xml:"updated"`
+	Summary string `xml:"summary"`
+}
+
+func registerFeedRoutes(mux *http.ServeMux, rf *resultFeeds, publicURL string, limit func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("GET /feeds/{file}", limit(func(w http.ResponseWriter, r *http.Request) {
+		file := r.PathValue("file")
+		key, atom := strings.CutSuffix(file, ".atom")
+		if !atom {
+			var ok bool
+			if key, ok = strings.CutSuffix(file, ".xml"); !ok {
+				http.NotFound(w, r)
+				return
+			}
+		}
+		if key == "" {
+			http.NotFound(w, r)
+			return
+		}
+		league, results, err := rf.results(r.Context(), key)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("no results for league %q: %v", key, err), http.StatusNotFound)
+			return
+		}
+
+		self := publicURL + "/feeds/" + file
+		updated := time.Now().UTC()
+		if len(results) > 0 {
+			updated = results[0].Time.UTC()
+		}
+		var doc interface{}
+		contentType := "application/rss+xml; charset=utf-8"
+		if atom {
+			contentType = "application/atom+xml; charset=utf-8"
+			feed := atomFeed{
+				ID:      "urn:" + serverName + ":feed:" + key,
+				Title:   league + " results",
+				Updated: updated.Format(time.RFC3339),
+				Author:  atomAuthor{Name: "LiveScore MCP"},
+				Links:   []atomLink{{Href: self, Rel: "self", Type: "application/atom+xml"}, {Href: publicURL}},
+			}
+			for _, res := range results {
+				feed.Entries = append(feed.Entries, atomEntry{
+					ID:      res.guid(),
+					Title:   res.title(),
+					Updated: res.Time.UTC().Format(time.RFC3339),
+					Summary: res.summary(league),
+				})
+			}
+			doc = feed
+		} else {
+			feed := rssFeed{
+				Version: "2.0",
+				Atom:    "http://www.w3.org/2005/Atom",
+				Channel: rssChannel{
+					Title:         league + " results",
+					Link:          publicURL,
+					Self:          atomLink{Href: self, Rel: "self", Type: "application/rss+xml"},
+					Description:   fmt.Sprintf("Full-time results in %s", league),
+					LastBuildDate: updated.Format(time.RFC1123Z),
+					TTL:           feedTTLMinutes,
+				},
+			}
+			for _, res := range results {
+				feed.Channel.Items = append(feed.Channel.Items, rssItem{
+					Title:       res.title(),
+					Description: res.summary(league),
+					GUID:        rssGUID{Value: res.guid()},
+					PubDate:     res.Time.UTC().Format(time.RFC1123Z),
+				})
+			}
+			doc = feed
+		}
+
+		out, err := xml.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Write([]byte(xml.Header))
+		w.Write(out)
+	}))
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Output Formats ---
+//
+// List-shaped tools (fixtures, live scores, standings, top scorers) return
+// JSON by default. With format=csv they return the same rows as CSV text
+// with a header line instead, ready to paste into a spreadsheet or load into
+// a data frame. format=markdown returns a compact, column-aligned markdown
+// table that chat clients render cleanly.
+
+var formatOption = mcp.WithString("format",
+	mcp.Enum("json", "csv", "markdown"),
+	mcp.Description("Output format: json (default), csv or markdown"),
+)
+
+// tableFunc turns a tool's decoded JSON result into a header and rows for
+// format ("csv" or "markdown"). Markdown tables may use fewer, shorter
+// columns than CSV.
+type tableFunc func(data interface{}, format string) (header []string, rows [][]string)
+
+// withFormats wraps a tool handler so format=csv and format=markdown convert
+// its JSON result with table.
+func withFormats(table tableFunc, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format := strings.ToLower(getStr(req.Params.Arguments, "format", "json"))
+		switch format {
+		case "json":
+			return next(ctx, req)
+		case "csv", "markdown":
+		default:
+			return toolErrorResult(codeInvalidArgument, fmt.Sprintf("unknown format %q (use json, csv or markdown)", format)), nil
+		}
+
+		res, err := next(ctx, req)
+		if err != nil || res == nil || res.IsError {
+			return res, err
+		}
+		data, ok := resultJSON(res)
+		if !ok {
+			return res, nil
+		}
+		header, rows := table(data, format)
+		if format == "markdown" {
+			return mcp.NewToolResultText(markdownTable(header, rows)), nil
+		}
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		w.Write(header)
+		w.WriteAll(rows)
+		if err := w.Error(); err != nil {
+			return toolErrorResult(codeInternal, fmt.Sprintf("encode error: %v", err)), nil
+		}
+		return mcp.NewToolResultText(b.String()), nil
+	}
+}
+
+// resultJSON decodes the JSON part of a "Title:\n\n{json}" tool result.
+func resultJSON(res *mcp.CallToolResult) (interface{}, bool) {
+	for _, c := range res.Content {
+		tc, ok := mcp.AsTextContent(c)
+		if !ok {
+			continue
+		}
+		_, body, _ := strings.Cut(tc.Text, "\n\n")
+		var data interface{}
+		if json.Unmarshal([]byte(body), &data) == nil {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// markdownTable renders an aligned markdown table. Columns whose cells are
+// all numbers are right-aligned.
+func markdownTable(header []string, rows [][]string) string {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+	for _, row := range append([][]string{header}, rows...) {
+		for i, c := range row {
+			row[i] = escape.Replace(c)
+		}
+	}
+
+	widths := make([]int, len(header))
+	numeric := make([]bool, len(header))
+	for i, h := range header {
+		widths[i] = max(3, utf8.RuneCountInString(h))
+		numeric[i] = len(rows) > 0
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+			if _, err := strconv.ParseFloat(cell, 64); err != nil && cell != "" {
+				numeric[i] = false
+			}
+		}
+	}
+	cell := func(s string, i int) string {
+		pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(s))
+		if numeric[i] {
+			return pad + s
+		}
+		return s + pad
+	}
+	var b strings.Builder
+	line := func(cells []string) {
+		b.WriteString("|")
+		for i, c := range cells {
+			b.WriteString(" " + cell(c, i) + " |")
+		}
+		b.WriteString("\n")
+	}
+	line(header)
+	b.WriteString("|")
+	for i, w := range widths {
+		if numeric[i] {
+			b.WriteString(" " + strings.Repeat("-", w-1) + ": |")
+		} else {
+			b.WriteString(" " + strings.Repeat("-", w) + " |")
+		}
+	}
+	b.WriteString("\n")
+	for _, row := range rows {
+		line(row)
+	}
+	return b.String()
+}
+
+func matchTable(data interface{}, format string) ([]string, [][]string) {
+	if format == "markdown" {
+		header := []string{"Kickoff (UTC)", "League", "Home", "Score", "Away", "Status"}
+		var rows [][]string
+		for _, fm := range extractMatches(data) {
+			kickoff := ""
+			if t, ok := fm.kickoff(); ok {
+				kickoff = t.Format("2006-01-02 15:04")
+			}
+			score := "-"
+			if fm.HasScore {
+				score = fmt.Sprintf("%d-%d", fm.HomeGoals, fm.AwayGoals)
+			}
+			rows = append(rows, []string{kickoff, fm.LeagueName, fm.HomeName, score, fm.AwayName, fm.Status})
+		}
+		return header, rows
+	}
+	header := []string{"match_id", "kickoff_utc", "league_key", "league", "round", "status",
+		"home_id", "home", "away_id", "away", "home_goals", "away_goals"}
+	var rows [][]string
+	for _, fm := range extractMatches(data) {
+		kickoff := ""
+		if t, ok := fm.kickoff(); ok {
+			kickoff = t.Format(time.RFC3339)
+		}
+		homeGoals, awayGoals := "", ""
+		if fm.HasScore {
+			homeGoals, awayGoals = strconv.Itoa(fm.HomeGoals), strconv.Itoa(fm.AwayGoals)
+		}
+		rows = append(rows, []string{fm.ID, kickoff, fm.LeagueKey, fm.LeagueName, fm.Round, fm.Status,
+			fm.HomeID, fm.HomeName, fm.AwayID, fm.AwayName, homeGoals, awayGoals})
+	}
+	return header, rows
+}
+
+func standingsTable(data interface{}, format string) ([]string, [][]string) {
+	if format == "markdown" {
+		header := []string{"Pos", "Team", "P", "W", "D", "L", "GD", "Pts"}
+		var rows [][]string
+		for _, r := range extractStandings(data) {
+			rows = append(rows, []string{strconv.Itoa(r.Position), r.TeamName,
+				strconv.Itoa(r.Played), strconv.Itoa(r.Won), strconv.Itoa(r.Drawn), strconv.Itoa(r.Lost),
+				fmt.Sprintf("%+d", r.GoalDifference()), strconv.Itoa(r.Points)})
+		}
+		return header, rows
+	}
+	header := []string{"position", "team_id", "team", "played", "won", "drawn", "lost",
+		"goals_for", "goals_against", "goal_difference", "points"}
+	var rows [][]string
+	for _, r := range extractStandings(data) {
+		rows = append(rows, []string{strconv.Itoa(r.Position), r.TeamID, r.TeamName,
+			strconv.Itoa(r.Played), strconv.Itoa(r.Won), strconv.Itoa(r.Drawn), strconv.Itoa(r.Lost),
+			strconv.Itoa(r.GoalsFor), strconv.Itoa(r.GoalsAgainst), strconv.Itoa(r.GoalDifference()),
+			strconv.Itoa(r.Points)})
+	}
+	return header, rows
+}
+
+func topScorersTable(data interface{}, format string) ([]string, [][]string) {
+	list, _ := data.([]interface{})
+	header := []string{"rank", "player_id", "player", "team_id", "team", "goals"}
+	if format == "markdown" {
+		header = []string{"#", "Player", "Team", "Goals"}
+	}
+	var rows [][]string
+	for _, item := range list {
+		p, _ := item.(map[string]interface{})
+		rank, player, team, goals := scalarString(p["rank"]), scalarString(p["player"]), scalarString(p["team"]), scalarString(p["goals"])
+		if format == "markdown" {
+			rows = append(rows, []string{rank, player, team, goals})
+		} else {
+			rows = append(rows, []string{rank, scalarString(p["player_id"]), player, scalarString(p["team_id"]), team, goals})
+		}
+	}
+	return header, rows
+}
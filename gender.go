@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// --- Women's Football ---
+//
+// The feeds do not say whether a competition is men's or women's; women's
+// leagues, cups and internationals are mixed in with the rest. They are told
+// apart by their names and keys (WSL, NWSL, Liga F, "Women", "Frauen", ...)
+// and by women's sides named "Arsenal W" or "Ajax Vrouwen", so the gender
+// and womens arguments can narrow search, fixtures and live scores to one or
+// the other.
+
+const (
+	genderWomen = "women"
+	genderMen   = "men"
+)
+
+var genderOption = mcp.WithString("gender",
+	mcp.Enum(genderWomen, genderMen),
+	mcp.Description("Only women's or only men's football"),
+)
+
+var womensOption = mcp.WithBoolean("womens",
+	mcp.Description("Shorthand for gender=women"),
+)
+
+// womensName matches the names of women's competitions and sides.
+var womensName = regexp.MustCompile(`(?i)\b(women'?s?|ladies|wsl|nwsl|liga f|f[eé]minine|femenin[ao]|femminile|frauen|vrouwen|damallsvenskan|toppserien)\b|\sW$`)
+
+// womensKeyParts are found in the CamelCase league keys of women's
+// competitions, such as EnglandWomensSuperLeague or USANWSL.
+var womensKeyParts = []string{"women", "wsl", "ligaf", "feminin", "femenin", "femminile", "frauen", "vrouwen", "damallsvenskan", "toppserien"}
+
+// isWomensLeague reports whether a league key or name is a women's
+// competition.
+func isWomensLeague(key, name string) bool {
+	if womensName.MatchString(name) {
+		return true
+	}
+	key = strings.ToLower(strings.TrimSuffix(key, "_small"))
+	for _, part := range womensKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// womens reports whether a match is women's football, by its league or, for
+// friendlies in a mixed feed, its teams.
+func (fm feedMatch) womens() bool {
+	return isWomensLeague(fm.LeagueKey, fm.LeagueName) ||
+		womensName.MatchString(fm.HomeName) || womensName.MatchString(fm.AwayName)
+}
+
+// genderArg reads the gender and womens arguments: "women", "men" or "" for
+// both.
+func genderArg(args any) (string, error) {
+	gender := strings.ToLower(getStr(args, "gender", ""))
+	if m, ok := args.(map[string]any); ok && m["womens"] == true {
+		if gender == genderMen {
+			return "", fmt.Errorf("womens=true contradicts gender=men")
+		}
+		gender = genderWomen
+	}
+	if gender != "" && gender != genderWomen && gender != genderMen {
+		return "", fmt.Errorf("invalid gender %q: expected women or men", gender)
+	}
+	return gender, nil
+}
+
+// ofGender reports whether a match is of gender, where "" accepts any.
+func (fm feedMatch) ofGender(gender string) bool {
+	return gender == "" || fm.womens() == (gender == genderWomen)
+}
+
+// searchOfGender narrows upstream search results to gender: teams by name,
+// competitions by key and name, players by their team.
+func searchOfGender(data interface{}, gender string) interface{} {
+	results, ok := data.(map[string]interface{})
+	if gender == "" || !ok {
+		return data
+	}
+	want := gender == genderWomen
+	keep := func(list string, womens func(map[string]interface{}) bool) {
+		items, ok := results[list].([]interface{})
+		if !ok {
+			return
+		}
+		kept := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			if obj, ok := item.(map[string]interface{}); ok && womens(obj) == want {
+				kept = append(kept, item)
+			}
+		}
+		results[list] = kept
+	}
+	keep("teams", func(t map[string]interface{}) bool {
+		return womensName.MatchString(lookupStr(t, teamNameKeys...))
+	})
+	keep("competitions", func(c map[string]interface{}) bool {
+		return isWomensLeague(lookupStr(c, leagueKeyKeys...), lookupStr(c, leagueNameKeys...))
+	})
+	keep("players", func(p map[string]interface{}) bool {
+		team, _ := p["team"].(map[string]interface{})
+		return womensName.MatchString(lookupStr(team, teamNameKeys...))
+	})
+	return results
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Goals of the Day ---
+//
+// get_goals lists every goal of a day across leagues, in the order they were
+// scored, from the events of the day's fixtures. The aggregated fixtures
+// feed mostly comes without events, so the match pages of matches with goals
+// are fetched, at most maxBulkMatches of them, most recent kickoff first; the
+// rest are counted under matches_without_events. A goal's time of day is
+// estimated from the kickoff and its minute, with a quarter of an hour for
+// half-time.
+
+const halfTime = 15 * time.Minute
+
+type dayGoal struct {
+	MatchID  string `json:"match_id"`
+	Match    string `json:"match"`
+	Score    string `json:"score"`
+	League   string `json:"league,omitempty"`
+	Minute   string `json:"minute"`
+	Scorer   string `json:"scorer,omitempty"`
+	PlayerID string `json:"player_id,omitempty"`
+	TeamID   string `json:"team_id,omitempty"`
+	Team     string `json:"team,omitempty"`
+	Type     string `json:"type"`
+
+	at time.Time
+}
+
+// parseDay reads a DD/MM/YYYY date argument, today (UTC) when empty.
+func parseDay(date string) (time.Time, error) {
+	if date == "" {
+		return time.Now().UTC().Truncate(24 * time.Hour), nil
+	}
+	day, err := time.Parse("02/01/2006", date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: expected DD/MM/YYYY", date)
+	}
+	return day, nil
+}
+
+// withEvents returns the matches of data that pass keep, with the match
+// pages fetched for those without events in the feed; missing counts the
+// matches left without events.
+func withEvents(ctx context.Context, req mcp.CallToolRequest, data interface{}, keep func(feedMatch) bool) (matches []feedMatch, missing int) {
+	var fetch []feedMatch
+	seen := map[string]bool{}
+	for _, fm := range extractMatches(data) {
+		if !keep(fm) || (fm.ID != "" && seen[fm.ID]) {
+			continue
+		}
+		seen[fm.ID] = true
+		if len(fm.events()) > 0 || fm.ID == "" {
+			matches = append(matches, fm)
+		} else {
+			fetch = append(fetch, fm)
+		}
+	}
+	sort.SliceStable(fetch, func(i, j int) bool {
+		ti, _ := fetch[i].kickoff()
+		tj, _ := fetch[j].kickoff()
+		return ti.After(tj)
+	})
+	missing = max(0, len(fetch)-maxBulkMatches)
+	fetch = fetch[:min(maxBulkMatches, len(fetch))]
+	for _, detail := range matchDetails(ctx, req, fetch) {
+		if len(detail.events()) == 0 {
+			missing++
+		}
+		matches = append(matches, detail)
+	}
+	return matches, missing
+}
+
+// eventTime estimates when an event happened from the kickoff and its
+// minute.
+func eventTime(kickoff time.Time, e feedEvent) time.Time {
+	minute, added, _ := e.minute()
+	at := kickoff.Add(time.Duration(minute+added) * time.Minute)
+	if minute > 45 {
+		at = at.Add(halfTime)
+	}
+	return at
+}
+
+// dayGoals lists the goals of matches, in the order they were scored.
+func dayGoals(matches []feedMatch) []dayGoal {
+	goals := []dayGoal{}
+	for _, fm := range matches {
+		kickoff, _ := fm.kickoff()
+		for _, e := range fm.events() {
+			if !e.goal() {
+				continue
+			}
+			teamID, team := fm.eventTeam(e)
+			goals = append(goals, dayGoal{
+				MatchID:  fm.ID,
+				Match:    fmt.Sprintf("%s - %s", fm.HomeName, fm.AwayName),
+				Score:    fmt.Sprintf("%d-%d", fm.HomeGoals, fm.AwayGoals),
+				League:   fm.LeagueName,
+				Minute:   e.Minute,
+				Scorer:   e.Player,
+				PlayerID: e.PlayerID,
+				TeamID:   teamID,
+				Team:     team,
+				Type:     e.Type,
+				at:       eventTime(kickoff, e),
+			})
+		}
+	}
+	sort.SliceStable(goals, func(i, j int) bool { return goals[i].at.Before(goals[j].at) })
+	return goals
+}
+
+func registerGoalTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_goals",
+			mcp.WithDescription("Get every goal scored on a day across all leagues, in the order they were scored: scorer, team, minute and match, for a goals-of-the-day feed. Matches in play are included."),
+			mcp.WithString("date", mcp.Description("Date in DD/MM/YYYY format. Default: today (UTC)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			day, err := parseDay(getStr(req.Params.Arguments, "date", ""))
+			if err != nil {
+				return toolErrorResult(codeInvalidArgument, err.Error()), nil
+			}
+			date := day.Format("02/01/2006")
+			data, err := source.Fixtures(ctx, date, 0, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			matches, missing := withEvents(ctx, req, data, func(fm feedMatch) bool {
+				return fm.HasScore && fm.HomeGoals+fm.AwayGoals > 0
+			})
+			goals := dayGoals(matches)
+			return jsonResult(fmt.Sprintf("%d goals on %s (%d matches with goals had no events)", len(goals), date, missing),
+				map[string]interface{}{"date": date, "goals": goals, "matches_without_events": missing}), nil
+		},
+	)
+}
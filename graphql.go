@@ -0,0 +1,509 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// --- GraphQL ---
+//
+// /graphql lets clients fetch teams, players, matches and standings in one
+// request and select only the fields they need. Matches and table rows are
+// typed; everything else in the upstream blobs is reachable through the
+// data(path:) field. Each request fetches an upstream document at most once
+// and at most graphqlMaxFetches documents in total, so deeply nested queries
+// cannot fan out without bound.
+//
+//	{
+//	  match(id: "5001") { status score home { name team { standings { position team { name } points } } } }
+//	}
+
+const graphqlMaxFetches = 20
+
+// gqlLoader memoizes the upstream documents fetched during one request, by a
+// key naming the document ("team/en/101").
+type gqlLoader struct {
+	mu      sync.Mutex
+	results map[string]gqlFetch
+}
+
+type gqlFetch struct {
+	data interface{}
+	err  error
+}
+
+type gqlLoaderKey struct{}
+
+func loadGraphQL(ctx context.Context, key string, fetch func() (interface{}, error)) (interface{}, error) {
+	l, _ := ctx.Value(gqlLoaderKey{}).(*gqlLoader)
+	if l == nil {
+		return fetch()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if f, ok := l.results[key]; ok {
+		return f.data, f.err
+	}
+	if len(l.results) >= graphqlMaxFetches {
+		return nil, fmt.Errorf("query needs more than %d upstream requests; select fewer nested objects", graphqlMaxFetches)
+	}
+	data, err := fetch()
+	l.results[key] = gqlFetch{data, err}
+	return data, err
+}
+
+// gqlEntity is a team, player or league: its ID and upstream document.
+type gqlEntity struct {
+	ID       string
+	Language string
+	Data     interface{}
+}
+
+// gqlTeamRef is a team as named in a match or table row.
+type gqlTeamRef struct {
+	ID       string
+	Name     string
+	Language string
+}
+
+// gqlMatch carries the request language along so nested lookups use it.
+type gqlMatch struct {
+	feedMatch
+	Language string
+}
+
+type gqlStanding struct {
+	standingRow
+	Language string
+}
+
+// jsonPath walks a dotted path ("team.venue", "squad.0") through decoded JSON.
+func jsonPath(v interface{}, path string) interface{} {
+	if path == "" {
+		return v
+	}
+	for _, part := range strings.Split(path, ".") {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = lookup(t, normKey(part)); !ok {
+				return nil
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil
+			}
+			v = t[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// entityName finds the display name at the top of a document or in its
+// team/player/league object.
+func entityName(data interface{}, wrappers ...string) string {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for _, w := range wrappers {
+		if inner, ok := lookup(m, w); ok {
+			if im, ok := inner.(map[string]interface{}); ok {
+				if name := lookupStr(im, "name", "leaguename", "teamname", "playername"); name != "" {
+					return name
+				}
+			}
+		}
+	}
+	return lookupStr(m, "name", "leaguename", "teamname", "playername")
+}
+
+func strArg(p graphql.ResolveParams, name string) string {
+	s, _ := p.Args[name].(string)
+	return s
+}
+
+var jsonScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "Arbitrary JSON from the upstream API",
+	Serialize:   func(v interface{}) interface{} { return v },
+	ParseValue:  func(v interface{}) interface{} { return v },
+	ParseLiteral: func(v ast.Value) interface{} {
+		return v.GetValue()
+	},
+})
+
+func dataField(description string, source func(interface{}) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type:        jsonScalar,
+		Description: description + ", optionally narrowed to a dotted path such as squad or team.venue",
+		Args: graphql.FieldConfigArgument{
+			"path": &graphql.ArgumentConfig{Type: graphql.String},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return jsonPath(source(p.Source), strArg(p, "path")), nil
+		},
+	}
+}
+
+func newGraphQLSchema() (graphql.Schema, error) {
+	var matchType, teamType, standingType *graphql.Object
+
+	langArg := &graphql.ArgumentConfig{Type: graphql.String, Description: "Language code (en, nl, de, etc.). Default: en"}
+	language := func(p graphql.ResolveParams) string {
+		if l := strArg(p, "language"); l != "" {
+			return l
+		}
+		return defaultLang
+	}
+	q := func(lang string) sourceQuery { return sourceQuery{Language: lang, Version: defaultVersion} }
+
+	fetchTeam := func(ctx context.Context, id, lang string) (interface{}, error) {
+		data, err := loadGraphQL(ctx, "team/"+lang+"/"+id, func() (interface{}, error) {
+			return source.Team(ctx, id, q(lang))
+		})
+		if err != nil {
+			return nil, err
+		}
+		return gqlEntity{ID: id, Language: lang, Data: data}, nil
+	}
+	matchesOf := func(data interface{}, lang string) []gqlMatch {
+		var out []gqlMatch
+		for _, fm := range extractMatches(data) {
+			out = append(out, gqlMatch{fm, lang})
+		}
+		return out
+	}
+	standingsOf := func(data interface{}, lang string) []gqlStanding {
+		var out []gqlStanding
+		for _, row := range extractStandings(data) {
+			out = append(out, gqlStanding{row, lang})
+		}
+		return out
+	}
+	str := func(get func(interface{}) string) *graphql.Field {
+		return &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source), nil
+		}}
+	}
+	num := func(get func(interface{}) int) *graphql.Field {
+		return &graphql.Field{Type: graphql.Int, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source), nil
+		}}
+	}
+
+	teamRefType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "TeamRef",
+		Description: "A team as named in a match or table row",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":   str(func(s interface{}) string { return s.(gqlTeamRef).ID }),
+				"name": str(func(s interface{}) string { return s.(gqlTeamRef).Name }),
+				"team": &graphql.Field{
+					Type:        teamType,
+					Description: "Full team details (one upstream request)",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						ref := p.Source.(gqlTeamRef)
+						if ref.ID == "" {
+							return nil, nil
+						}
+						return fetchTeam(p.Context, ref.ID, ref.Language)
+					},
+				},
+			}
+		}),
+	})
+
+	matchType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Match",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			m := func(s interface{}) gqlMatch { return s.(gqlMatch) }
+			return graphql.Fields{
+				"id":         str(func(s interface{}) string { return m(s).ID }),
+				"status":     str(func(s interface{}) string { return m(s).Status }),
+				"round":      str(func(s interface{}) string { return m(s).Round }),
+				"leagueKey":  str(func(s interface{}) string { return m(s).LeagueKey }),
+				"leagueName": str(func(s interface{}) string { return m(s).LeagueName }),
+				"country":    str(func(s interface{}) string { return m(s).Country }),
+				"score": &graphql.Field{Type: graphql.String, Description: "Score as home-away, null before kickoff", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if fm := m(p.Source); fm.HasScore {
+						return fmt.Sprintf("%d-%d", fm.HomeGoals, fm.AwayGoals), nil
+					}
+					return nil, nil
+				}},
+				"homeGoals": &graphql.Field{Type: graphql.Int, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if fm := m(p.Source); fm.HasScore {
+						return fm.HomeGoals, nil
+					}
+					return nil, nil
+				}},
+				"awayGoals": &graphql.Field{Type: graphql.Int, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if fm := m(p.Source); fm.HasScore {
+						return fm.AwayGoals, nil
+					}
+					return nil, nil
+				}},
+				"finished": &graphql.Field{Type: graphql.Boolean, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return m(p.Source).finished(), nil
+				}},
+				"home": &graphql.Field{Type: teamRefType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					fm := m(p.Source)
+					return gqlTeamRef{fm.HomeID, fm.HomeName, fm.Language}, nil
+				}},
+				"away": &graphql.Field{Type: teamRefType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					fm := m(p.Source)
+					return gqlTeamRef{fm.AwayID, fm.AwayName, fm.Language}, nil
+				}},
+				"details": &graphql.Field{
+					Type:        jsonScalar,
+					Description: "Full match document (events, lineups, stats, h2h; one upstream request), optionally narrowed to a dotted path such as events",
+					Args:        graphql.FieldConfigArgument{"path": &graphql.ArgumentConfig{Type: graphql.String}},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						fm := m(p.Source)
+						if fm.ID == "" {
+							return nil, nil
+						}
+						data, err := loadGraphQL(p.Context, "match/"+fm.Language+"/"+fm.ID, func() (interface{}, error) {
+							return source.Match(p.Context, fm.ID, true, q(fm.Language))
+						})
+						if err != nil {
+							return nil, err
+						}
+						return jsonPath(data, strArg(p, "path")), nil
+					},
+				},
+				"data": dataField("The match as it appears in the feed", func(s interface{}) interface{} { return m(s).Raw }),
+			}
+		}),
+	})
+	standingType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Standing",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			r := func(s interface{}) standingRow { return s.(gqlStanding).standingRow }
+			return graphql.Fields{
+				"position":       num(func(s interface{}) int { return r(s).Position }),
+				"played":         num(func(s interface{}) int { return r(s).Played }),
+				"won":            num(func(s interface{}) int { return r(s).Won }),
+				"drawn":          num(func(s interface{}) int { return r(s).Drawn }),
+				"lost":           num(func(s interface{}) int { return r(s).Lost }),
+				"goalsFor":       num(func(s interface{}) int { return r(s).GoalsFor }),
+				"goalsAgainst":   num(func(s interface{}) int { return r(s).GoalsAgainst }),
+				"goalDifference": num(func(s interface{}) int { return r(s).GoalDifference() }),
+				"points":         num(func(s interface{}) int { return r(s).Points }),
+				"team": &graphql.Field{Type: teamRefType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					st := p.Source.(gqlStanding)
+					return gqlTeamRef{st.TeamID, st.TeamName, st.Language}, nil
+				}},
+			}
+		}),
+	})
+
+	entity := func(s interface{}) gqlEntity { return s.(gqlEntity) }
+	teamType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Team",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":   str(func(s interface{}) string { return entity(s).ID }),
+				"name": str(func(s interface{}) string { return entityName(entity(s).Data, "team") }),
+				"matches": &graphql.Field{Type: graphql.NewList(matchType), Description: "Recent and upcoming matches", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					e := entity(p.Source)
+					return matchesOf(e.Data, e.Language), nil
+				}},
+				"standings": &graphql.Field{Type: graphql.NewList(standingType), Description: "League table included with the team", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					e := entity(p.Source)
+					return standingsOf(e.Data, e.Language), nil
+				}},
+				"data": dataField("Team document (squad, stats, injuries)", func(s interface{}) interface{} { return entity(s).Data }),
+			}
+		}),
+	})
+
+	playerType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Player",
+		Fields: graphql.Fields{
+			"id":   str(func(s interface{}) string { return entity(s).ID }),
+			"name": str(func(s interface{}) string { return entityName(entity(s).Data, "player") }),
+			"data": dataField("Player document (career, stats)", func(s interface{}) interface{} { return entity(s).Data }),
+		},
+	})
+
+	leagueType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "League",
+		Fields: graphql.Fields{
+			"key":  str(func(s interface{}) string { return entity(s).ID }),
+			"name": str(func(s interface{}) string { return entityName(entity(s).Data, "league", "competition") }),
+			"matches": &graphql.Field{Type: graphql.NewList(matchType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				e := entity(p.Source)
+				return matchesOf(e.Data, e.Language), nil
+			}},
+			"standings": &graphql.Field{Type: graphql.NewList(standingType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				e := entity(p.Source)
+				return standingsOf(e.Data, e.Language), nil
+			}},
+			"data": dataField("League fixtures document", func(s interface{}) interface{} { return entity(s).Data }),
+		},
+	})
+
+	idArgs := func(name string) graphql.FieldConfigArgument {
+		return graphql.FieldConfigArgument{
+			name:       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			"language": langArg,
+		}
+	}
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"team": &graphql.Field{Type: teamType, Args: idArgs("id"), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return fetchTeam(p.Context, strArg(p, "id"), language(p))
+			}},
+			"player": &graphql.Field{Type: playerType, Args: idArgs("id"), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id, lang := strArg(p, "id"), language(p)
+				data, err := loadGraphQL(p.Context, "player/"+lang+"/"+id, func() (interface{}, error) {
+					return source.Player(p.Context, id, q(lang))
+				})
+				if err != nil {
+					return nil, err
+				}
+				return gqlEntity{ID: id, Language: lang, Data: data}, nil
+			}},
+			"match": &graphql.Field{Type: matchType, Args: idArgs("id"), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id, lang := strArg(p, "id"), language(p)
+				data, err := loadGraphQL(p.Context, "match/"+lang+"/"+id, func() (interface{}, error) {
+					return source.Match(p.Context, id, true, q(lang))
+				})
+				if err != nil {
+					return nil, err
+				}
+				fm, ok := primaryMatch(data)
+				if !ok {
+					return nil, fmt.Errorf("match %s not found", id)
+				}
+				if fm.ID == "" {
+					fm.ID = id
+				}
+				return gqlMatch{fm, lang}, nil
+			}},
+			"league": &graphql.Field{Type: leagueType, Args: idArgs("key"), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				key, lang := strArg(p, "key"), language(p)
+				data, err := loadGraphQL(p.Context, "league/"+lang+"/"+key, func() (interface{}, error) {
+					return source.Competition(p.Context, leagueFeed(key), q(lang))
+				})
+				if err != nil {
+					return nil, err
+				}
+				return gqlEntity{ID: key, Language: lang, Data: data}, nil
+			}},
+			"liveMatches": &graphql.Field{
+				Type: graphql.NewList(matchType),
+				Args: graphql.FieldConfigArgument{
+					"teamId":    &graphql.ArgumentConfig{Type: graphql.String},
+					"teamName":  &graphql.ArgumentConfig{Type: graphql.String},
+					"leagueKey": &graphql.ArgumentConfig{Type: graphql.String},
+					"language":  langArg,
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					lang := language(p)
+					data, err := loadGraphQL(p.Context, "live/"+lang, func() (interface{}, error) {
+						return source.LiveScores(p.Context, q(lang))
+					})
+					if err != nil {
+						return nil, err
+					}
+					teamID, teamName, league := strArg(p, "teamId"), strArg(p, "teamName"), strArg(p, "leagueKey")
+					var out []gqlMatch
+					for _, fm := range matchesOf(data, lang) {
+						if (teamID != "" || teamName != "") && !fm.involvesTeam(teamID, teamName) {
+							continue
+						}
+						if league != "" && !fm.inLeague(league) {
+							continue
+						}
+						out = append(out, fm)
+					}
+					return out, nil
+				},
+			},
+			"fixtures": &graphql.Field{
+				Type:        graphql.NewList(matchType),
+				Description: "All matches on a date",
+				Args: graphql.FieldConfigArgument{
+					"date":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String), Description: "DD/MM/YYYY"},
+					"leagueKey": &graphql.ArgumentConfig{Type: graphql.String},
+					"language":  langArg,
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					lang := language(p)
+					data, err := loadGraphQL(p.Context, "fixtures/"+lang+"/"+strArg(p, "date"), func() (interface{}, error) {
+						return source.Fixtures(p.Context, strArg(p, "date"), 0, q(lang))
+					})
+					if err != nil {
+						return nil, err
+					}
+					league := strArg(p, "leagueKey")
+					var out []gqlMatch
+					for _, fm := range matchesOf(data, lang) {
+						if league == "" || fm.inLeague(league) {
+							out = append(out, fm)
+						}
+					}
+					return out, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// graphqlHandler serves GraphQL over POST (JSON body) and GET (query string).
+func graphqlHandler(schema graphql.Schema) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query         string                 `json:"query"`
+			Variables     map[string]interface{} `json:"variables"`
+			OperationName string                 `json:"operationName"`
+		}
+		switch r.Method {
+		case http.MethodGet:
+			body.Query = r.URL.Query().Get("query")
+			body.OperationName = r.URL.Query().Get("operationName")
+			if v := r.URL.Query().Get("variables"); v != "" {
+				json.Unmarshal([]byte(v), &body.Variables)
+			}
+		case http.MethodPost:
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "expected a JSON body with a query"})
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET or POST"})
+			return
+		}
+		if body.Query == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "missing query"})
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), gqlLoaderKey{}, &gqlLoader{results: make(map[string]gqlFetch)})
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  body.Query,
+			VariableValues: body.Variables,
+			OperationName:  body.OperationName,
+			Context:        ctx,
+		})
+		writeJSON(w, http.StatusOK, result)
+	}
+}
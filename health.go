@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// --- Health ---
+//
+// The deep health report probes upstream, at most once per healthProbeTTL
+// however often it is asked for. Anyone gets the status, build and probe;
+// the state of the caches and sessions and the upstream URLs in use are only
+// reported in full, to requests with the admin token.
+
+const healthProbeTTL = 10 * time.Second
+
+// healthChecker gathers the deep health report: an upstream probe plus the
+// state of the in-memory caches and sessions.
+type healthChecker struct {
+	started  time.Time
+	sessions atomic.Int64 // open SSE sessions
+	tracker  *liveTracker
+	catalog  *competitionCatalog
+	store    *sessionStore
+	limiter  *rateLimiter
+
+	mu       sync.Mutex
+	probe    upstreamProbe
+	probedAt time.Time
+}
+
+type upstreamProbe struct {
+	OK        bool   `json:"ok"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+func probeUpstream(ctx context.Context) upstreamProbe {
+	start := time.Now()
+	_, err := requestUpstream(ctx, buildURL("fixtures/feed_livenow.json", nil))
+	p := upstreamProbe{OK: err == nil, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		p.Error = err.Error()
+	}
+	return p
+}
+
+// cachedProbe returns the latest upstream probe, probing again once it is
+// older than healthProbeTTL.
+func (hc *healthChecker) cachedProbe(ctx context.Context) upstreamProbe {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	if hc.probedAt.IsZero() || time.Since(hc.probedAt) > healthProbeTTL {
+		hc.probe, hc.probedAt = probeUpstream(ctx), time.Now()
+	}
+	return hc.probe
+}
+
+// deep probes upstream and reports overall status "ok" or "degraded", with
+// the caches, sessions and failover state when full is set.
+func (hc *healthChecker) deep(ctx context.Context, full bool) (map[string]interface{}, bool) {
+	probe := hc.cachedProbe(ctx)
+	status := "ok"
+	if !probe.OK {
+		status = "degraded"
+	}
+
+	report := map[string]interface{}{
+		"status":         status,
+		"server":         serverName,
+		"version":        serverVersion,
+		"build":          currentBuild(),
+		"uptime_seconds": int64(time.Since(hc.started).Seconds()),
+		"upstream":       probe,
+	}
+	if !full {
+		probe.Error = "" // names upstream hosts
+		report["upstream"] = probe
+		return report, probe.OK
+	}
+
+	live, lastPoll, events := hc.tracker.stats()
+	competitions, catalogUpdated := hc.catalog.list()
+	prefetched, prefetchServed := hotFeeds.stats()
+	driftChecked, drift := payloadDrift.stats()
+	report["sessions"] = map[string]interface{}{
+		"open":       hc.sessions.Load(),
+		"with_state": hc.store.count(),
+	}
+	report["cache"] = map[string]interface{}{
+		"live_matches":         live,
+		"live_last_poll":       formatTime(lastPoll),
+		"recent_events":        events,
+		"competitions":         len(competitions),
+		"competitions_updated": formatTime(catalogUpdated),
+		"rate_limit_visitors":  hc.limiter.visitorCount(),
+		"prefetched_feeds":     prefetched,
+		"prefetch_served":      prefetchServed,
+	}
+	report["upstream_drift"] = map[string]interface{}{
+		"payloads_checked": driftChecked,
+		"warnings":         drift,
+	}
+	if fs, ok := source.(*failoverSource); ok {
+		report["upstream_failover"] = fs.status()
+	}
+	return report, probe.OK
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
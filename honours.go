@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Honours ---
+//
+// Some team pages carry the club's trophies, as a list of competitions with
+// the seasons won or as one entry per title. get_team_honours groups them per
+// competition with a count and the years, and sorts them into league titles,
+// domestic cups, European and other international trophies.
+
+var (
+	europeanTrophy = regexp.MustCompile(`(?i)\b(champions league|european cup|europa league|uefa cup|conference league|cup winners'? cup|super ?cup|intertoto)\b`)
+	worldTrophy    = regexp.MustCompile(`(?i)\b(club world cup|intercontinental|libertadores|sudamericana|concacaf|caf|afc)\b`)
+	cupTrophy      = regexp.MustCompile(`(?i)\b(cup|pokal|coupe|copa|coppa|beker|ta[cç]a|shield|supercopa|supercoppa|trophy)\b`)
+	yearPattern    = regexp.MustCompile(`\b(18|19|20)\d\d(/\d{2,4})?\b`)
+)
+
+type honour struct {
+	Competition string   `json:"competition"`
+	Category    string   `json:"category"` // league, cup, europe, international
+	Count       int      `json:"count"`
+	Years       []string `json:"years,omitempty"`
+}
+
+func honourCategory(name string) string {
+	switch {
+	case worldTrophy.MatchString(name):
+		return "international"
+	case europeanTrophy.MatchString(name):
+		return "europe"
+	case cupTrophy.MatchString(name):
+		return "cup"
+	}
+	return "league"
+}
+
+// extractHonours reads a team payload's trophies, or nil if it has none.
+func extractHonours(data interface{}) []honour {
+	v, ok := findKey(data, "honours", "honors", "trophies", "titles", "palmares")
+	if !ok {
+		return nil
+	}
+	items, _ := v.([]interface{})
+	byName := map[string]*honour{}
+	var order []string
+	for _, item := range items {
+		e, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		_, name := nameOrString(e, append([]string{"name", "trophy", "title"}, competitionKeys...)...)
+		if name == "" {
+			continue
+		}
+		h := byName[name]
+		if h == nil {
+			h = &honour{Competition: name, Category: honourCategory(name)}
+			byName[name] = h
+			order = append(order, name)
+		}
+		var years []string
+		if list, ok := lookup(e, "years", "seasons", "won"); ok {
+			if l, ok := list.([]interface{}); ok {
+				for _, y := range l {
+					years = append(years, scalarString(y))
+				}
+			} else {
+				years = yearPattern.FindAllString(scalarString(list), -1)
+			}
+		} else if y := lookupStr(e, "year", "season"); y != "" {
+			years = []string{y}
+		}
+		h.Years = append(h.Years, years...)
+		if n, ok := lookupInt(e, "count", "times", "total"); ok {
+			h.Count += n
+		} else {
+			h.Count += max(1, len(years))
+		}
+	}
+	rank := map[string]int{"league": 0, "cup": 1, "europe": 2, "international": 3}
+	out := make([]honour, 0, len(order))
+	for _, name := range order {
+		h := byName[name]
+		sort.Strings(h.Years)
+		h.Count = max(h.Count, len(h.Years))
+		out = append(out, *h)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return rank[out[i].Category] < rank[out[j].Category] })
+	return out
+}
+
+func registerHonoursTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_team_honours",
+			mcp.WithDescription("Get a team's trophies: league titles, domestic cups, European and international trophies, each with the number won and the years"),
+			mcp.WithString("team_id", mcp.Required(), mcp.Description("Team ID from search results (e.g. 13183 for Ajax)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "team_id", "")
+			data, err := source.Team(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			honours := extractHonours(data)
+			if honours == nil {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("upstream has no trophy history for team %s", id)), nil
+			}
+			total := 0
+			for _, h := range honours {
+				total += h.Count
+			}
+			return jsonResult(fmt.Sprintf("Honours of team %s (%d trophies)", id, total), honours), nil
+		},
+	)
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/image/draw"
+)
+
+// --- Image Proxy ---
+//
+// /img/team/{id}.png serves team logos from this server's domain, so agent
+// UIs and embeds never link to the upstream host. Logos are cached in memory
+// for a day, missing logos for a few minutes, and ?size=N scales a logo to
+// fit an N×N box. The cache is bounded by total size and by entry count, so
+// requests for ids without a logo cannot grow it, and the route is rate
+// limited per IP like the other public pages.
+
+const (
+	imageCacheTTL      = 24 * time.Hour
+	imageMissingTTL    = 10 * time.Minute
+	imageCacheMaxBytes = 32 << 20
+	imageCacheMaxItems = 4096
+	imageMaxBytes      = 2 << 20
+	imageMinSize       = 16
+	imageMaxSize       = 512
+)
+
+var imageIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+type cachedImage struct {
+	data    []byte // nil when upstream has no logo
+	etag    string
+	fetched time.Time
+	ttl     time.Duration // overrides the default lifetime when set
+}
+
+type imageCache struct {
+	mu    sync.Mutex
+	items map[string]*cachedImage // by "id", "id@size" or "og:match:id"
+	bytes int
+}
+
+func newImageCache() *imageCache {
+	return &imageCache{items: make(map[string]*cachedImage)}
+}
+
+func (ic *imageCache) get(key string, now time.Time) (*cachedImage, bool) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	img, ok := ic.items[key]
+	if !ok {
+		return nil, false
+	}
+	ttl := imageCacheTTL
+	if img.ttl > 0 {
+		ttl = img.ttl
+	} else if img.data == nil {
+		ttl = imageMissingTTL
+	}
+	if now.Sub(img.fetched) > ttl {
+		ic.bytes -= len(img.data)
+		delete(ic.items, key)
+		return nil, false
+	}
+	return img, true
+}
+
+// put stores img, evicting the oldest entries to stay under the size and
+// entry limits.
+func (ic *imageCache) put(key string, img *cachedImage) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	if old, ok := ic.items[key]; ok {
+		ic.bytes -= len(old.data)
+	}
+	ic.items[key] = img
+	ic.bytes += len(img.data)
+	for (ic.bytes > imageCacheMaxBytes || len(ic.items) > imageCacheMaxItems) && len(ic.items) > 1 {
+		oldest := ""
+		for k, v := range ic.items {
+			if k != key && (oldest == "" || v.fetched.Before(ic.items[oldest].fetched)) {
+				oldest = k
+			}
+		}
+		ic.bytes -= len(ic.items[oldest].data)
+		delete(ic.items, oldest)
+	}
+}
+
+// teamImageURL is the upstream logo URL for a team.
+func teamImageURL(id string) string {
+	u, _ := url.Parse(baseURL)
+	u.Path, _ = url.JoinPath(u.Path, "images", "teams_gs", id+".png")
+	return u.String()
+}
+
+// teamLogo returns the team's logo scaled to size (0 for the original),
+// from the cache when possible. A nil result means upstream has no logo.
+func (ic *imageCache) teamLogo(ctx context.Context, id string, size int) (*cachedImage, error) {
+	key := id
+	if size > 0 {
+		key = fmt.Sprintf("%s@%d", id, size)
+	}
+	now := time.Now()
+	if img, ok := ic.get(key, now); ok {
+		return img, nil
+	}
+
+	var data []byte
+	if size > 0 {
+		orig, err := ic.teamLogo(ctx, id, 0)
+		if err != nil || orig.data == nil {
+			return orig, err
+		}
+		if data, err = scalePNG(orig.data, size); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if data, err = fetchImage(ctx, teamImageURL(id)); err != nil {
+			return nil, err
+		}
+	}
+
+	img := &cachedImage{data: data, fetched: now}
+	if data != nil {
+		sum := sha256.Sum256(data)
+		img.etag = `"` + hex.EncodeToString(sum[:8]) + `"`
+	}
+	ic.put(key, img)
+	return img, nil
+}
+
+// fetchImage downloads an upstream image. It returns nil data when the
+// image does not exist.
+func fetchImage(ctx context.Context, imageURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request error: %v", err)
+	}
+	req.Header.Set("User-Agent", "LiveScore-MCP/1.0")
+	resp, err := upstreamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch error: %v", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("upstream status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, imageMaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read error: %v", err)
+	}
+	if len(data) > imageMaxBytes {
+		return nil, fmt.Errorf("image larger than %d bytes", imageMaxBytes)
+	}
+	return data, nil
+}
+
+// scalePNG fits a PNG into a size×size box, keeping its aspect ratio.
+func scalePNG(data []byte, size int) ([]byte, error) {
+	src, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode logo: %v", err)
+	}
+	b := src.Bounds()
+	w, h := size, size
+	if b.Dx() > b.Dy() {
+		h = max(1, b.Dy()*size/b.Dx())
+	} else if b.Dy() > b.Dx() {
+		w = max(1, b.Dx()*size/b.Dy())
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	var out bytes.Buffer
+	if err := png.Encode(&out, dst); err != nil {
+		return nil, fmt.Errorf("encode logo: %v", err)
+	}
+	return out.Bytes(), nil
+}
+
+func registerImageRoutes(mux *http.ServeMux, ic *imageCache, limit func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("GET /img/team/{file}", limit(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := strings.CutSuffix(r.PathValue("file"), ".png")
+		if !ok || !imageIDPattern.MatchString(id) {
+			http.NotFound(w, r)
+			return
+		}
+		size := 0
+		if s := r.URL.Query().Get("size"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < imageMinSize || n > imageMaxSize {
+				http.Error(w, fmt.Sprintf("size must be between %d and %d", imageMinSize, imageMaxSize), http.StatusBadRequest)
+				return
+			}
+			size = n
+		}
+
+		img, err := ic.teamLogo(r.Context(), id, size)
+		if err != nil {
+			log.Printf("Image proxy: team %s: %v", id, err)
+			http.Error(w, "logo unavailable", http.StatusBadGateway)
+			return
+		}
+		if img.data == nil {
+			w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(imageMissingTTL.Seconds())))
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "public, max-age=604800, stale-while-revalidate=86400")
+		w.Header().Set("ETag", img.etag)
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Cross-Origin-Resource-Policy", "cross-origin")
+		if r.Header.Get("If-None-Match") == img.etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write(img.data)
+	}))
+}
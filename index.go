@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- Offline Index ---
+//
+// data/index.json is a snapshot of league keys and team IDs and names,
+// embedded in the binary. It seeds the competitions catalog, so league key
+// validation and suggestions work from the first request, and search falls
+// back to it when upstream search fails or is slow. Regenerate it with
+// `livescore-mcp build-index` before a release; it is built by crawling the
+// day feeds and league tables like the catalog does, just further back and
+// ahead. The players of the top-tier squads are added with their birth
+// dates, for get_birthdays.
+
+const (
+	indexPastDays  = 60
+	indexAheadDays = 60
+	searchTimeout  = 5 * time.Second // before search falls back to the index
+	indexMaxHits   = 20
+)
+
+//go:embed data/index.json
+var embeddedIndex []byte
+
+type indexedTeam struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Country   string `json:"country,omitempty"`
+	LeagueKey string `json:"league_key,omitempty"`
+}
+
+type indexedPlayer struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	TeamID    string `json:"team_id,omitempty"`
+	Team      string `json:"team,omitempty"`
+	BirthDate string `json:"birth_date"` // YYYY-MM-DD
+}
+
+type offlineIndex struct {
+	Generated    string          `json:"generated"`
+	Competitions []competition   `json:"competitions"`
+	Teams        []indexedTeam   `json:"teams"`
+	Players      []indexedPlayer `json:"players,omitempty"`
+}
+
+// builtinIndex is the embedded index, shared like hotFeeds.
+var builtinIndex = loadIndex(embeddedIndex)
+
+func loadIndex(data []byte) *offlineIndex {
+	ix := &offlineIndex{}
+	if err := json.Unmarshal(data, ix); err != nil {
+		log.Printf("Index: embedded index is invalid: %v", err)
+	}
+	return ix
+}
+
+// search answers a search from the index, in the shape of upstream search.
+func (ix *offlineIndex) search(query, country string) map[string]interface{} {
+	q := strings.ToLower(strings.TrimSpace(query))
+	matches := func(name, c string) bool {
+		return strings.Contains(strings.ToLower(name), q) && (country == "" || strings.EqualFold(c, country))
+	}
+	teams := []map[string]interface{}{}
+	for _, t := range ix.Teams {
+		if len(teams) < indexMaxHits && matches(t.Name, t.Country) {
+			teams = append(teams, map[string]interface{}{"id": t.ID, "name": t.Name, "country": t.Country})
+		}
+	}
+	competitions := []map[string]interface{}{}
+	for _, c := range ix.Competitions {
+		if len(competitions) < indexMaxHits && (matches(c.Name, c.Country) || matches(c.Key, c.Country)) {
+			competitions = append(competitions, map[string]interface{}{"league_key": c.Key, "league_name": c.Name, "country": c.Country})
+		}
+	}
+	return map[string]interface{}{
+		"teams":        teams,
+		"players":      []interface{}{},
+		"competitions": competitions,
+	}
+}
+
+// buildIndex crawls upstream for competitions and their teams.
+func buildIndex(ctx context.Context, now time.Time) (*offlineIndex, error) {
+	catalog := newCompetitionCatalog(catalogRefresh)
+	teams := map[string]indexedTeam{}
+	addTeam := func(id, name, country, league string) {
+		if id == "" || name == "" {
+			return
+		}
+		if _, ok := teams[id]; !ok {
+			teams[id] = indexedTeam{ID: id, Name: name, Country: country, LeagueKey: league}
+		}
+	}
+
+	days := 0
+	for d := -indexPastDays; d <= indexAheadDays; d++ {
+		day := now.AddDate(0, 0, d).Format("02/01/2006")
+		data, err := source.Fixtures(ctx, day, 0, queryOf(nil))
+		if err != nil {
+			log.Printf("Index: %s: %v", day, err)
+			continue
+		}
+		days++
+		matches := extractMatches(data)
+		catalog.add(matches)
+		for _, m := range matches {
+			addTeam(m.HomeID, m.HomeName, m.Country, m.LeagueKey)
+			addTeam(m.AwayID, m.AwayName, m.Country, m.LeagueKey)
+		}
+	}
+	if days == 0 {
+		return nil, fmt.Errorf("no day feed could be fetched")
+	}
+
+	// League tables fill in teams that had no match in the window.
+	competitions, _ := catalog.list()
+	for _, c := range competitions {
+		data, err := source.Competition(ctx, leagueFeed(c.Key), queryOf(nil))
+		if err != nil {
+			log.Printf("Index: %s: %v", c.Key, err)
+			continue
+		}
+		for _, row := range extractStandings(data) {
+			addTeam(row.TeamID, row.TeamName, c.Country, c.Key)
+		}
+	}
+
+	ix := &offlineIndex{Generated: now.UTC().Format(time.RFC3339), Competitions: competitions}
+	for _, t := range teams {
+		ix.Teams = append(ix.Teams, t)
+		if c, ok := catalog.lookup(t.LeagueKey); ok && competitionTier(c.Key, c.Name) == 1 {
+			ix.Players = append(ix.Players, indexSquad(ctx, t)...)
+		}
+	}
+	sort.Slice(ix.Teams, func(i, j int) bool {
+		if ix.Teams[i].Name != ix.Teams[j].Name {
+			return ix.Teams[i].Name < ix.Teams[j].Name
+		}
+		return ix.Teams[i].ID < ix.Teams[j].ID
+	})
+	sort.Slice(ix.Players, func(i, j int) bool { return ix.Players[i].ID < ix.Players[j].ID })
+	return ix, nil
+}
+
+// indexSquad fetches the birth dates of a team's players.
+func indexSquad(ctx context.Context, t indexedTeam) []indexedPlayer {
+	data, err := source.Team(ctx, t.ID, queryOf(nil))
+	if err != nil {
+		log.Printf("Index: team %s: %v", t.ID, err)
+		return nil
+	}
+	var players []indexedPlayer
+	for _, p := range extractSquad(data) {
+		data, err := source.Player(ctx, p.ID, queryOf(nil))
+		if err != nil {
+			log.Printf("Index: player %s: %v", p.ID, err)
+			continue
+		}
+		if born := birthDate(data); born != "" {
+			players = append(players, indexedPlayer{ID: p.ID, Name: p.Name, TeamID: t.ID, Team: t.Name, BirthDate: born})
+		}
+	}
+	return players
+}
+
+// writeIndex builds the index and writes it to path, for the build-index
+// command.
+func writeIndex(path string) int {
+	ix, err := buildIndex(context.Background(), time.Now().UTC())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "build-index: %v\n", err)
+		return 1
+	}
+	data, err := json.MarshalIndent(ix, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "build-index: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "build-index: %v\n", err)
+		return 1
+	}
+	fmt.Printf("wrote %s: %d competitions, %d teams, %d players\n", path, len(ix.Competitions), len(ix.Teams), len(ix.Players))
+	return 0
+}
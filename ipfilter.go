@@ -0,0 +1,155 @@
+package main
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// --- IP Filter ---
+//
+// deny_cidrs (DENY_CIDRS) blocks networks from every endpoint. allow_cidrs
+// (ALLOW_CIDRS) marks trusted networks (internal monitoring, health
+// checkers) that bypass rate limiting. Both take CIDRs or single IPs, and a
+// config reload applies them to the running server.
+//
+// X-Forwarded-For is believed only from the reverse proxies listed in
+// trusted_proxies (TRUSTED_PROXIES), "unix" standing for connections on a
+// unix socket; anyone else could put any address there. Without trusted
+// proxies the client address is the connection's own.
+
+type ipRules struct {
+	allow       []*net.IPNet
+	deny        []*net.IPNet
+	proxies     []*net.IPNet
+	unixProxied bool // connections on a unix socket come from a trusted proxy
+}
+
+type ipFilter struct {
+	rules atomic.Pointer[ipRules]
+}
+
+func newIPFilter(cfg *serverConfig) *ipFilter {
+	f := &ipFilter{}
+	f.reconfigure(cfg)
+	return f
+}
+
+// reconfigure replaces the filter's rules with those of cfg.
+func (f *ipFilter) reconfigure(cfg *serverConfig) {
+	rules := &ipRules{
+		allow: parseCIDRs("allow_cidrs", cfg.AllowCIDRs),
+		deny:  parseCIDRs("deny_cidrs", cfg.DenyCIDRs),
+	}
+	var proxies []string
+	for _, entry := range cfg.TrustedProxies {
+		if strings.TrimSpace(entry) == "unix" {
+			rules.unixProxied = true
+		} else {
+			proxies = append(proxies, entry)
+		}
+	}
+	rules.proxies = parseCIDRs("trusted_proxies", proxies)
+	if len(rules.allow)+len(rules.deny) > 0 {
+		log.Printf("IP filter: %d allowed, %d denied network(s)", len(rules.allow), len(rules.deny))
+	}
+	f.rules.Store(rules)
+}
+
+func parseCIDRs(name string, entries []string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Printf("IP filter: ignoring invalid %s entry %q", name, entry)
+			continue
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+func matchAny(nets []*net.IPNet, addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *ipFilter) exempt(ip string) bool { return matchAny(f.rules.Load().allow, ip) }
+
+func (f *ipFilter) denied(ip string) bool { return matchAny(f.rules.Load().deny, ip) }
+
+// trustedProxy reports whether the connection with remote address ip comes
+// from a trusted reverse proxy.
+func (f *ipFilter) trustedProxy(ip string) bool {
+	rules := f.rules.Load()
+	if net.ParseIP(ip) == nil {
+		return rules.unixProxied
+	}
+	return matchAny(rules.proxies, ip)
+}
+
+// clientIP returns the address of the client. Behind trusted proxies it is
+// the last X-Forwarded-For entry not added by one of them.
+func (f *ipFilter) clientIP(r *http.Request) string {
+	ip := remoteIP(r)
+	if !f.trustedProxy(ip) {
+		return ip
+	}
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !f.trustedProxy(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+// remoteIP returns the address the connection of r comes from.
+func remoteIP(r *http.Request) string {
+	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+	if ip == "" {
+		ip = r.RemoteAddr
+	}
+	return ip
+}
+
+func (f *ipFilter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(f.rules.Load().deny) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if ip := f.clientIP(r); f.denied(ip) {
+			log.Printf("IP filter: denied %s on %s", ip, r.URL.Path)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Upcoming Kickoffs ---
+//
+// get_upcoming_kickoffs answers "what can I watch tonight": the matches
+// kicking off in the next few hours, soonest first, from the fixtures of
+// each day the window touches. A day whose fixtures fail to load is named in
+// the result rather than failing the call.
+
+const (
+	defaultKickoffHours = 12
+	maxKickoffHours     = 72
+)
+
+type upcomingKickoff struct {
+	MatchID   string `json:"match_id"`
+	Kickoff   string `json:"kickoff"`
+	InMinutes int    `json:"in_minutes"`
+	HomeID    string `json:"home_id,omitempty"`
+	Home      string `json:"home"`
+	AwayID    string `json:"away_id,omitempty"`
+	Away      string `json:"away"`
+	League    string `json:"league,omitempty"`
+	LeagueKey string `json:"league_key,omitempty"`
+	Country   string `json:"country,omitempty"`
+
+	at time.Time
+}
+
+// upcomingKickoffs returns the matches of data that have not started and
+// kick off between now and until.
+func upcomingKickoffs(data interface{}, now, until time.Time, league string, seen map[string]bool) []upcomingKickoff {
+	var out []upcomingKickoff
+	for _, fm := range extractMatches(data) {
+		kickoff, ok := fm.kickoff()
+		if !ok || kickoff.Before(now) || kickoff.After(until) || fm.finished() || (fm.ID != "" && seen[fm.ID]) {
+			continue
+		}
+		if league != "" && !fm.inLeague(league) {
+			continue
+		}
+		seen[fm.ID] = true
+		out = append(out, upcomingKickoff{
+			MatchID:   fm.ID,
+			Kickoff:   kickoff.Format(time.RFC3339),
+			InMinutes: int(kickoff.Sub(now).Minutes()),
+			HomeID:    fm.HomeID,
+			Home:      fm.HomeName,
+			AwayID:    fm.AwayID,
+			Away:      fm.AwayName,
+			League:    fm.LeagueName,
+			LeagueKey: fm.LeagueKey,
+			Country:   fm.Country,
+			at:        kickoff,
+		})
+	}
+	return out
+}
+
+func registerKickoffTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_upcoming_kickoffs",
+			mcp.WithDescription("Get the matches kicking off in the next few hours, soonest first, across all leagues or one, for questions like \"what can I watch tonight\". All timestamps are GMT/UTC."),
+			mcp.WithNumber("hours", mcp.Description(fmt.Sprintf("How far ahead to look, 1 to %d hours. Default: %d", maxKickoffHours, defaultKickoffHours))),
+			mcp.WithString("league", mcp.Description("Only this league, by league key or name (e.g. EnglandPremierLeague or Premier League)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args := req.Params.Arguments
+			hours := getInt(args, "hours", defaultKickoffHours)
+			if hours < 1 || hours > maxKickoffHours {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("hours must be between 1 and %d", maxKickoffHours)), nil
+			}
+			league := getStr(args, "league", "")
+			now := time.Now().UTC()
+			until := now.Add(time.Duration(hours) * time.Hour)
+
+			kickoffs := []upcomingKickoff{}
+			failed := map[string]string{}
+			seen := map[string]bool{}
+			var lastErr error
+			days := 0
+			for day := now.Truncate(24 * time.Hour); !day.After(until); day = day.AddDate(0, 0, 1) {
+				days++
+				date := day.Format("02/01/2006")
+				data, err := source.Fixtures(ctx, date, 0, queryOf(args))
+				if err != nil {
+					failed[date], lastErr = err.Error(), err
+					continue
+				}
+				kickoffs = append(kickoffs, upcomingKickoffs(data, now, until, league, seen)...)
+			}
+			if len(failed) == days {
+				return errorResult(lastErr), nil
+			}
+			sort.SliceStable(kickoffs, func(i, j int) bool { return kickoffs[i].at.Before(kickoffs[j].at) })
+			result := map[string]interface{}{"from": now.Format(time.RFC3339), "until": until.Format(time.RFC3339), "kickoffs": kickoffs}
+			if len(failed) > 0 {
+				result["errors"] = failed
+			}
+			return jsonResult(fmt.Sprintf("%d matches kicking off in the next %d hours", len(kickoffs), hours), result), nil
+		},
+	)
+}
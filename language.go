@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Session Language ---
+//
+// Upstream translates team, competition and status names into a fixed set of
+// languages and silently falls back to English for any other code, so the
+// set is listed by list_supported_languages and enforced by set_language.
+
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+type language struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Native string `json:"native_name"`
+}
+
+var supportedLanguages = []language{
+	{"en", "English", "English"},
+	{"nl", "Dutch", "Nederlands"},
+	{"de", "German", "Deutsch"},
+	{"fr", "French", "Français"},
+	{"es", "Spanish", "Español"},
+	{"pt", "Portuguese", "Português"},
+	{"it", "Italian", "Italiano"},
+}
+
+func supportedLanguage(code string) bool {
+	for _, l := range supportedLanguages {
+		if l.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (ss *sessionStore) language(owner string) string {
+	var lang string
+	ss.view(owner, func(st *sessionState) { lang = st.Language })
+	return lang
+}
+
+func (ss *sessionStore) setLanguage(owner, lang string) {
+	ss.update(owner, func(st *sessionState) bool {
+		changed := st.Language != lang
+		st.Language = lang
+		return changed
+	})
+}
+
+// languageMiddleware fills in the session's language for tool calls that do not
+// pass one explicitly.
+func (ss *sessionStore) languageMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		lang := ss.language(ss.owner(ctx))
+		if lang == "" {
+			return next(ctx, req)
+		}
+		args := req.GetArguments()
+		if getStr(args, "language", "") != "" {
+			return next(ctx, req)
+		}
+		withLang := make(map[string]any, len(args)+1)
+		for k, v := range args {
+			withLang[k] = v
+		}
+		withLang["language"] = lang
+		req.Params.Arguments = withLang
+		return next(ctx, req)
+	}
+}
+
+func registerLanguageTools(s *server.MCPServer, ss *sessionStore) {
+	// Session language
+	s.AddTool(
+		mcp.NewTool("set_language",
+			mcp.WithDescription("Set the default language for every later tool call in this session, so language does not have to be passed each time. An explicit language argument still wins."),
+			mcp.WithString("language", mcp.Required(), mcp.Description("Language code (en, nl, de, fr, es, pt, it, etc.), or 'default' to go back to en")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner := ss.owner(ctx)
+			if owner == "" {
+				return toolErrorResult(codeSessionRequired, "set_language requires an MCP session"), nil
+			}
+			lang := strings.ToLower(strings.TrimSpace(getStr(req.Params.Arguments, "language", "")))
+			if lang == "default" {
+				ss.setLanguage(owner, "")
+				return mcp.NewToolResultText(fmt.Sprintf("Session language reset to %s", defaultLang)), nil
+			}
+			if !languageCode.MatchString(lang) {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("invalid language code %q (expected e.g. en, nl, de)", lang)), nil
+			}
+			if !supportedLanguage(lang) {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("language %q is not supported upstream and would return English; see list_supported_languages", lang)), nil
+			}
+			ss.setLanguage(owner, lang)
+			return mcp.NewToolResultText(fmt.Sprintf("Session language set to %s", lang)), nil
+		},
+	)
+
+	// Supported languages
+	s.AddTool(
+		mcp.NewTool("list_supported_languages",
+			mcp.WithDescription("List the language codes upstream translates names into, with English and native names. Other codes silently return English."),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return jsonResult("Supported languages", map[string]interface{}{
+				"default":   defaultLang,
+				"languages": supportedLanguages,
+			}), nil
+		},
+	)
+}
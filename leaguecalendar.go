@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- League Calendar ---
+//
+// get_league_calendar lays out a league's season from the rounds of its
+// fixtures feed: the dates of each round, the season's first and last
+// kickoff, and the breaks between rounds of at least seasonBreak, such as
+// international breaks. A break that takes in the turn of the year is the
+// winter break. Matches the feed gives no round are left out of the rounds
+// but count for the season's start and end.
+
+const seasonBreak = 10 * 24 * time.Hour
+
+type calendarRound struct {
+	Round    string `json:"round"`
+	From     string `json:"from"`
+	Until    string `json:"until"`
+	Matches  int    `json:"matches"`
+	Finished int    `json:"finished"`
+
+	first, last time.Time
+}
+
+type seasonBreakPeriod struct {
+	Kind  string `json:"kind"` // winter or break
+	After string `json:"after_round"`
+	From  string `json:"from"`  // day after the last match before it
+	Until string `json:"until"` // day before the first match after it
+	Days  int    `json:"days"`
+}
+
+type leagueCalendar struct {
+	Season      string              `json:"season,omitempty"`
+	Start       string              `json:"season_start"`
+	End         string              `json:"season_end"`
+	Rounds      []calendarRound     `json:"rounds"`
+	Breaks      []seasonBreakPeriod `json:"breaks"`
+	WinterBreak *seasonBreakPeriod  `json:"winter_break,omitempty"`
+}
+
+// seasonCalendar builds the calendar of a league feed; ok is false when no
+// match has a kickoff time.
+func seasonCalendar(data interface{}) (leagueCalendar, bool) {
+	const day = "2006-01-02"
+	root, _ := data.(map[string]interface{})
+	cal := leagueCalendar{Season: lookupStr(root, "season"), Rounds: []calendarRound{}, Breaks: []seasonBreakPeriod{}}
+	rounds := map[string]*calendarRound{}
+	var start, end time.Time
+	seen := map[string]bool{}
+	for _, fm := range extractMatches(data) {
+		kickoff, ok := fm.kickoff()
+		if !ok || (fm.ID != "" && seen[fm.ID]) {
+			continue
+		}
+		seen[fm.ID] = true
+		if start.IsZero() || kickoff.Before(start) {
+			start = kickoff
+		}
+		end = later(end, kickoff)
+		if fm.Round == "" {
+			continue
+		}
+		r := rounds[fm.Round]
+		if r == nil {
+			r = &calendarRound{Round: fm.Round, first: kickoff}
+			rounds[fm.Round] = r
+		}
+		if kickoff.Before(r.first) {
+			r.first = kickoff
+		}
+		r.last = later(r.last, kickoff)
+		r.Matches++
+		if fm.finished() {
+			r.Finished++
+		}
+	}
+	if start.IsZero() {
+		return cal, false
+	}
+	cal.Start, cal.End = start.Format(day), end.Format(day)
+	for _, r := range rounds {
+		r.From, r.Until = r.first.Format(day), r.last.Format(day)
+		cal.Rounds = append(cal.Rounds, *r)
+	}
+	sort.Slice(cal.Rounds, func(i, j int) bool { return cal.Rounds[i].first.Before(cal.Rounds[j].first) })
+
+	for i := 1; i < len(cal.Rounds); i++ {
+		prev, next := cal.Rounds[i-1], cal.Rounds[i]
+		if next.first.Sub(prev.last) < seasonBreak {
+			continue
+		}
+		from, until := prev.last.AddDate(0, 0, 1), next.first.AddDate(0, 0, -1)
+		b := seasonBreakPeriod{
+			Kind:  "break",
+			After: prev.Round,
+			From:  from.Format(day),
+			Until: until.Format(day),
+			Days:  int(until.Truncate(24*time.Hour).Sub(from.Truncate(24*time.Hour)).Hours()/24) + 1,
+		}
+		if from.Year() != until.Year() {
+			b.Kind = "winter"
+			winter := b
+			cal.WinterBreak = &winter
+		}
+		cal.Breaks = append(cal.Breaks, b)
+	}
+	return cal, true
+}
+
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+func registerLeagueCalendarTools(s *server.MCPServer, catalog *competitionCatalog) {
+	s.AddTool(
+		mcp.NewTool("get_league_calendar",
+			mcp.WithDescription("Get a league's season calendar: the first and last match of the season, the dates of every round, and the breaks between rounds, including the winter break. All dates are GMT/UTC."),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			cal, ok := seasonCalendar(data)
+			if !ok {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no dated fixtures found for %s", key)), nil
+			}
+			return jsonResult(fmt.Sprintf("Calendar of %s from the %d rounds in its fixtures feed: %s to %s", key, len(cal.Rounds), cal.Start, cal.End), cal), nil
+		},
+	)
+}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Lineups ---
+//
+// get_lineups draws each side's starting XI as a text pitch, one row per
+// formation line from the goalkeeper up to the forwards, so "show me the
+// lineup" answers are readable at a glance. /lineup/match/{id}.svg draws
+// both sides on one pitch. Players are placed by their grid position when
+// the feed has one, otherwise by the formation and the order of the XI, and
+// as a last resort by their listed position.
+
+var (
+	lineupKeys   = []string{"lineups", "lineup", "teamlineups"}
+	startingKeys = []string{"players", "startxi", "startingxi", "starting", "starters", "lineup", "startinglineup"}
+	benchKeys    = []string{"substitutes", "subs", "bench", "reserves"}
+	shirtKeys    = []string{"number", "shirtnumber", "shirt", "no", "jersey"}
+)
+
+type lineupPlayer struct {
+	ID       string
+	Name     string
+	Number   string
+	Position string
+	Grid     string // "row:column", row 1 being the goalkeeper
+}
+
+type teamLineup struct {
+	Team      string
+	Formation string
+	Lines     [][]lineupPlayer // goalkeeper first
+	Bench     []lineupPlayer
+}
+
+// matchLineups extracts both sides' lineups from a match-detail response.
+func matchLineups(fm feedMatch) (home, away teamLineup, ok bool) {
+	home.Team, away.Team = fm.HomeName, fm.AwayName
+	var homeSide, awaySide interface{}
+	if v, found := lookup(fm.Raw, lineupKeys...); found {
+		if m, isMap := v.(map[string]interface{}); isMap {
+			homeSide, _ = lookup(m, homeKeys...)
+			awaySide, _ = lookup(m, awayKeys...)
+		}
+	}
+	// Some feeds nest the lineup in the team objects instead.
+	if homeSide == nil {
+		homeSide, _ = lookup(fm.Raw, homeKeys...)
+	}
+	if awaySide == nil {
+		awaySide, _ = lookup(fm.Raw, awayKeys...)
+	}
+	homeOK := home.parse(homeSide)
+	awayOK := away.parse(awaySide)
+	return home, away, homeOK || awayOK
+}
+
+// parse fills tl from a side given as a list of starters or an object with
+// a formation, starters and substitutes.
+func (tl *teamLineup) parse(side interface{}) bool {
+	var starters, bench interface{}
+	switch t := side.(type) {
+	case []interface{}:
+		starters = t
+	case map[string]interface{}:
+		if inner, ok := lookup(t, lineupKeys...); ok {
+			if m, isMap := inner.(map[string]interface{}); isMap {
+				t = m
+			} else {
+				starters = inner
+			}
+		}
+		tl.Formation = lookupStr(t, "formation", "tactic", "system")
+		if starters == nil {
+			starters, _ = lookup(t, startingKeys...)
+		}
+		bench, _ = lookup(t, benchKeys...)
+	}
+	xi := lineupPlayers(starters)
+	if len(xi) == 0 {
+		return false
+	}
+	tl.Lines = formationLines(xi, tl.Formation)
+	tl.Bench = lineupPlayers(bench)
+	return true
+}
+
+func lineupPlayers(v interface{}) []lineupPlayer {
+	items, _ := v.([]interface{})
+	var out []lineupPlayer
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// {"player": {...}} wrappers
+		if inner, ok := lookup(m, "player"); ok {
+			if pm, isMap := inner.(map[string]interface{}); isMap {
+				for k, v := range m {
+					if _, exists := pm[k]; !exists && normKey(k) != "player" {
+						pm[k] = v
+					}
+				}
+				m = pm
+			}
+		}
+		p := lineupPlayer{
+			ID:       lookupStr(m, "id", "playerid"),
+			Name:     lookupStr(m, "name", "playername", "player", "shortname"),
+			Number:   lookupStr(m, shirtKeys...),
+			Position: lookupStr(m, "position", "pos", "role"),
+			Grid:     lookupStr(m, "grid", "formationposition"),
+		}
+		if p.Name != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// formationLines groups the starting XI into lines, goalkeeper first.
+func formationLines(xi []lineupPlayer, formation string) [][]lineupPlayer {
+	if lines, ok := gridLines(xi); ok {
+		return lines
+	}
+	var sizes []int
+	total := 1
+	for _, f := range strings.FieldsFunc(formation, func(r rune) bool { return r < '0' || r > '9' }) {
+		n, _ := strconv.Atoi(f)
+		sizes = append(sizes, n)
+		total += n
+	}
+	if len(sizes) > 1 && total == len(xi) {
+		lines := [][]lineupPlayer{xi[:1]}
+		rest := xi[1:]
+		for _, n := range sizes {
+			lines = append(lines, rest[:n])
+			rest = rest[n:]
+		}
+		return lines
+	}
+	return positionLines(xi)
+}
+
+// gridLines places players by "row:column" grid positions, which must be
+// present for the whole XI.
+func gridLines(xi []lineupPlayer) ([][]lineupPlayer, bool) {
+	type placed struct {
+		row, col int
+		p        lineupPlayer
+	}
+	var all []placed
+	for _, p := range xi {
+		r, c, ok := strings.Cut(p.Grid, ":")
+		row, err1 := strconv.Atoi(r)
+		col, err2 := strconv.Atoi(c)
+		if !ok || err1 != nil || err2 != nil {
+			return nil, false
+		}
+		all = append(all, placed{row, col, p})
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].row != all[j].row {
+			return all[i].row < all[j].row
+		}
+		return all[i].col < all[j].col
+	})
+	var lines [][]lineupPlayer
+	for i, pl := range all {
+		if i == 0 || pl.row != all[i-1].row {
+			lines = append(lines, nil)
+		}
+		lines[len(lines)-1] = append(lines[len(lines)-1], pl.p)
+	}
+	return lines, true
+}
+
+// positionLines groups players by goalkeeper, defence, midfield and attack,
+// treating the first player as the goalkeeper when positions are missing.
+func positionLines(xi []lineupPlayer) [][]lineupPlayer {
+	lines := make([][]lineupPlayer, 4)
+	for i, p := range xi {
+		pos := strings.ToLower(p.Position)
+		switch {
+		case pos == "g" || pos == "gk" || strings.HasPrefix(pos, "goal") || (pos == "" && i == 0):
+			lines[0] = append(lines[0], p)
+		case pos == "d" || strings.HasPrefix(pos, "def") || strings.HasSuffix(pos, "b"):
+			lines[1] = append(lines[1], p)
+		case pos == "f" || pos == "a" || strings.HasPrefix(pos, "for") || strings.HasPrefix(pos, "att") ||
+			strings.HasPrefix(pos, "str") || pos == "st" || pos == "cf" || strings.HasSuffix(pos, "w"):
+			lines[3] = append(lines[3], p)
+		default:
+			lines[2] = append(lines[2], p)
+		}
+	}
+	out := lines[:0]
+	for _, l := range lines {
+		if len(l) > 0 {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// label is how a player appears on the pitch: shirt number and name,
+// without the first name when short is set.
+func (p lineupPlayer) label(short bool) string {
+	name := p.Name
+	if _, rest, ok := strings.Cut(name, " "); ok && short {
+		name = rest
+	}
+	if p.Number != "" {
+		return p.Number + " " + name
+	}
+	return name
+}
+
+// fitLabel returns the longest form of the player's label that fits width.
+func (p lineupPlayer) fitLabel(width int) string {
+	label := p.label(false)
+	if utf8.RuneCountInString(label) > width {
+		label = p.label(true)
+	}
+	if utf8.RuneCountInString(label) > width {
+		label = string([]rune(label)[:max(1, width)])
+	}
+	return label
+}
+
+const pitchWidth = 64
+
+// pitchText draws a lineup with the forwards at the top and the goalkeeper
+// at the bottom.
+func (tl teamLineup) pitchText() string {
+	var b strings.Builder
+	title := tl.Team
+	if tl.Formation != "" {
+		title += " (" + tl.Formation + ")"
+	}
+	b.WriteString(title + "\n")
+	border := "+" + strings.Repeat("-", pitchWidth) + "+\n"
+	b.WriteString(border)
+	for i := len(tl.Lines) - 1; i >= 0; i-- {
+		line := tl.Lines[i]
+		slot := pitchWidth / len(line)
+		var row strings.Builder
+		for _, p := range line {
+			label := p.fitLabel(slot - 1)
+			pad := slot - utf8.RuneCountInString(label)
+			row.WriteString(strings.Repeat(" ", pad/2) + label + strings.Repeat(" ", pad-pad/2))
+		}
+		text := row.String()
+		pad := pitchWidth - utf8.RuneCountInString(text)
+		b.WriteString("|" + strings.Repeat(" ", pad/2) + text + strings.Repeat(" ", pad-pad/2) + "|\n")
+		if i > 0 {
+			b.WriteString("|" + strings.Repeat(" ", pitchWidth) + "|\n")
+		}
+	}
+	b.WriteString(border)
+	if len(tl.Bench) > 0 {
+		var subs []string
+		for _, p := range tl.Bench {
+			subs = append(subs, p.label(false))
+		}
+		b.WriteString("Bench: " + strings.Join(subs, ", ") + "\n")
+	}
+	return b.String()
+}
+
+const (
+	svgPitchLength = 1050
+	svgPitchWidth  = 680
+	svgMargin      = 30
+)
+
+// lineupSVG draws both lineups on a horizontal pitch, home attacking left
+// to right.
+func lineupSVG(home, away teamLineup) string {
+	w, h := svgPitchLength+2*svgMargin, svgPitchWidth+2*svgMargin+50
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" font-family="sans-serif">`, w, h, w, h)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#0b3d2e"/>`, w, h)
+	x0, y0 := svgMargin, svgMargin+50
+	fmt.Fprintf(&b, `<g fill="none" stroke="#cfe8db" stroke-width="3"><rect x="%d" y="%d" width="%d" height="%d" fill="#1d6b4a"/>`, x0, y0, svgPitchLength, svgPitchWidth)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`, x0+svgPitchLength/2, y0, x0+svgPitchLength/2, y0+svgPitchWidth)
+	fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="91"/>`, x0+svgPitchLength/2, y0+svgPitchWidth/2)
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="165" height="403"/>`, x0, y0+svgPitchWidth/2-201)
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="165" height="403"/></g>`, x0+svgPitchLength-165, y0+svgPitchWidth/2-201)
+
+	header := func(tl teamLineup, x int, anchor string) {
+		title := tl.Team
+		if tl.Formation != "" {
+			title += " · " + tl.Formation
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="#fff" font-size="26" font-weight="bold" text-anchor="%s">%s</text>`, x, svgMargin+20, anchor, html.EscapeString(title))
+	}
+	header(home, x0, "start")
+	header(away, x0+svgPitchLength, "end")
+
+	side := func(tl teamLineup, fill string, mirror bool) {
+		n := len(tl.Lines)
+		for i, line := range tl.Lines {
+			// Lines spread from the goal line to just short of halfway.
+			x := 45 + i*(svgPitchLength/2-90)/max(1, n-1)
+			if mirror {
+				x = svgPitchLength - x
+			}
+			for j, p := range line {
+				y := (j + 1) * svgPitchWidth / (len(line) + 1)
+				if mirror {
+					y = svgPitchWidth - y
+				}
+				cx, cy := x0+x, y0+y
+				fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="20" fill="%s" stroke="#fff" stroke-width="2"/>`, cx, cy, fill)
+				fmt.Fprintf(&b, `<text x="%d" y="%d" fill="#fff" font-size="16" font-weight="bold" text-anchor="middle">%s</text>`, cx, cy+6, html.EscapeString(p.Number))
+				name := p
+				name.Number = ""
+				fmt.Fprintf(&b, `<text x="%d" y="%d" fill="#fff" font-size="14" text-anchor="middle">%s</text>`, cx, cy+38, html.EscapeString(name.fitLabel(18)))
+			}
+		}
+	}
+	side(home, "#c62828", false)
+	side(away, "#1565c0", true)
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+func fetchLineups(ctx context.Context, id string, args any) (home, away teamLineup, err error) {
+	data, err := source.Match(ctx, id, false, queryOf(args))
+	if err != nil {
+		return home, away, err
+	}
+	return lineupsOf(data, id)
+}
+
+// lineupsOf reads both lineups from a match page.
+func lineupsOf(data interface{}, id string) (home, away teamLineup, err error) {
+	fm, found := primaryMatch(data)
+	if !found {
+		return home, away, notFound("match %s not found", id)
+	}
+	home, away, ok := matchLineups(fm)
+	if !ok {
+		return home, away, notFound("no lineups available for match %s yet", id)
+	}
+	return home, away, nil
+}
+
+func registerLineupTools(s *server.MCPServer, publicURL string) {
+	// Starting XI as pitch diagrams
+	s.AddTool(
+		mcp.NewTool("get_lineups",
+			mcp.WithDescription(fmt.Sprintf("Get a match's starting lineups drawn as text pitch diagrams grouped by formation line, plus the bench. An SVG of both lineups is at %s/lineup/match/{id}.svg", publicURL)),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Match ID from live scores or fixtures")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "id", "")
+			if id == "" {
+				return toolErrorResult(codeInvalidArgument, "id is required"), nil
+			}
+			home, away, err := fetchLineups(ctx, id, req.Params.Arguments)
+			if err != nil {
+				return errorResult(err), nil
+			}
+			var b strings.Builder
+			fmt.Fprintf(&b, "Lineups for match %s:\n\n", id)
+			for _, tl := range []teamLineup{home, away} {
+				if len(tl.Lines) == 0 {
+					fmt.Fprintf(&b, "%s: lineup not available\n\n", tl.Team)
+					continue
+				}
+				b.WriteString("```\n" + tl.pitchText() + "```\n\n")
+			}
+			if publicURL != "" {
+				fmt.Fprintf(&b, "Pitch image: %s/lineup/match/%s.svg\n", publicURL, id)
+			}
+			return mcp.NewToolResultText(b.String()), nil
+		},
+	)
+}
+
+func registerLineupRoutes(mux *http.ServeMux, limit func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("GET /lineup/match/{file}", limit(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := strings.CutSuffix(r.PathValue("file"), ".svg")
+		if !ok || !imageIDPattern.MatchString(id) {
+			http.NotFound(w, r)
+			return
+		}
+		data, err := publicMatches.fetch(r.Context(), id)
+		if err != nil {
+			http.Error(w, "match unavailable", http.StatusBadGateway)
+			return
+		}
+		home, away, err := lineupsOf(data, id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Header().Set("Cross-Origin-Resource-Policy", "cross-origin")
+		w.Write([]byte(lineupSVG(home, away)))
+	}))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// --- Listeners ---
+//
+// listen (LISTEN, comma-separated) lists the addresses to serve on, each
+// either a TCP address (":8080", "127.0.0.1:8080") or a unix socket
+// ("unix:/run/livescore.sock"), created with socket_mode (SOCKET_MODE)
+// permissions. Without it the server listens on PORT.
+
+func listenAddrs(cfg *serverConfig, port string) []string {
+	if len(cfg.Listen) > 0 {
+		return cfg.Listen
+	}
+	return []string{":" + port}
+}
+
+func listen(addr, socketMode string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, "unix:")
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	// A socket left behind by a previous run would make Listen fail.
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	mode := os.FileMode(0o660)
+	if v := socketMode; v != "" {
+		parsed, err := strconv.ParseUint(v, 8, 32)
+		if err != nil {
+			l.Close()
+			return nil, fmt.Errorf("invalid socket mode %q", v)
+		}
+		mode = os.FileMode(parsed)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// serveAll serves srv on every address and returns when one of them fails.
+func serveAll(srv *http.Server, addrs []string, socketMode string) error {
+	errs := make(chan error, len(addrs))
+	for _, addr := range addrs {
+		l, err := listen(addr, socketMode)
+		if err != nil {
+			return fmt.Errorf("listen %s: %v", addr, err)
+		}
+		log.Printf("Listening on %s", addr)
+		go func() { errs <- srv.Serve(l) }()
+	}
+	return <-errs
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Live Match Directory ---
+//
+// liveDirectory mirrors the live feed into the resource list: every live
+// match is registered as a concrete match://{id}/live resource and removed
+// again when it leaves the feed. mcp-go sends list_changed notifications
+// whenever resources are added or deleted.
+
+type liveDirectory struct {
+	mu      sync.Mutex
+	server  *server.MCPServer
+	tracker *liveTracker
+	listed  map[string]bool // match IDs currently registered
+	updated time.Time
+}
+
+type liveDirectoryEntry struct {
+	URI    string `json:"uri"`
+	ID     string `json:"id"`
+	Home   string `json:"home"`
+	Away   string `json:"away"`
+	Score  string `json:"score,omitempty"`
+	Status string `json:"status,omitempty"`
+	League string `json:"league,omitempty"`
+}
+
+func newLiveDirectory(s *server.MCPServer, lt *liveTracker) *liveDirectory {
+	return &liveDirectory{server: s, tracker: lt, listed: make(map[string]bool)}
+}
+
+func liveMatchResourceURI(id string) string {
+	return fmt.Sprintf("match://%s/live", id)
+}
+
+func (ld *liveDirectory) register() {
+	ld.server.AddResource(
+		mcp.NewResource("livescore://live", "Live matches",
+			mcp.WithResourceDescription("Directory of currently live matches, each linking to its match://{id}/live resource"),
+			mcp.WithMIMEType("application/json"),
+		),
+		func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			data, err := json.MarshalIndent(ld.listing(), "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "application/json", Text: string(data)},
+			}, nil
+		},
+	)
+}
+
+func (ld *liveDirectory) listing() map[string]interface{} {
+	matches := ld.tracker.snapshot()
+	entries := make([]liveDirectoryEntry, 0, len(matches))
+	for _, m := range matches {
+		e := liveDirectoryEntry{
+			URI:    liveMatchResourceURI(m.ID),
+			ID:     m.ID,
+			Home:   m.HomeName,
+			Away:   m.AwayName,
+			Status: m.Status,
+			League: m.LeagueName,
+		}
+		if m.HasScore {
+			e.Score = fmt.Sprintf("%d-%d", m.HomeGoals, m.AwayGoals)
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+
+	ld.mu.Lock()
+	updated := ld.updated
+	ld.mu.Unlock()
+	return map[string]interface{}{
+		"updated": updated.Format(time.RFC3339),
+		"count":   len(entries),
+		"matches": entries,
+	}
+}
+
+// sync registers resources for matches that went live and removes those
+// that are no longer in the feed.
+func (ld *liveDirectory) sync(matches []feedMatch) {
+	current := make(map[string]feedMatch, len(matches))
+	for _, m := range matches {
+		if m.ID != "" {
+			current[m.ID] = m
+		}
+	}
+
+	ld.mu.Lock()
+	var added []server.ServerResource
+	for id, m := range current {
+		if ld.listed[id] {
+			continue
+		}
+		ld.listed[id] = true
+		added = append(added, server.ServerResource{
+			Resource: mcp.NewResource(liveMatchResourceURI(id),
+				fmt.Sprintf("%s vs %s (live)", m.HomeName, m.AwayName),
+				mcp.WithResourceDescription("Current state of a live match"),
+				mcp.WithMIMEType("application/json"),
+			),
+			Handler: liveMatchHandler(id),
+		})
+	}
+	var removed []string
+	for id := range ld.listed {
+		if _, ok := current[id]; !ok {
+			delete(ld.listed, id)
+			removed = append(removed, liveMatchResourceURI(id))
+		}
+	}
+	ld.updated = time.Now().UTC()
+	ld.mu.Unlock()
+
+	if len(added) > 0 {
+		ld.server.AddResources(added...)
+	}
+	if len(removed) > 0 {
+		ld.server.DeleteResources(removed...)
+	}
+}
+
+func liveMatchHandler(id string) server.ResourceHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return readUpstream(ctx, req.Params.URI, id, fetchMatchNoH2H)
+	}
+}
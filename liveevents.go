@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Live Event Engine ---
+//
+// liveTracker polls the live feed, keeps the previous snapshot and turns the
+// difference between two polls into discrete events. Webhooks and resource
+// subscriptions listen to it instead of polling on their own.
+
+const (
+	liveWatchInterval = 30 * time.Second
+	eventHistory      = 2 * time.Hour
+	maxEventHistory   = 2000
+)
+
+type matchEvent struct {
+	Seq        uint64    `json:"seq"`
+	Type       string    `json:"type"`
+	MatchID    string    `json:"match_id"`
+	HomeID     string    `json:"home_id,omitempty"`
+	Home       string    `json:"home"`
+	AwayID     string    `json:"away_id,omitempty"`
+	Away       string    `json:"away"`
+	Score      string    `json:"score"`
+	Status     string    `json:"status,omitempty"`
+	PrevStatus string    `json:"prev_status,omitempty"`
+	LeagueKey  string    `json:"league_key,omitempty"`
+	Detail     string    `json:"detail,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+type matchState struct {
+	match   feedMatch
+	yellow  int
+	red     int
+	reviews []feedEvent // events with a VAR decision
+}
+
+type liveTracker struct {
+	mu        sync.Mutex
+	states    map[string]matchState
+	polled    bool
+	seq       uint64
+	events    []matchEvent
+	listeners []func([]matchEvent)
+	pollHooks []func([]feedMatch)
+	lastPoll  time.Time
+	interval  atomic.Int64 // poll interval, a time.Duration
+}
+
+func newLiveTracker(interval time.Duration) *liveTracker {
+	lt := &liveTracker{states: make(map[string]matchState)}
+	lt.setInterval(interval)
+	return lt
+}
+
+func (lt *liveTracker) setInterval(d time.Duration) { lt.interval.Store(int64(d)) }
+
+// onEvents registers fn to be called with the events of every poll that
+// produced any. It must be called before run.
+func (lt *liveTracker) onEvents(fn func([]matchEvent)) {
+	lt.listeners = append(lt.listeners, fn)
+}
+
+// onPoll registers fn to be called with the full set of live matches after
+// every successful poll. It must be called before run.
+func (lt *liveTracker) onPoll(fn func([]feedMatch)) {
+	lt.pollHooks = append(lt.pollHooks, fn)
+}
+
+// snapshot returns the live matches seen by the latest poll.
+func (lt *liveTracker) snapshot() []feedMatch {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	out := make([]feedMatch, 0, len(lt.states))
+	for _, st := range lt.states {
+		out = append(out, st.match)
+	}
+	return out
+}
+
+// stats reports the number of live matches, the time of the last successful
+// poll and the number of buffered events.
+func (lt *liveTracker) stats() (live int, lastPoll time.Time, events int) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	return len(lt.states), lt.lastPoll, len(lt.events)
+}
+
+func (lt *liveTracker) run() {
+	for {
+		lt.poll()
+		time.Sleep(time.Duration(lt.interval.Load()))
+	}
+}
+
+func (lt *liveTracker) poll() {
+	data, err := source.LiveScores(context.Background(), queryOf(nil))
+	if err != nil {
+		log.Printf("Live events: feed error: %v", err)
+		return
+	}
+	matches := extractMatches(data)
+	events := lt.update(matches, time.Now().UTC())
+	for _, fn := range lt.pollHooks {
+		fn(matches)
+	}
+	if len(events) == 0 {
+		return
+	}
+	for _, fn := range lt.listeners {
+		fn(events)
+	}
+}
+
+// update replaces the snapshot with matches and returns the derived events.
+func (lt *liveTracker) update(matches []feedMatch, now time.Time) []matchEvent {
+	next := make(map[string]matchState, len(matches))
+	for _, m := range matches {
+		if m.ID == "" {
+			continue
+		}
+		st := matchState{match: m}
+		st.yellow, st.red = m.cardCounts()
+		for _, e := range m.events() {
+			if e.VAR != "" {
+				st.reviews = append(st.reviews, e)
+			}
+		}
+		next[m.ID] = st
+	}
+
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	prev, first := lt.states, !lt.polled
+	lt.states, lt.polled, lt.lastPoll = next, true, now
+	if first {
+		return nil
+	}
+
+	var events []matchEvent
+	emit := func(m feedMatch, typ, prevStatus, detail string) {
+		lt.seq++
+		events = append(events, matchEvent{
+			Seq:        lt.seq,
+			Type:       typ,
+			MatchID:    m.ID,
+			HomeID:     m.HomeID,
+			Home:       m.HomeName,
+			AwayID:     m.AwayID,
+			Away:       m.AwayName,
+			Score:      fmt.Sprintf("%d-%d", m.HomeGoals, m.AwayGoals),
+			Status:     m.Status,
+			PrevStatus: prevStatus,
+			LeagueKey:  m.LeagueKey,
+			Detail:     detail,
+			Time:       now,
+		})
+	}
+
+	for id, cur := range next {
+		m := cur.match
+		old, seen := prev[id]
+		if !seen {
+			emit(m, "kickoff", "", "")
+			continue
+		}
+		o := old.match
+		switch {
+		case m.HomeGoals > o.HomeGoals || m.AwayGoals > o.AwayGoals:
+			emit(m, "goal", "", scorerDetail(m, o))
+		case m.HomeGoals < o.HomeGoals || m.AwayGoals < o.AwayGoals:
+			emit(m, "score_correction", "", fmt.Sprintf("was %d-%d", o.HomeGoals, o.AwayGoals))
+		}
+		if cur.red > old.red {
+			emit(m, "red_card", "", fmt.Sprintf("%d red card(s) in match", cur.red))
+		}
+		if cur.yellow > old.yellow {
+			emit(m, "yellow_card", "", fmt.Sprintf("%d yellow card(s) in match", cur.yellow))
+		}
+		for _, e := range cur.reviews[min(len(old.reviews), len(cur.reviews)):] {
+			emit(m, "var_decision", "", varDetail(e))
+		}
+		if m.Status != o.Status {
+			if m.finished() && !o.finished() {
+				emit(m, "full_time", o.Status, "")
+			} else {
+				emit(m, "status_change", o.Status, "")
+			}
+		}
+	}
+	// Matches drop out of the live feed once they are over.
+	for id, old := range prev {
+		if _, still := next[id]; !still && !old.match.finished() {
+			emit(old.match, "full_time", old.match.Status, "left live feed")
+		}
+	}
+
+	lt.events = append(lt.events, events...)
+	cutoff := now.Add(-eventHistory)
+	drop := 0
+	for drop < len(lt.events) && (lt.events[drop].Time.Before(cutoff) || len(lt.events)-drop > maxEventHistory) {
+		drop++
+	}
+	lt.events = append([]matchEvent(nil), lt.events[drop:]...)
+	return events
+}
+
+// scorerDetail names the latest goal scorer when the feed carries events.
+func scorerDetail(m, prev feedMatch) string {
+	side := "home"
+	if m.AwayGoals > prev.AwayGoals {
+		side = "away"
+	}
+	evs := m.events()
+	for i := len(evs) - 1; i >= 0; i-- {
+		if strings.Contains(evs[i].Type, "goal") && evs[i].Player != "" {
+			return fmt.Sprintf("%s goal by %s (%s')", side, evs[i].Player, evs[i].Minute)
+		}
+	}
+	return side + " goal"
+}
+
+// recent returns events newer than since that satisfy keep, oldest first.
+func (lt *liveTracker) recent(since time.Time, keep func(matchEvent) bool) []matchEvent {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	out := make([]matchEvent, 0)
+	for _, e := range lt.events {
+		if e.Time.After(since) && keep(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// matchChange is the latest state of a match whose score or status changed.
+type matchChange struct {
+	MatchID   string   `json:"match_id"`
+	Home      string   `json:"home"`
+	Away      string   `json:"away"`
+	Score     string   `json:"score"`
+	Status    string   `json:"status,omitempty"`
+	LeagueKey string   `json:"league_key,omitempty"`
+	Live      bool     `json:"live"`
+	Changes   []string `json:"changes,omitempty"` // event types since the cursor
+}
+
+// changesSince returns the matches whose score or status changed after event
+// sequence number cursor, and the cursor to pass next time. Without a cursor,
+// or when it is unknown or older than the buffered events, every live match
+// is returned and reset is set.
+func (lt *liveTracker) changesSince(cursor uint64, hasCursor bool) (changes []matchChange, next uint64, reset bool) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	next = lt.seq
+	reset = !hasCursor || cursor > lt.seq ||
+		(cursor < lt.seq && (len(lt.events) == 0 || lt.events[0].Seq > cursor+1))
+
+	current := func(m feedMatch) matchChange {
+		return matchChange{
+			MatchID:   m.ID,
+			Home:      m.HomeName,
+			Away:      m.AwayName,
+			Score:     fmt.Sprintf("%d-%d", m.HomeGoals, m.AwayGoals),
+			Status:    m.Status,
+			LeagueKey: m.LeagueKey,
+			Live:      !m.finished(),
+		}
+	}
+	changes = make([]matchChange, 0)
+	if reset {
+		for _, st := range lt.states {
+			changes = append(changes, current(st.match))
+		}
+		sort.Slice(changes, func(i, j int) bool { return changes[i].MatchID < changes[j].MatchID })
+		return changes, next, true
+	}
+
+	index := map[string]int{}
+	for _, e := range lt.events {
+		if e.Seq <= cursor {
+			continue
+		}
+		switch e.Type {
+		case "kickoff", "goal", "score_correction", "status_change", "full_time":
+		default:
+			continue
+		}
+		i, ok := index[e.MatchID]
+		if !ok {
+			c := matchChange{MatchID: e.MatchID, Home: e.Home, Away: e.Away, Score: e.Score, Status: e.Status, LeagueKey: e.LeagueKey}
+			if st, live := lt.states[e.MatchID]; live {
+				c = current(st.match)
+			}
+			i = len(changes)
+			index[e.MatchID] = i
+			changes = append(changes, c)
+		}
+		changes[i].Changes = append(changes[i].Changes, e.Type)
+	}
+	return changes, next, false
+}
+
+func (e matchEvent) involvesTeam(id string) bool {
+	return id != "" && (e.HomeID == id || e.AwayID == id)
+}
+
+func registerLiveEventTools(s *server.MCPServer, lt *liveTracker) {
+	// Recent events
+	s.AddTool(
+		mcp.NewTool("get_recent_events",
+			mcp.WithDescription("Get goals, cards, VAR decisions, kickoffs, status changes and full-time results detected in live matches over the recent past (up to 2 hours). All timestamps are GMT/UTC."),
+			mcp.WithNumber("since_minutes", mcp.Description("How far back to look, in minutes. Default: 15")),
+			mcp.WithString("match_id", mcp.Description("Only events for this match")),
+			mcp.WithString("team_id", mcp.Description("Only events for matches involving this team")),
+			mcp.WithString("league_key", mcp.Description("Only events in this league")),
+			mcp.WithString("type", mcp.Description("Only this event type: kickoff, goal, score_correction, red_card, yellow_card, var_decision, status_change, full_time")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args := req.Params.Arguments
+			since := time.Now().Add(-time.Duration(getInt(args, "since_minutes", 15)) * time.Minute)
+			matchID := getStr(args, "match_id", "")
+			teamID := getStr(args, "team_id", "")
+			leagueKey := getStr(args, "league_key", "")
+			typ := getStr(args, "type", "")
+
+			events := lt.recent(since, func(e matchEvent) bool {
+				return (matchID == "" || e.MatchID == matchID) &&
+					(teamID == "" || e.involvesTeam(teamID)) &&
+					(leagueKey == "" || strings.EqualFold(e.LeagueKey, leagueKey)) &&
+					(typ == "" || e.Type == typ)
+			})
+			return jsonResult(fmt.Sprintf("Recent live events (%d)", len(events)), events), nil
+		},
+	)
+
+	// Incremental changes
+	s.AddTool(
+		mcp.NewTool("get_changes_since",
+			mcp.WithDescription("Get only the live matches whose score or status changed since a cursor, plus the cursor for the next call. Call without a cursor first to get every live match; polling with the returned cursor transfers a fraction of get_live_scores."),
+			mcp.WithString("cursor", mcp.Description("Cursor from the previous call. Omit to start over")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var cursor uint64
+			c := getStr(req.Params.Arguments, "cursor", "")
+			if c != "" {
+				n, err := strconv.ParseUint(c, 10, 64)
+				if err != nil {
+					return toolErrorResult(codeInvalidArgument, fmt.Sprintf("invalid cursor %q", c)), nil
+				}
+				cursor = n
+			}
+			changes, next, reset := lt.changesSince(cursor, c != "")
+			return jsonResult(fmt.Sprintf("Changed matches (%d)", len(changes)), map[string]interface{}{
+				"cursor":  strconv.FormatUint(next, 10),
+				"reset":   reset,
+				"changes": changes,
+			}), nil
+		},
+	)
+}
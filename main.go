@@ -2,15 +2,16 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
 	"embed"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net"
 	"net/http"
 	"net/url"
-	"os"
 	"strconv"
 	"sync"
 	"time"
@@ -24,41 +25,223 @@ import (
 var staticFiles embed.FS
 
 const (
-	baseURL        = "https://uitslagen.live/footapi"
+	defaultBaseURL = "https://uitslagen.live/footapi"
 	defaultLang    = "en"
 	defaultVersion = 2800
 	serverName     = "livescore-mcp"
 	serverVersion  = "1.0.0"
 )
 
-func main() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-
-	publicURL := os.Getenv("PUBLIC_URL")
-	if publicURL == "" {
-		publicURL = fmt.Sprintf("http://localhost:%s", port)
-	}
+// baseURL is the upstream API root, overridable with upstream_url or
+// UPSTREAM_URL.
+var baseURL = defaultBaseURL
+
+// serverInstructions is sent in the initialize response so models know how
+// the tools fit together before their first call.
+const serverInstructions = `LiveScore MCP serves football data: live scores, fixtures, teams, players and matches.
+
+Finding IDs:
+- Most tools take numeric IDs. Resolve names first: search (q=team, player or competition name) -> get_team (team_id) -> get_match (match_id from the team's fixtures or from get_live_scores / get_day_fixtures).
+- League keys are CamelCase country + competition, e.g. NetherlandsEredivisie, EnglandPremierLeague. Use the key as returned by search or listed in the livescore://competitions resource; case does not matter, and an unknown key fails with INVALID_LEAGUE_KEY and the closest known keys as suggestions.
+- get_fixtures takes a competition file id such as EurocupsUEFAChampionsLeague_small.
+
+Formats:
+- Dates are DD/MM/YYYY (e.g. 25/12/2025). get_day_fixtures accepts an end_date for ranges of up to 15 days.
+- All timestamps are GMT/UTC; convert to the user's timezone when presenting them.
+- language takes a short code (en, nl, de, fr, es, pt, it; list_supported_languages has the full list). Other codes silently return English. Default: en, or the session language chosen with set_language.
+
+Live data:
+- Use get_live_scores for a snapshot, get_recent_events for what changed recently, and subscribe to match://{id}/live for push updates.
+
+Errors:
+- Failed tool calls carry structuredContent {"error": {"code", "message", "remediation"}}. Codes: INVALID_ARGUMENT, INVALID_LEAGUE_KEY, NOT_FOUND, SESSION_REQUIRED, RATE_LIMITED. Fix the arguments, or wait before retrying RATE_LIMITED.
+- Server-side failures are JSON-RPC errors (-32603) whose message starts with UPSTREAM_TIMEOUT, UPSTREAM_ERROR or INTERNAL_ERROR; they are not caused by the arguments and are worth retrying later.`
+
+// app is the MCP server together with the subsystems its tools rely on. The
+// HTTP server and the stdio transport share it.
+type app struct {
+	mcp      *server.MCPServer
+	sessions *sessionStore
+	keys     *keyRing
+	health   *healthChecker
+	subs     *subscriptionManager
+	tracker  *liveTracker
+	feeds    *resultFeeds
+	images   *imageCache
+	sse      *sseGuard
+	catalog  *competitionCatalog
+	ips      *ipFilter
+	rl       *rateLimiter
+	rateCfg  rateLimitConfig
+}
 
+// newApp builds the MCP server and registers every tool, resource and
+// prompt. Background polling starts with start.
+func newApp(cfg *serverConfig, publicURL string) *app {
+	hooks := &server.Hooks{}
+	sessions := newSessionStore(cfg.SessionStateFile, orDefault(cfg.Cache.SessionIdle, defaultSessionIdleTTL))
 	s := server.NewMCPServer(
 		serverName,
 		serverVersion,
 		server.WithToolCapabilities(true),
-		server.WithResourceCapabilities(true, false),
+		server.WithResourceCapabilities(true, true),
+		server.WithPromptCapabilities(false),
+		server.WithHooks(hooks),
+		server.WithInstructions(serverInstructions),
+		server.WithToolHandlerMiddleware(sessions.languageMiddleware),
+		server.WithToolHandlerMiddleware(structuredErrors),
 	)
 
-	registerTools(s)
+	keys := loadAPIKeys(cfg)
+	keys.oauth = newOAuthVerifier(cfg.OAuth, publicURL)
+	sessions.keys = keys
+	health := &healthChecker{started: time.Now(), store: sessions}
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		health.sessions.Add(1)
+		if k, ok := apiKeyFromContext(ctx); ok {
+			keys.bind(session.SessionID(), k)
+		}
+	})
+	subs := newSubscriptionManager(s)
+	webhooks := newWebhookManager()
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		health.sessions.Add(-1)
+		subs.dropSession(session.SessionID())
+		webhooks.dropSession(session.SessionID())
+		sessions.drop(session.SessionID())
+		keys.unbind(session.SessionID())
+	})
+
+	tracker := newLiveTracker(orDefault(cfg.Cache.LivePoll, liveWatchInterval))
+	tracker.onEvents(subs.handle)
+	tracker.onEvents(webhooks.handle)
+	feeds := newResultFeeds()
+	tracker.onEvents(feeds.handle)
+	liveDir := newLiveDirectory(s, tracker)
+	tracker.onPoll(liveDir.sync)
+	ticker := newLiveTicker()
+	tracker.onPoll(ticker.update)
+
+	catalog := newCompetitionCatalog(orDefault(cfg.Cache.Competitions, catalogRefresh))
+	hotFeeds.setInterval(orDefault(cfg.Cache.Prefetch, prefetchInterval))
+
+	ips := newIPFilter(cfg)
+	rateCfg := newRateLimitConfig(cfg)
+	rl := newRateLimiter(rateCfg, keys, ips)
+	health.tracker, health.catalog, health.limiter = tracker, catalog, rl
+
+	registerTools(s, health, catalog, publicURL)
+	registerFavoriteTools(s, sessions)
+	registerWebhookTools(s, webhooks)
+	registerLanguageTools(s, sessions)
+	registerQuotaTools(s, rl)
+	registerLiveEventTools(s, tracker)
+	registerCalendarTools(s, publicURL)
+	registerLineupTools(s, publicURL)
+	registerNationalTeamTools(s)
+	registerPlayerTools(s)
+	registerValueTools(s)
+	registerSquadTools(s)
+	registerMilestoneTools(s)
+	registerHonoursTools(s)
+	registerManagerTools(s)
+	registerDisciplineTools(s, catalog)
+	registerTopScorerTools(s, catalog)
+	registerDefenceTools(s, catalog)
+	registerMatchStatsTools(s)
+	registerTrendTools(s)
+	registerAttendanceTools(s, catalog)
+	registerVenueTools(s)
+	registerRivalryTools(s)
+	registerCongestionTools(s)
+	registerRunInTools(s, catalog)
+	registerWhatIfTools(s, catalog)
+	registerStreakTools(s, catalog)
+	registerRecordTools(s, catalog)
+	registerLeagueCalendarTools(s, catalog)
+	registerDrawTools(s, catalog)
+	registerGoalTools(s)
+	registerRedCardTools(s)
+	registerKickoffTools(s)
+	registerBulkTools(s)
+	registerSports(s, cfg.Sports)
 	registerResources(s)
+	registerLiveMatchResources(s)
+	liveDir.register()
+	ticker.register(s)
+	registerCompetitionResources(s, catalog)
+	registerPrompts(s)
+
+	return &app{
+		mcp:      s,
+		sessions: sessions,
+		keys:     keys,
+		health:   health,
+		subs:     subs,
+		tracker:  tracker,
+		feeds:    feeds,
+		images:   newImageCache(),
+		sse:      newSSEGuard(cfg, ips),
+		catalog:  catalog,
+		ips:      ips,
+		rl:       rl,
+		rateCfg:  rateCfg,
+	}
+}
+
+// start launches the background loops: session expiry, the live feed poller
+// and the competitions catalog.
+func (a *app) start() {
+	go a.sessions.run()
+	go a.tracker.run()
+	go a.catalog.run()
+	go hotFeeds.run()
+	go a.sse.run()
+	if fs, ok := source.(*failoverSource); ok {
+		go fs.run()
+	}
+}
+
+// reload applies a reloaded configuration (see watchConfig).
+func (a *app) reload(cfg *serverConfig) {
+	a.rl.reconfigure(newRateLimitConfig(cfg))
+	a.sse.reconfigure(cfg)
+	a.keys.reload(cfg)
+	a.tracker.setInterval(orDefault(cfg.Cache.LivePoll, liveWatchInterval))
+	a.catalog.setInterval(orDefault(cfg.Cache.Competitions, catalogRefresh))
+	hotFeeds.setInterval(orDefault(cfg.Cache.Prefetch, prefetchInterval))
+	a.sessions.setIdleTTL(orDefault(cfg.Cache.SessionIdle, defaultSessionIdleTTL))
+	a.ips.reconfigure(cfg)
+	log.Printf("Config: reloaded rate limits, API keys, cache TTLs, SSE settings and IP filter")
+}
+
+// serve runs the HTTP server with the SSE transport, the landing page and
+// the operational endpoints.
+func serve(cfg *serverConfig, configPath string) {
+	port := cfg.Port
+	if port == "" {
+		port = "8080"
+	}
+
+	domains := cfg.TLS.Domains
+	publicURL := cfg.PublicURL
+	if publicURL == "" {
+		publicURL = fmt.Sprintf("http://localhost:%s", port)
+		if len(domains) > 0 {
+			publicURL = "https://" + domains[0]
+		}
+	}
+
+	a := newApp(cfg, publicURL)
+	a.start()
+	go watchConfig(configPath, a.reload)
+	s, keys, rl, subs, health := a.mcp, a.keys, a.rl, a.subs, a.health
 
 	sseServer := server.NewSSEServer(s,
 		server.WithBaseURL(publicURL),
+		server.WithSessionIDGenerator(a.sse.newSessionID),
 	)
 
-	// 30 requests/min per IP, burst of 10
-	rl := newRateLimiter(rate.Every(2*time.Second), 10)
-
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/" || r.URL.Path == "" {
@@ -67,11 +250,46 @@ func main() {
 		}
 		sseServer.ServeHTTP(w, r)
 	})
-	mux.HandleFunc("/sse", sseServer.ServeHTTP)
-	mux.HandleFunc("/message", rl.middleware(sseServer.ServeHTTP))
+	mux.HandleFunc("/sse", keys.middleware(a.sse.middleware(sseServer.ServeHTTP)))
+	mux.HandleFunc("/message", a.sse.touch(keys.middleware(rl.middleware(subs.middleware(sseServer, sseServer.ServeHTTP)))))
+	registerRESTRoutes(mux, s, publicURL, func(next http.HandlerFunc) http.HandlerFunc {
+		return keys.middleware(rl.middleware(next))
+	})
+	schema, err := newGraphQLSchema()
+	if err != nil {
+		log.Fatalf("GraphQL: %v", err)
+	}
+	mux.HandleFunc("/graphql", keys.middleware(rl.middleware(graphqlHandler(schema))))
+	registerCalendarRoutes(mux, publicURL, rl.public)
+	registerFeedRoutes(mux, a.feeds, publicURL, rl.public)
+	registerImageRoutes(mux, a.images, rl.public)
+	registerOGRoutes(mux, a.images, publicURL, rl.public)
+	registerWidgetRoutes(mux, a.tracker, publicURL, rl.public)
+	registerLineupRoutes(mux, rl.public)
+	if keys.oauth != nil {
+		mux.HandleFunc(oauthMetadataPath, keys.oauth.metadataHandler)
+	}
+	if cfg.AdminToken != "" {
+		registerAdminRoutes(mux, cfg.AdminToken, keys, a.rateCfg.tiers)
+	}
+	if cfg.PprofAddr != "" {
+		go servePprof(cfg.PprofAddr)
+	}
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"status":"ok","server":"livescore-mcp","version":"1.0.0"}`))
+		if r.URL.Query().Get("deep") == "true" {
+			full := cfg.AdminToken != "" && subtle.ConstantTimeCompare([]byte(presentedKey(r)), []byte(cfg.AdminToken)) == 1
+			report, ok := health.deep(r.Context(), full)
+			status := http.StatusOK
+			if !ok {
+				status = http.StatusServiceUnavailable
+			}
+			writeJSON(w, status, report)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "server": "livescore-mcp", "version": currentBuild().Version})
+	})
+	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, currentBuild())
 	})
 	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
@@ -96,10 +314,26 @@ func main() {
 		fmt.Fprint(w, termsHTML)
 	})
 
-	handler := securityHeaders(mux)
+	handler := a.ips.middleware(securityHeaders(compress(mux)))
 
-	log.Printf("LiveScore MCP Server %s starting on :%s", serverVersion, port)
-	if err := (&http.Server{Addr: ":" + port, Handler: handler}).ListenAndServe(); err != nil {
+	srv := &http.Server{Addr: ":" + port, Handler: handler, Protocols: new(http.Protocols)}
+	// HTTP/2 lets many SSE streams share one connection. It is negotiated
+	// automatically over TLS; H2C=true also accepts cleartext HTTP/2 for
+	// internal deployments behind a proxy that speaks h2c.
+	srv.Protocols.SetHTTP1(true)
+	srv.Protocols.SetHTTP2(true)
+	if cfg.H2C {
+		srv.Protocols.SetUnencryptedHTTP2(true)
+	}
+	log.Printf("Build: %s", currentBuild())
+	if len(domains) > 0 {
+		log.Printf("LiveScore MCP Server %s starting with TLS", serverVersion)
+		err = listenAndServeAutocert(srv, cfg.TLS)
+	} else {
+		log.Printf("LiveScore MCP Server %s starting", serverVersion)
+		err = serveAll(srv, listenAddrs(cfg, port), cfg.SocketMode)
+	}
+	if err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
@@ -123,48 +357,135 @@ func securityHeaders(next http.Handler) http.Handler {
 
 // --- Rate Limiter ---
 
+const (
+	tierAnonymous  = "anonymous"
+	tierFree       = "free"
+	tierCommercial = "commercial"
+	tierExempt     = "exempt" // ALLOW_CIDRS, not rate limited
+)
+
+type rateTier struct {
+	rate  rate.Limit
+	burst int
+}
+
+// defaultRateTiers: anonymous clients get 30 requests/min per IP, API keys
+// get a per-key limit depending on their tier.
+var defaultRateTiers = map[string]rateTier{
+	tierAnonymous:  {rate: rate.Every(2 * time.Second), burst: 10},
+	tierFree:       {rate: rate.Every(time.Second), burst: 20},
+	tierCommercial: {rate: rate.Every(100 * time.Millisecond), burst: 100},
+}
+
+type rateLimitConfig struct {
+	tiers           map[string]rateTier
+	cleanupInterval time.Duration // how often idle visitors are dropped
+	visitorTTL      time.Duration // idle time after which a visitor is dropped
+}
+
+// newRateLimitConfig starts from the defaults and applies the rate limits of
+// cfg (the config file and RATE_LIMIT_<TIER>, RATE_BURST_<TIER>,
+// RATE_LIMIT_CLEANUP_INTERVAL and RATE_LIMIT_VISITOR_TTL).
+func newRateLimitConfig(cfg *serverConfig) rateLimitConfig {
+	rc := rateLimitConfig{
+		tiers:           make(map[string]rateTier, len(defaultRateTiers)),
+		cleanupInterval: orDefault(cfg.RateLimitCleanup, 5*time.Minute),
+		visitorTTL:      orDefault(cfg.RateLimitVisitorTTL, 10*time.Minute),
+	}
+	for name, t := range defaultRateTiers {
+		if l, ok := cfg.RateLimits[name]; ok {
+			if l.PerMinute > 0 {
+				t.rate = rate.Limit(l.PerMinute / 60)
+			}
+			if l.Burst > 0 {
+				t.burst = l.Burst
+			}
+		}
+		rc.tiers[name] = t
+	}
+	return rc
+}
+
 type ipLimiter struct {
 	limiter  *rate.Limiter
+	tier     string
 	lastSeen time.Time
 }
 
 type rateLimiter struct {
 	mu       sync.Mutex
 	visitors map[string]*ipLimiter
-	rate     rate.Limit
-	burst    int
+	tiers    map[string]rateTier
+	cleanup  time.Duration
+	ttl      time.Duration
+	keys     *keyRing
+	ips      *ipFilter
+	usage    *usageMeter
 }
 
-func newRateLimiter(r rate.Limit, burst int) *rateLimiter {
+func newRateLimiter(cfg rateLimitConfig, keys *keyRing, ips *ipFilter) *rateLimiter {
 	rl := &rateLimiter{
 		visitors: make(map[string]*ipLimiter),
-		rate:     r,
-		burst:    burst,
+		tiers:    cfg.tiers,
+		cleanup:  cfg.cleanupInterval,
+		ttl:      cfg.visitorTTL,
+		keys:     keys,
+		ips:      ips,
+		usage:    newUsageMeter(),
 	}
-	go rl.cleanup()
+	go rl.evictIdle()
 	return rl
 }
 
-func (rl *rateLimiter) getLimiter(ip string) *rate.Limiter {
+// reconfigure applies new limits, including to clients already being
+// tracked, whose remaining allowance is kept.
+func (rl *rateLimiter) reconfigure(cfg rateLimitConfig) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.tiers, rl.cleanup, rl.ttl = cfg.tiers, cfg.cleanupInterval, cfg.visitorTTL
+	for _, v := range rl.visitors {
+		t, ok := rl.tiers[v.tier]
+		if !ok {
+			t = rl.tiers[tierAnonymous]
+		}
+		v.limiter.SetLimit(t.rate)
+		v.limiter.SetBurst(t.burst)
+	}
+}
+
+func (rl *rateLimiter) getLimiter(id, tier string) *rate.Limiter {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	v, exists := rl.visitors[ip]
-	if !exists {
-		limiter := rate.NewLimiter(rl.rate, rl.burst)
-		rl.visitors[ip] = &ipLimiter{limiter: limiter, lastSeen: time.Now()}
+	v, exists := rl.visitors[id]
+	if !exists || v.tier != tier {
+		t, ok := rl.tiers[tier]
+		if !ok {
+			t = rl.tiers[tierAnonymous]
+		}
+		limiter := rate.NewLimiter(t.rate, t.burst)
+		rl.visitors[id] = &ipLimiter{limiter: limiter, tier: tier, lastSeen: time.Now()}
 		return limiter
 	}
 	v.lastSeen = time.Now()
 	return v.limiter
 }
 
-func (rl *rateLimiter) cleanup() {
+func (rl *rateLimiter) visitorCount() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return len(rl.visitors)
+}
+
+func (rl *rateLimiter) evictIdle() {
 	for {
-		time.Sleep(5 * time.Minute)
+		rl.mu.Lock()
+		cleanup := rl.cleanup
+		rl.mu.Unlock()
+		time.Sleep(cleanup)
 		rl.mu.Lock()
 		for ip, v := range rl.visitors {
-			if time.Since(v.lastSeen) > 10*time.Minute {
+			if time.Since(v.lastSeen) > rl.ttl {
 				delete(rl.visitors, ip)
 			}
 		}
@@ -172,34 +493,89 @@ func (rl *rateLimiter) cleanup() {
 	}
 }
 
+// bucketState returns the whole requests left in the limiter's bucket and
+// when it will be full again.
+func bucketState(l *rate.Limiter, now time.Time) (remaining int, full time.Time) {
+	tokens := l.TokensAt(now)
+	full = now
+	if missing := float64(l.Burst()) - tokens; missing > 0 && l.Limit() > 0 {
+		full = now.Add(time.Duration(missing / float64(l.Limit()) * float64(time.Second)))
+	}
+	return int(math.Max(0, math.Floor(tokens))), full
+}
+
+// retryAfter returns the whole seconds until the limiter allows a request.
+func retryAfter(l *rate.Limiter, now time.Time) int {
+	missing := 1 - l.TokensAt(now)
+	if missing <= 0 || l.Limit() <= 0 {
+		return 1
+	}
+	return int(math.Ceil(missing / float64(l.Limit())))
+}
+
 func (rl *rateLimiter) middleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ip, _, _ := net.SplitHostPort(r.RemoteAddr)
-		if ip == "" {
-			ip = r.RemoteAddr
+		ip := rl.ips.clientIP(r)
+		if rl.ips.exempt(ip) {
+			rl.usage.record(ip, true)
+			next(w, r.WithContext(context.WithValue(r.Context(), rateClientKey{}, rateClient{ID: ip, Name: ip, Tier: tierExempt})))
+			return
+		}
+		// Keyed clients share one limit wherever they connect from.
+		c := rateClient{ID: ip, Name: ip, Tier: tierAnonymous}
+		if k, ok := rl.keys.client(r); ok {
+			c = rateClient{ID: k.id(), Name: k.Name, Tier: k.Tier}
 		}
-		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
-			ip = fwd
+		if rl.allow(w, r, c.ID, c.Tier) {
+			next(w, r.WithContext(context.WithValue(r.Context(), rateClientKey{}, c)))
 		}
+	}
+}
 
-		limiter := rl.getLimiter(ip)
-		if !limiter.Allow() {
-			log.Printf("Rate limit exceeded for %s on %s", ip, r.URL.Path)
-			w.Header().Set("Content-Type", "application/json")
-			w.Header().Set("Retry-After", "60")
-			w.WriteHeader(http.StatusTooManyRequests)
-			w.Write([]byte(`{"error":"rate limit exceeded","retry_after":60}`))
-			return
+// public limits the keyless public pages (widgets, share images, logos, lineup
+// images, calendars, result feeds) per IP at the anonymous tier, in a bucket apart from
+// the IP's MCP requests so an embed cannot use up a client's allowance.
+func (rl *rateLimiter) public(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ip := rl.ips.clientIP(r)
+		if rl.ips.exempt(ip) || rl.allow(w, r, "public:"+ip, tierAnonymous) {
+			next(w, r)
 		}
-		next(w, r)
 	}
 }
 
+// allow takes a request from the bucket of id and sets the rate limit
+// headers; when the bucket is empty it writes the 429 response and returns
+// false.
+func (rl *rateLimiter) allow(w http.ResponseWriter, r *http.Request, id, tier string) bool {
+	limiter := rl.getLimiter(id, tier)
+	now := time.Now()
+	allowed := limiter.AllowN(now, 1)
+	rl.usage.record(id, allowed)
+
+	remaining, full := bucketState(limiter, now)
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(int(math.Round(float64(limiter.Limit())*60))))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(full.Unix(), 10))
+	if !allowed {
+		retry := retryAfter(limiter, now)
+		log.Printf("Rate limit exceeded for %s on %s", rl.ips.clientIP(r), r.URL.Path)
+		h.Set("Content-Type", "application/json")
+		h.Set("Retry-After", strconv.Itoa(retry))
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprintf(w, `{"error":"rate limit exceeded","code":"RATE_LIMITED","retry_after":%d}`, retry)
+	}
+	return allowed
+}
+
 const robotsTxt = `User-agent: *
 Allow: /
 Disallow: /sse
 Disallow: /message
 Disallow: /health
+Disallow: /admin/
+Disallow: /debug/
 
 Sitemap: https://livescoremcp.com/sitemap.xml
 `
@@ -1451,8 +1827,26 @@ func getInt(args any, key string, fallback int) int {
 	return fallback
 }
 
+// templateArg reads a variable matched from a resource URI template.
+func templateArg(args map[string]any, key string) string {
+	switch v := args[key].(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
 func buildURL(path string, args any, extra ...string) string {
-	u, _ := url.Parse(baseURL)
+	return buildURLAt(baseURL, path, args, extra...)
+}
+
+// buildURLAt is buildURL for another upstream root, such as a mirror.
+func buildURLAt(base, path string, args any, extra ...string) string {
+	u, _ := url.Parse(base)
 	u.Path, _ = url.JoinPath(u.Path, path)
 
 	q := url.Values{}
@@ -1467,66 +1861,157 @@ func buildURL(path string, args any, extra ...string) string {
 	return u.String()
 }
 
-func apiRequest(apiURL, title string) (*mcp.CallToolResult, error) {
-	req, err := http.NewRequest("GET", apiURL, nil)
+// upstreamClient is shared by every upstream call so connections to the
+// footapi host are kept alive and reused.
+var upstreamClient = &http.Client{
+	Timeout: 30 * time.Second,
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   32,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 20 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	},
+}
+
+func fetchUpstream(ctx context.Context, apiURL string) ([]byte, error) {
+	if body, ok := hotFeeds.get(apiURL, time.Now()); ok {
+		return body, nil
+	}
+	return requestUpstream(ctx, apiURL)
+}
+
+// requestUpstream fetches apiURL from upstream, bypassing the prefetcher.
+func requestUpstream(ctx context.Context, apiURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("request error: %v", err)), nil
+		return nil, fmt.Errorf("request error: %v", err)
 	}
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "LiveScore-MCP/1.0")
 
-	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	resp, err := upstreamClient.Do(req)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("request failed: %v", err)), nil
+		return nil, &upstreamError{Err: err}
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("read error: %v", err)), nil
+		return nil, fmt.Errorf("read error: %v", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return mcp.NewToolResultError(fmt.Sprintf("API error (status %d): %s", resp.StatusCode, string(body))), nil
+		return nil, &upstreamError{Status: resp.StatusCode, Body: string(body)}
 	}
+	return body, nil
+}
 
+func fetchJSON(ctx context.Context, apiURL string) (interface{}, error) {
+	body, err := fetchUpstream(ctx, apiURL)
+	if err != nil {
+		return nil, err
+	}
 	var data interface{}
-	if err := json.Unmarshal(body, &data); err == nil {
-		if pretty, err := json.MarshalIndent(data, "", "  "); err == nil {
-			return mcp.NewToolResultText(fmt.Sprintf("%s:\n\n%s", title, string(pretty))), nil
-		}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("decode error: %v", err)
+	}
+	payloadDrift.check(apiURL, data)
+	return data, nil
+}
+
+func jsonResult(title string, data interface{}) *mcp.CallToolResult {
+	pretty, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return toolErrorResult(codeInternal, fmt.Sprintf("encode error: %v", err))
 	}
+	return mcp.NewToolResultText(fmt.Sprintf("%s:\n\n%s", title, string(pretty)))
+}
 
-	return mcp.NewToolResultText(fmt.Sprintf("%s:\n\n%s", title, string(body))), nil
+// reportProgress sends notifications/progress for tools that fan out to
+// several upstream calls, if the client asked for it with a progress token.
+func reportProgress(ctx context.Context, req mcp.CallToolRequest, done, total int, message string) {
+	if req.Params.Meta == nil || req.Params.Meta.ProgressToken == nil {
+		return
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return
+	}
+	srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+		"progressToken": req.Params.Meta.ProgressToken,
+		"progress":      done,
+		"total":         total,
+		"message":       message,
+	})
 }
 
 // --- Tool Registration ---
 
-func registerTools(s *server.MCPServer) {
+func registerTools(s *server.MCPServer, health *healthChecker, catalog *competitionCatalog, publicURL string) {
 	// Health check
 	s.AddTool(
 		mcp.NewTool("health",
-			mcp.WithDescription("Health check - echo back a message"),
-			mcp.WithString("message", mcp.Required(), mcp.Description("Message to echo")),
+			mcp.WithDescription("Health check - echo back a message with the server build, or with deep=true report whether the upstream API is reachable and its latency"),
+			mcp.WithString("message", mcp.Description("Message to echo")),
+			mcp.WithBoolean("deep", mcp.Description("Probe upstream and report its status. Default: false")),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if req.GetBool("deep", false) {
+				report, _ := health.deep(ctx, false)
+				return jsonResult("Health", report), nil
+			}
 			msg := getStr(req.Params.Arguments, "message", "ok")
-			return mcp.NewToolResultText(fmt.Sprintf("Echo: %s", msg)), nil
+			return mcp.NewToolResultText(fmt.Sprintf("Echo: %s\nBuild: %s", msg, currentBuild())), nil
 		},
 	)
 
 	// Live scores
 	s.AddTool(
 		mcp.NewTool("get_live_scores",
-			mcp.WithDescription("Get currently live football matches and scores. All timestamps are GMT/UTC."),
+			mcp.WithDescription("Get currently live football matches and scores, optionally narrowed to one team or league, or to women's or men's football. All timestamps are GMT/UTC."),
 			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.). Default: en")),
+			mcp.WithString("team_id", mcp.Description("Only matches involving this team ID")),
+			mcp.WithString("team_name", mcp.Description("Only matches involving a team whose name contains this text")),
+			mcp.WithString("league_key", mcp.Description("Only matches in this league, by exact league key or full name (e.g. NetherlandsEredivisie or Eredivisie)")),
+			genderOption,
+			womensOption,
+			tierOption,
+			friendliesOption,
+			youthOption,
+			formatOption,
 		),
-		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return apiRequest(
-				buildURL("fixtures/feed_livenow.json", req.Params.Arguments),
-				"Live Scores",
-			)
-		},
+		withFormats(matchTable, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			teamID := getStr(req.Params.Arguments, "team_id", "")
+			teamName := getStr(req.Params.Arguments, "team_name", "")
+			leagueKey := getStr(req.Params.Arguments, "league_key", "")
+			filter, err := competitionFilterArg(req.Params.Arguments)
+			if err != nil {
+				return toolErrorResult(codeInvalidArgument, err.Error()), nil
+			}
+			data, err := source.LiveScores(ctx, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			markDerbies(data)
+			if teamID == "" && teamName == "" && leagueKey == "" && !filter.active() {
+				return jsonResult("Live Scores", data), nil
+			}
+			filtered := filterFeed(data, func(m feedMatch) bool {
+				if (teamID != "" || teamName != "") && !m.involvesTeam(teamID, teamName) {
+					return false
+				}
+				return (leagueKey == "" || m.inLeague(leagueKey)) && filter.keep(m)
+			})
+			return jsonResult("Live Scores (filtered)", filtered), nil
+		}),
 	)
 
 	// Competition fixtures
@@ -1535,14 +2020,16 @@ func registerTools(s *server.MCPServer) {
 			mcp.WithDescription("Get fixtures for a specific competition (e.g. EurocupsUEFAChampionsLeague_small). All timestamps are GMT/UTC."),
 			mcp.WithString("competition", mcp.Required(), mcp.Description("Competition identifier")),
 			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+			formatOption,
 		),
-		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		withFormats(matchTable, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			comp := getStr(req.Params.Arguments, "competition", "")
-			return apiRequest(
-				buildURL(fmt.Sprintf("fixtures_v2/%s.json", comp), req.Params.Arguments),
-				fmt.Sprintf("Fixtures for %s", comp),
-			)
-		},
+			data, err := source.Competition(ctx, comp, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			return jsonResult(fmt.Sprintf("Fixtures for %s", comp), markDerbies(data)), nil
+		}),
 	)
 
 	// Search
@@ -1552,22 +2039,28 @@ func registerTools(s *server.MCPServer) {
 			mcp.WithString("q", mcp.Required(), mcp.Description("Search term (team, player, or competition name)")),
 			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
 			mcp.WithString("country", mcp.Description("Country filter (e.g. Netherlands, England)")),
+			genderOption,
+			womensOption,
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			query := getStr(req.Params.Arguments, "q", "")
-
-			u, _ := url.Parse(baseURL)
-			u.Path, _ = url.JoinPath(u.Path, "search_v3")
-			q := url.Values{}
-			q.Set("q", query)
-			q.Set("lang", getStr(req.Params.Arguments, "language", defaultLang))
-			q.Set("version", strconv.Itoa(getInt(req.Params.Arguments, "version", defaultVersion)))
-			if country := getStr(req.Params.Arguments, "country", ""); country != "" {
-				q.Set("country", country)
+			country := getStr(req.Params.Arguments, "country", "")
+			gender, err := genderArg(req.Params.Arguments)
+			if err != nil {
+				return toolErrorResult(codeInvalidArgument, err.Error()), nil
 			}
-			u.RawQuery = q.Encode()
 
-			return apiRequest(u.String(), fmt.Sprintf("Search results for '%s'", query))
+			searchCtx, cancel := context.WithTimeout(ctx, searchTimeout)
+			defer cancel()
+			data, err := source.Search(searchCtx, query, country, queryOf(req.Params.Arguments))
+			if err == nil {
+				return jsonResult(fmt.Sprintf("Search results for '%s'", query), searchOfGender(data, gender)), nil
+			}
+			if code := errorCode(err); (serverFaults[code] || code == codeRateLimited) && len(builtinIndex.Teams) > 0 {
+				return jsonResult(fmt.Sprintf("Search results for '%s' from the offline index of %s (upstream search failed: %s)",
+					query, builtinIndex.Generated, code), searchOfGender(builtinIndex.search(query, country), gender)), nil
+			}
+			return errorResult(err), nil
 		},
 	)
 
@@ -1576,83 +2069,186 @@ func registerTools(s *server.MCPServer) {
 		mcp.NewTool("get_league_fixtures",
 			mcp.WithDescription("Get fixtures for a specific league (e.g. NetherlandsEredivisie). All timestamps are GMT/UTC."),
 			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithString("round", mcp.Description("Only this round or matchweek, e.g. 24")),
 			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+			formatOption,
 		),
-		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			key := getStr(req.Params.Arguments, "league_key", "")
-			return apiRequest(
-				buildURL(fmt.Sprintf("fixtures_v2/%s_small.json", key), req.Params.Arguments),
-				fmt.Sprintf("League fixtures for %s", key),
-			)
-		},
+		withFormats(matchTable, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			if round := getStr(req.Params.Arguments, "round", ""); round != "" {
+				data = filterFeed(data, func(m feedMatch) bool { return m.inRound(round) })
+				if len(extractMatches(data)) == 0 {
+					return toolErrorResult(codeNotFound, fmt.Sprintf("no fixtures of round %s found for %s", round, key)), nil
+				}
+				return jsonResult(fmt.Sprintf("League fixtures for %s, round %s", key, round), markDerbies(data)), nil
+			}
+			return jsonResult(fmt.Sprintf("League fixtures for %s", key), markDerbies(data)), nil
+		}),
+	)
+
+	// League table
+	s.AddTool(
+		mcp.NewTool("get_standings",
+			mcp.WithDescription("Get the current table for a league (e.g. NetherlandsEredivisie), or the final table of a past season: position, played, won, drawn, lost, goals and points per team"),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithString("season", mcp.Description("Past season, e.g. 2023/2024, where upstream archives it. Default: the current season")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+			formatOption,
+		),
+		withFormats(standingsTable, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			season := getStr(req.Params.Arguments, "season", "")
+			data, err := source.Competition(ctx, leagueFeed(key), seasonQuery(req.Params.Arguments, season))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			if err := archivedSeason(data, key, season); err != nil {
+				return errorResult(err), nil
+			}
+			rows := extractStandings(data)
+			if len(rows) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no standings found for %s", key)), nil
+			}
+			type standing struct {
+				standingRow
+				GoalDifference int `json:"goal_difference"`
+			}
+			table := make([]standing, len(rows))
+			for i, r := range rows {
+				table[i] = standing{r, r.GoalDifference()}
+			}
+			title := fmt.Sprintf("Standings for %s", key)
+			if season != "" {
+				title = fmt.Sprintf("Standings for %s in %s", key, normSeason(season))
+			}
+			return jsonResult(title, table), nil
+		}),
 	)
 
 	// Team info
 	s.AddTool(
 		mcp.NewTool("get_team",
-			mcp.WithDescription("Get detailed team information (squad, stats) by team ID"),
+			mcp.WithDescription("Get detailed team information (squad, stats) by team ID, with squad aggregates: size, average age, foreigners, total market value and most capped players"),
 			mcp.WithString("id", mcp.Required(), mcp.Description("Team ID from search results (e.g. 13183 for Ajax)")),
 			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			id := getStr(req.Params.Arguments, "id", "")
-			return apiRequest(
-				buildURL(fmt.Sprintf("team_gs/%s.json", id), req.Params.Arguments),
-				fmt.Sprintf("Team info for ID %s", id),
-			)
+			data, err := source.Team(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			return jsonResult(fmt.Sprintf("Team info for ID %s", id), withSquadStats(data)), nil
 		},
 	)
 
 	// Player info
 	s.AddTool(
 		mcp.NewTool("get_player",
-			mcp.WithDescription("Get detailed player information (stats, career) by player ID"),
+			mcp.WithDescription("Get detailed player information (stats, career) by player ID, with the season's goals and assists per 90 minutes"),
 			mcp.WithString("id", mcp.Required(), mcp.Description("Player ID (e.g. 474972)")),
 			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			id := getStr(req.Params.Arguments, "id", "")
-			return apiRequest(
-				buildURL(fmt.Sprintf("players/%s.json", id), req.Params.Arguments),
-				fmt.Sprintf("Player info for ID %s", id),
-			)
+			data, err := source.Player(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			return jsonResult(fmt.Sprintf("Player info for ID %s", id), withPer90(data)), nil
 		},
 	)
 
 	// Match info
 	s.AddTool(
 		mcp.NewTool("get_match",
-			mcp.WithDescription("Get detailed match information (events, lineups, stats) with optional head-to-head data"),
+			mcp.WithDescription("Get detailed match information (events, lineups, stats, attendance where known) with optional head-to-head data and its record split by venue, and the weather at the venue around kickoff when the server has a weather provider"),
 			mcp.WithString("id", mcp.Required(), mcp.Description("Match ID from live scores or fixtures")),
 			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
 			mcp.WithNumber("h2h", mcp.Description("Include head-to-head data: 1=yes, 0=no. Default: 1")),
+			mcp.WithNumber("h2h_limit", mcp.Description(fmt.Sprintf("Number of past meetings in the head-to-head, up to %d, topped up from the teams' pages when upstream has fewer. Default: upstream's", maxH2HMeetings))),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			id := getStr(req.Params.Arguments, "id", "")
-			h2h := strconv.Itoa(getInt(req.Params.Arguments, "h2h", 1))
-			return apiRequest(
-				buildURL(fmt.Sprintf("matches/%s.json", id), req.Params.Arguments, "h2h", h2h),
-				fmt.Sprintf("Match info for ID %s", id),
-			)
+			h2h := getInt(req.Params.Arguments, "h2h", 1) != 0
+			limit := getInt(req.Params.Arguments, "h2h_limit", 0)
+			if limit < 0 || limit > maxH2HMeetings {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("h2h_limit must be between 0 and %d (0 = upstream default)", maxH2HMeetings)), nil
+			}
+			q := queryOf(req.Params.Arguments)
+			data, err := source.Match(ctx, id, h2h, q)
+			if err != nil {
+				return errorResult(err), nil
+			}
+			data = markVARDecisions(data)
+			if h2h {
+				data = withH2H(ctx, q, data, limit)
+			}
+			return jsonResult(fmt.Sprintf("Match info for ID %s", id), withWeather(ctx, withAttendance(data))), nil
 		},
 	)
 
 	// Day fixtures
 	s.AddTool(
 		mcp.NewTool("get_day_fixtures",
-			mcp.WithDescription("Get all fixtures for a specific date. All timestamps are GMT/UTC."),
+			mcp.WithDescription("Get all fixtures for a specific date, or for every day in a date range when end_date is given. All timestamps are GMT/UTC."),
 			mcp.WithString("date", mcp.Required(), mcp.Description("Date in DD/MM/YYYY format (e.g. 30/08/2025)")),
+			mcp.WithString("end_date", mcp.Description("Optional last date of a range in DD/MM/YYYY format, at most 14 days after date")),
 			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
 			mcp.WithNumber("tzoffset", mcp.Description("Timezone offset in minutes (e.g. 120 for UTC+2). Default: 0")),
+			genderOption,
+			womensOption,
+			tierOption,
+			friendliesOption,
+			youthOption,
+			formatOption,
 		),
-		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		withFormats(matchTable, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			date := getStr(req.Params.Arguments, "date", "")
-			tzOffset := strconv.Itoa(getInt(req.Params.Arguments, "tzoffset", 0))
-			return apiRequest(
-				buildURL("fixtures/feed_matches_aggregated.json", req.Params.Arguments, "date", date, "tzoffset", tzOffset),
-				fmt.Sprintf("Fixtures for %s", date),
-			)
-		},
+			tzOffset := getInt(req.Params.Arguments, "tzoffset", 0)
+			endDate := getStr(req.Params.Arguments, "end_date", "")
+			filter, err := competitionFilterArg(req.Params.Arguments)
+			if err != nil {
+				return toolErrorResult(codeInvalidArgument, err.Error()), nil
+			}
+			if endDate == "" {
+				data, err := source.Fixtures(ctx, date, tzOffset, queryOf(req.Params.Arguments))
+				if err != nil {
+					return errorResult(err), nil
+				}
+				return jsonResult(fmt.Sprintf("Fixtures for %s", date), filter.apply(markDerbies(data))), nil
+			}
+
+			start, err := time.Parse("02/01/2006", date)
+			if err != nil {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("invalid date %q: expected DD/MM/YYYY", date)), nil
+			}
+			end, err := time.Parse("02/01/2006", endDate)
+			if err != nil {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("invalid end_date %q: expected DD/MM/YYYY", endDate)), nil
+			}
+			days := int(end.Sub(start).Hours()/24) + 1
+			if days < 1 || days > 15 {
+				return toolErrorResult(codeInvalidArgument, "end_date must be on or after date and at most 14 days later"), nil
+			}
+
+			results := make(map[string]interface{}, days)
+			for i := 0; i < days; i++ {
+				day := start.AddDate(0, 0, i).Format("02/01/2006")
+				data, err := source.Fixtures(ctx, day, tzOffset, queryOf(req.Params.Arguments))
+				if err != nil {
+					results[day] = map[string]string{"error": err.Error()}
+				} else {
+					results[day] = filter.apply(markDerbies(data))
+				}
+				reportProgress(ctx, req, i+1, days, fmt.Sprintf("fetched %d/%d days", i+1, days))
+			}
+			return jsonResult(fmt.Sprintf("Fixtures from %s to %s", date, endDate), results), nil
+		}),
 	)
 
 	// Team image
@@ -1660,29 +2256,44 @@ func registerTools(s *server.MCPServer) {
 		mcp.NewTool("get_team_image",
 			mcp.WithDescription("Get team logo PNG URL by team ID"),
 			mcp.WithString("id", mcp.Required(), mcp.Description("Team ID")),
+			mcp.WithNumber("size", mcp.Description(fmt.Sprintf("Scale the logo to fit a size x size box (%d-%d pixels)", imageMinSize, imageMaxSize))),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			id := getStr(req.Params.Arguments, "id", "")
-			u, _ := url.Parse(baseURL)
-			u.Path, _ = url.JoinPath(u.Path, "images", "teams_gs", id+".png")
-			imageURL := u.String()
+			if !imageIDPattern.MatchString(id) {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("invalid team ID %q", id)), nil
+			}
+			size := getInt(req.Params.Arguments, "size", 0)
+			if size != 0 && (size < imageMinSize || size > imageMaxSize) {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("size must be between %d and %d", imageMinSize, imageMaxSize)), nil
+			}
+			imageURL := teamImageURL(id)
 
-			httpReq, err := http.NewRequest("HEAD", imageURL, nil)
+			headCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+			defer cancel()
+			httpReq, err := http.NewRequestWithContext(headCtx, "HEAD", imageURL, nil)
 			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("error: %v", err)), nil
+				return toolErrorResult(codeInternal, fmt.Sprintf("error: %v", err)), nil
 			}
 			httpReq.Header.Set("User-Agent", "LiveScore-MCP/1.0")
 
-			resp, err := (&http.Client{Timeout: 15 * time.Second}).Do(httpReq)
+			resp, err := upstreamClient.Do(httpReq)
 			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("error checking image: %v", err)), nil
+				return errorResult(&upstreamError{Err: err}), nil
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode != http.StatusOK {
-				return mcp.NewToolResultError(fmt.Sprintf("image not available (status %d) for team ID %s", resp.StatusCode, id)), nil
+				return toolErrorResult(codeNotFound, fmt.Sprintf("image not available (status %d) for team ID %s", resp.StatusCode, id)), nil
 			}
 
+			// Link through this server's image proxy when it has a public URL.
+			if publicURL != "" {
+				imageURL = fmt.Sprintf("%s/img/team/%s.png", publicURL, id)
+				if size != 0 {
+					imageURL += "?size=" + strconv.Itoa(size)
+				}
+			}
 			return mcp.NewToolResultText(fmt.Sprintf("Team logo URL for ID %s:\n%s", id, imageURL)), nil
 		},
 	)
@@ -1690,7 +2301,73 @@ func registerTools(s *server.MCPServer) {
 
 // --- Resource Registration ---
 
+// sourceFetch fetches one item by ID from the data source.
+type sourceFetch func(ctx context.Context, id string) (interface{}, error)
+
+var (
+	fetchTeam   sourceFetch = func(ctx context.Context, id string) (interface{}, error) { return source.Team(ctx, id, queryOf(nil)) }
+	fetchPlayer sourceFetch = func(ctx context.Context, id string) (interface{}, error) { return source.Player(ctx, id, queryOf(nil)) }
+	fetchMatch  sourceFetch = func(ctx context.Context, id string) (interface{}, error) {
+		return source.Match(ctx, id, true, queryOf(nil))
+	}
+	fetchMatchNoH2H sourceFetch = func(ctx context.Context, id string) (interface{}, error) {
+		return source.Match(ctx, id, false, queryOf(nil))
+	}
+)
+
+func readUpstream(ctx context.Context, uri, id string, fetch sourceFetch) ([]mcp.ResourceContents, error) {
+	data, err := fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(body),
+		},
+	}, nil
+}
+
+// upstreamResource serves a URI-templated resource by fetching the
+// template's {id}.
+func upstreamResource(fetch sourceFetch) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		id := templateArg(req.Params.Arguments, "id")
+		if id == "" {
+			return nil, fmt.Errorf("missing id in %s", req.Params.URI)
+		}
+		return readUpstream(ctx, req.Params.URI, id, fetch)
+	}
+}
+
 func registerResources(s *server.MCPServer) {
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate("team://{id}", "Team",
+			mcp.WithTemplateDescription("Team details (squad, stats) by team ID, same data as get_team"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		upstreamResource(fetchTeam),
+	)
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate("player://{id}", "Player",
+			mcp.WithTemplateDescription("Player profile (career, stats) by player ID, same data as get_player"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		upstreamResource(fetchPlayer),
+	)
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate("match://{id}", "Match",
+			mcp.WithTemplateDescription("Match details (events, lineups, stats, h2h) by match ID, same data as get_match"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		upstreamResource(fetchMatch),
+	)
+
 	s.AddResource(
 		mcp.NewResource(
 			"server://info",
@@ -1698,24 +2375,81 @@ func registerResources(s *server.MCPServer) {
 			mcp.WithMIMEType("text/plain"),
 		),
 		func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-			info := `LiveScore MCP Server v1.0.0
+			info := "LiveScore MCP Server v" + currentBuild().Version + `
 
 A football livescore MCP providing real-time data about matches, teams, players, fixtures, standings, goals, events, lineups, and stats.
 
 Available Tools:
-- health: Echo test for connectivity check
-- get_live_scores: Currently live matches with real-time scores
+- health: Echo test for connectivity check with the server build; deep=true probes upstream and reports cache and session stats
+- get_live_scores: Currently live matches with real-time scores (filter by team_id, team_name, league_key, gender, competitions_tier, exclude_friendlies or youth)
 - get_fixtures: Competition fixtures (e.g. Champions League)
+- simulate_draw: Random knockout draws with seeding and country protection, and each team's chance of meeting each opponent
 - search: Search teams, players, or competitions by name
-- get_league_fixtures: League fixtures by league key (e.g. NetherlandsEredivisie)
-- get_team: Detailed team info (squad, stats) by team ID
-- get_player: Detailed player info (career, stats) by player ID
-- get_match: Match details (events, lineups, stats, h2h) by match ID
-- get_day_fixtures: All fixtures for a specific date
-- get_team_image: Team logo PNG URL by team ID
+- get_league_fixtures: League fixtures by league key (e.g. NetherlandsEredivisie), optionally one round
+- get_league_calendar: Season start and end, round dates and breaks (winter break) of a league
+- get_standings: League table by league key, or a past season's final table
+- get_title_race: Title contenders, points gaps, fixtures left and head-to-heads still to play in a league
+- get_relegation_battle: Teams near the bottom with gaps to safety, fixture difficulty and survival probabilities
+- get_points_projection: Projected end-of-season points per team with a likely range
+- get_what_if_standings: A league table recomputed with hypothetical results for unfinished matches
+- get_streaks: Active winning, unbeaten, scoring and other streaks per team in a league
+- get_league_records: A season's biggest wins, highest-scoring match, fastest goal and other records
+- get_league_attendance: Total, average and per-club crowds in a league's season
+- get_top_scorers: A league's top scorers from the goal events of its finished matches
+- get_discipline_table: Yellow and red cards per team and per player in a league's season
+- get_defensive_stats: Clean sheets, goals conceded per match and saves per team in a league
+- get_team: Detailed team info (squad, stats, squad aggregates) by team ID
+- get_team_honours: A team's league titles, cups and European trophies with years
+- get_venue: A team's stadium or a match's venue with city, capacity and coordinates, optionally the distance from another team's ground
+- get_rivalry: Record between two teams (overall and by venue), last and next meetings, and the derby's name for well-known rivalries
+- get_fixture_congestion: A team's matches in the next 30 days with rest days between them and per-competition counts
+- get_team_managers: A team's current and past managers with tenure dates and win rates
+- get_squad: A team's players with contract end dates; expiring_contracts=N for contracts ending within N months
+- get_teams: Up to 10 teams in one call, fetched concurrently and keyed by ID
+- get_player: Detailed player info (career, stats, per-90 rates) by player ID
+- get_player_career_stats: Season-by-season rows of club, competition, appearances, goals, assists, minutes and per-90 rates
+- get_player_transfer_history: A player's moves between clubs with dates and fees
+- get_player_value: A player's market value, or several players ranked by value
+- get_player_milestones: Next career and club milestones (100th goal, 300th appearance) and an upcoming birthday
+- get_birthdays: Notable players born on a date, from the offline index
+- get_match: Match details (events with VAR decisions, lineups, stats, attendance, h2h with a venue split, weather when configured) by match ID
+- get_match_stats: A match's stats as home/away rows, with xG, xA and attendance where available
+- get_team_xg: A team's xG for and against over its recent finished matches
+- get_team_trends: Rolling averages of goals, possession and shots over a team's last N matches
+- get_matches: Up to 25 matches in one call with partial results and per-ID errors
+- get_lineups: Starting XIs as text pitch diagrams by formation line, SVG at /lineup/match/{id}.svg
+- get_day_fixtures: All fixtures for a specific date or date range (with progress notifications)
+- get_upcoming_kickoffs: Matches kicking off in the next few hours, soonest first, optionally for one league
+- get_goals: Every goal of a day across leagues, in the order scored
+- get_red_cards: Every sending-off of a day, or of the matches in play (live=true)
+- get_team_image: Team logo PNG URL by team ID, served through /img/team/{id}.png (optional size)
+- get_national_team: A country's national side with upcoming qualifiers and friendlies, squad and recent results
+- get_team_calendar: A team's fixtures as iCalendar (ICS) text, also at /calendar/team/{id}.ics
+- add_favorite_team / remove_favorite_team / list_favorites: Manage this session's favorite teams
+- get_my_live_scores: Live matches involving this session's favorite teams
+- subscribe_match_events / unsubscribe_match_events: Webhook delivery of goals, cards and full-time results
+- get_recent_events: Goals, cards, VAR decisions, kickoffs and results detected in live matches recently
+- get_changes_since: Only the live matches whose score or status changed since a cursor
+- set_language: Default language for the rest of this session
+- list_supported_languages: Language codes upstream supports, with native names
+- get_my_quota: Current rate limit tier, remaining requests, reset time and today's usage
+- basketball_* / tennis_*: Live scores, fixtures, matches and search for other sports, when enabled in the config
+
+Resources:
+- team://{id}, player://{id}, match://{id}: Team, player and match details by ID
+- match://{id}/live: Current match state; subscribe for resources/updated notifications on score changes
+- livescore://live: Directory of currently live matches linking to their match://{id}/live resources
+- livescore://ticker: One plain-text line per live match, refreshed after every poll; cheap to re-read
+- livescore://competitions: Catalog of known league keys, names and countries
+
+Prompts:
+- match_preview: Pre-match preview from h2h, form, injuries and standings (match_id)
+- daily_digest: Today's fixtures and yesterday's results for a set of leagues
+- league_roundup: A matchweek's results, scorers and table for a league (league_key, matchweek)
 
 All timestamps are in GMT/UTC - convert to local timezone as needed.
-Supports multiple languages: en, nl, de, fr, es, pt, it, etc.
+Fixture, live score, standings and top scorer tools accept format=csv for spreadsheet-ready output or format=markdown for chat-ready tables.
+Supports multiple languages: en, nl, de, fr, es, pt, it (see list_supported_languages).
 
 Example Queries:
 - "Show me live football matches right now"
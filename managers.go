@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Managers ---
+//
+// Team pages name the current manager and, for some clubs, the previous
+// ones with their tenure and record. get_team_managers lists them newest
+// first with a win rate where the record is known. For the current manager
+// without a record, it is counted from the finished matches on the team page
+// since the appointment, which covers the recent matches only.
+
+type managerSpell struct {
+	ID      string   `json:"id,omitempty"`
+	Name    string   `json:"name"`
+	From    string   `json:"from,omitempty"`
+	Until   string   `json:"until,omitempty"`
+	Current bool     `json:"current"`
+	Matches *int     `json:"matches,omitempty"`
+	Won     *int     `json:"won,omitempty"`
+	Drawn   *int     `json:"drawn,omitempty"`
+	Lost    *int     `json:"lost,omitempty"`
+	WinRate *float64 `json:"win_rate,omitempty"` // percentage of matches won
+	Record  string   `json:"record_source,omitempty"`
+}
+
+// managerDate normalises a tenure date to YYYY-MM-DD, keeping what it
+// cannot parse.
+func managerDate(s string) string {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return s
+}
+
+func (m *managerSpell) setWinRate() {
+	if m.Matches != nil && m.Won != nil && *m.Matches > 0 {
+		rate := math.Round(float64(*m.Won)/float64(*m.Matches)*1000) / 10
+		m.WinRate = &rate
+	}
+}
+
+// extractManagers reads the managers of a team payload, newest first.
+func extractManagers(data interface{}) []managerSpell {
+	var items []interface{}
+	if v, ok := findKey(data, "managers", "coaches", "managerhistory"); ok {
+		items, _ = v.([]interface{})
+	} else if v, ok := findKey(data, "manager", "coach", "headcoach"); ok {
+		items = []interface{}{v}
+	}
+	spells := []managerSpell{}
+	for _, item := range items {
+		var m managerSpell
+		switch e := item.(type) {
+		case map[string]interface{}:
+			m = managerSpell{
+				ID:      lookupStr(e, "id", "managerid", "coachid"),
+				Name:    lookupStr(e, "name", "managername", "coachname"),
+				From:    managerDate(lookupStr(e, "from", "start", "startdate", "appointed", "since")),
+				Until:   managerDate(lookupStr(e, "until", "to", "end", "enddate", "left")),
+				Matches: optInt(e, "matches", "games", "played"),
+				Won:     optInt(e, wonKeys...),
+				Drawn:   optInt(e, drawnKeys...),
+				Lost:    optInt(e, lostKeys...),
+			}
+			if m.Matches == nil && m.Won != nil && m.Drawn != nil && m.Lost != nil {
+				n := *m.Won + *m.Drawn + *m.Lost
+				m.Matches = &n
+			}
+			if m.Matches != nil {
+				m.Record = "upstream"
+			}
+		default:
+			m = managerSpell{Name: scalarString(e)}
+		}
+		if m.Name == "" {
+			continue
+		}
+		m.Current = m.Until == ""
+		m.setWinRate()
+		spells = append(spells, m)
+	}
+	sort.SliceStable(spells, func(i, j int) bool {
+		if spells[i].Current != spells[j].Current {
+			return spells[i].Current
+		}
+		return spells[i].From > spells[j].From
+	})
+	return spells
+}
+
+// recentRecord counts the team's finished matches since from (all of them
+// when from is empty) for a manager without a record.
+func recentRecord(m *managerSpell, data interface{}, teamID, teamName string) {
+	since, _ := time.Parse("2006-01-02", m.From)
+	won, drawn, lost := 0, 0, 0
+	seen := map[string]bool{}
+	for _, fm := range extractMatches(data) {
+		if !fm.finished() || !fm.HasScore || seen[fm.ID] || !fm.involvesTeam(teamID, teamName) {
+			continue
+		}
+		if kickoff, ok := fm.kickoff(); ok && kickoff.Before(since) {
+			continue
+		}
+		seen[fm.ID] = true
+		home := fm.HomeID == teamID || (fm.HomeID == "" && fm.HomeName == teamName)
+		goalsFor, goalsAgainst := fm.HomeGoals, fm.AwayGoals
+		if !home {
+			goalsFor, goalsAgainst = goalsAgainst, goalsFor
+		}
+		switch {
+		case goalsFor > goalsAgainst:
+			won++
+		case goalsFor == goalsAgainst:
+			drawn++
+		default:
+			lost++
+		}
+	}
+	matches := won + drawn + lost
+	if matches == 0 {
+		return
+	}
+	m.Matches, m.Won, m.Drawn, m.Lost = &matches, &won, &drawn, &lost
+	m.Record = "recent matches"
+	m.setWinRate()
+}
+
+func registerManagerTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_team_managers",
+			mcp.WithDescription("Get a team's current and past managers with tenure dates and, where known, matches, wins, draws, losses and win rate"),
+			mcp.WithString("team_id", mcp.Required(), mcp.Description("Team ID from search results (e.g. 13183 for Ajax)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "team_id", "")
+			data, err := source.Team(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			managers := extractManagers(data)
+			if len(managers) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("upstream names no manager for team %s", id)), nil
+			}
+			if current := &managers[0]; current.Current && current.Matches == nil {
+				recentRecord(current, data, id, entityName(data, "team"))
+			}
+			return jsonResult(fmt.Sprintf("Managers of team %s", id), managers), nil
+		},
+	)
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Match Stats and xG ---
+//
+// get_match_stats lists a match's stats as home/away rows, with expected
+// goals (xG) and expected assists (xA) as fields of their own where upstream
+// has them, so an agent need not know what upstream calls them. get_team_xg
+// adds up a team's xG for and against over its recent finished matches; the
+// match pages are fetched for that, at most maxBulkMatches of them.
+
+var (
+	xgKeys = []string{"xg", "expectedgoals"}
+	xaKeys = []string{"xa", "expectedassists"}
+)
+
+// sidePair is a stat's home and away value.
+type sidePair struct {
+	Home float64 `json:"home"`
+	Away float64 `json:"away"`
+}
+
+type statLine struct {
+	Stat string `json:"stat"`
+	sidePair
+}
+
+type matchStats struct {
+	MatchID string     `json:"match_id"`
+	Home    string     `json:"home"`
+	Away    string     `json:"away"`
+	Score   string     `json:"score,omitempty"`
+	Status  string     `json:"status,omitempty"`
+	Crowd   *int       `json:"attendance,omitempty"`
+	XG      *sidePair  `json:"xg"`
+	XA      *sidePair  `json:"xa"`
+	Stats   []statLine `json:"stats"`
+}
+
+// statValue reads a stat given as a number or as text such as "54%" or
+// "23,000".
+func statValue(v interface{}) (float64, bool) {
+	if f, ok := v.(float64); ok {
+		return f, true
+	}
+	s := strings.ReplaceAll(strings.TrimSuffix(scalarString(v), "%"), ",", "")
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+// asSidePair reads a {"home": x, "away": y} stat.
+func asSidePair(v interface{}) (*sidePair, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	home, okHome := lookup(m, "home")
+	away, okAway := lookup(m, "away")
+	if !okHome || !okAway {
+		return nil, false
+	}
+	h, okHome := statValue(home)
+	a, okAway := statValue(away)
+	if !okHome || !okAway {
+		return nil, false
+	}
+	return &sidePair{h, a}, true
+}
+
+// extractMatchStats reads the stats of a match, sorted by name, with xG and
+// xA taken out of the list.
+func extractMatchStats(fm feedMatch) matchStats {
+	ms := matchStats{MatchID: fm.ID, Home: fm.HomeName, Away: fm.AwayName, Status: fm.Status, Stats: []statLine{}}
+	if fm.HasScore {
+		ms.Score = fmt.Sprintf("%d-%d", fm.HomeGoals, fm.AwayGoals)
+	}
+	if crowd, ok := attendance(fm.Raw); ok {
+		ms.Crowd = &crowd
+	}
+	v, _ := lookup(fm.Raw, "stats", "statistics")
+	stats, _ := v.(map[string]interface{})
+	for name, value := range stats {
+		pair, ok := asSidePair(value)
+		if !ok {
+			continue
+		}
+		switch key := normKey(name); {
+		case slices.Contains(xgKeys, key):
+			ms.XG = pair
+		case slices.Contains(xaKeys, key):
+			ms.XA = pair
+		default:
+			ms.Stats = append(ms.Stats, statLine{name, *pair})
+		}
+	}
+	sort.Slice(ms.Stats, func(i, j int) bool { return ms.Stats[i].Stat < ms.Stats[j].Stat })
+	return ms
+}
+
+// teamXG is a team's xG over the matches upstream has it for.
+type teamXG struct {
+	TeamID            string    `json:"team_id"`
+	Team              string    `json:"team,omitempty"`
+	Matches           int       `json:"matches_with_xg"`
+	XGFor             float64   `json:"xg_for"`
+	XGAgainst         float64   `json:"xg_against"`
+	XGForPerMatch     float64   `json:"xg_for_per_match"`
+	XGAgainstPerMatch float64   `json:"xg_against_per_match"`
+	GoalsFor          int       `json:"goals_for"`
+	GoalsAgainst      int       `json:"goals_against"`
+	PerMatch          []xgMatch `json:"per_match"`
+}
+
+type xgMatch struct {
+	MatchID   string  `json:"match_id"`
+	Date      string  `json:"date,omitempty"`
+	Opponent  string  `json:"opponent"`
+	Home      bool    `json:"home"`
+	Score     string  `json:"score"` // team's goals first
+	XGFor     float64 `json:"xg_for"`
+	XGAgainst float64 `json:"xg_against"`
+}
+
+func round2(f float64) float64 { return math.Round(f*100) / 100 }
+
+// aggregateXG adds up the xG of the team's matches; matches without xG are
+// skipped.
+func aggregateXG(teamID, teamName string, matches []feedMatch) teamXG {
+	agg := teamXG{TeamID: teamID, Team: teamName, PerMatch: []xgMatch{}}
+	for _, fm := range matches {
+		xg := extractMatchStats(fm).XG
+		if xg == nil || !fm.HasScore {
+			continue
+		}
+		home := fm.HomeID == teamID
+		row := xgMatch{MatchID: fm.ID, Home: home, Opponent: fm.AwayName, XGFor: xg.Home, XGAgainst: xg.Away}
+		goalsFor, goalsAgainst := fm.HomeGoals, fm.AwayGoals
+		if !home {
+			row.Opponent, row.XGFor, row.XGAgainst = fm.HomeName, xg.Away, xg.Home
+			goalsFor, goalsAgainst = goalsAgainst, goalsFor
+		}
+		if kickoff, ok := fm.kickoff(); ok {
+			row.Date = kickoff.Format("2006-01-02")
+		}
+		row.Score = fmt.Sprintf("%d-%d", goalsFor, goalsAgainst)
+		agg.Matches++
+		agg.XGFor += row.XGFor
+		agg.XGAgainst += row.XGAgainst
+		agg.GoalsFor += goalsFor
+		agg.GoalsAgainst += goalsAgainst
+		agg.PerMatch = append(agg.PerMatch, row)
+	}
+	if agg.Matches > 0 {
+		agg.XGForPerMatch = round2(agg.XGFor / float64(agg.Matches))
+		agg.XGAgainstPerMatch = round2(agg.XGAgainst / float64(agg.Matches))
+	}
+	agg.XGFor, agg.XGAgainst = round2(agg.XGFor), round2(agg.XGAgainst)
+	return agg
+}
+
+// recentFinished returns the team's finished matches on its page, latest
+// first, at most limit.
+func recentFinished(data interface{}, teamID string, limit int) []feedMatch {
+	var out []feedMatch
+	seen := map[string]bool{}
+	for _, fm := range extractMatches(data) {
+		if fm.ID == "" || seen[fm.ID] || !fm.finished() || !fm.involvesTeam(teamID, "") {
+			continue
+		}
+		seen[fm.ID] = true
+		out = append(out, fm)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		ti, _ := out[i].kickoff()
+		tj, _ := out[j].kickoff()
+		return ti.After(tj)
+	})
+	return out[:min(limit, len(out))]
+}
+
+// matchDetails fetches the match pages of matches, keeping the feed's
+// version of any that fail.
+func matchDetails(ctx context.Context, req mcp.CallToolRequest, matches []feedMatch) []feedMatch {
+	ids := make([]string, len(matches))
+	for i, fm := range matches {
+		ids[i] = fm.ID
+	}
+	res := fetchBulk(ctx, req, ids, fetchMatchNoH2H)
+	out := make([]feedMatch, len(matches))
+	for i, fm := range matches {
+		out[i] = fm
+		if detail, ok := primaryMatch(res.Results[fm.ID]); ok {
+			out[i] = detail
+		}
+	}
+	return out
+}
+
+func registerMatchStatsTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_match_stats",
+			mcp.WithDescription("Get a match's stats (possession, shots, corners...) as home/away rows, with expected goals (xG) and expected assists (xA) where upstream provides them"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Match ID from live scores or fixtures")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "id", "")
+			data, err := source.Match(ctx, id, false, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			fm, ok := primaryMatch(data)
+			if !ok {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("match %s not found", id)), nil
+			}
+			stats := extractMatchStats(fm)
+			if len(stats.Stats) == 0 && stats.XG == nil && stats.XA == nil {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("upstream has no stats for match %s", id)), nil
+			}
+			return jsonResult(fmt.Sprintf("Stats for match %s", id), stats), nil
+		},
+	)
+
+	s.AddTool(
+		mcp.NewTool("get_team_xg",
+			mcp.WithDescription(fmt.Sprintf("Get a team's expected goals (xG) for and against over its last %d finished matches, totals, per-match averages and each match, next to the goals actually scored", maxBulkMatches)),
+			mcp.WithString("team_id", mcp.Required(), mcp.Description("Team ID from search results (e.g. 13183 for Ajax)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "team_id", "")
+			data, err := source.Team(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			matches := recentFinished(data, id, maxBulkMatches)
+			if len(matches) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no finished matches found for team %s", id)), nil
+			}
+			agg := aggregateXG(id, entityName(data, "team"), matchDetails(ctx, req, matches))
+			if agg.Matches == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("upstream has no xG for the %d recent matches of team %s", len(matches), id)), nil
+			}
+			return jsonResult(fmt.Sprintf("xG of team %s (%d of %d recent matches have xG)", id, agg.Matches, len(matches)), agg), nil
+		},
+	)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Milestones and Birthdays ---
+//
+// get_player_milestones adds up a player's career rows, for the whole career
+// and per club, and reports the next round number of goals and appearances,
+// flagging those within reach. get_birthdays lists the players of the
+// offline index born on a day; the index has the birth dates of the top-tier
+// squads (see build-index), so it names the players people ask about without
+// crawling every squad at request time.
+
+const (
+	appearanceStep   = 50 // 50th, 100th, 150th... appearance
+	goalStep         = 50 // 50th, 100th... goal; every 10th below 50
+	appearanceWindow = 10 // an appearance milestone this close is approaching
+	goalWindow       = 5
+	birthdayWindow   = 30 // days ahead a birthday is mentioned
+)
+
+var birthDateKeys = []string{"birthdate", "dateofbirth", "dob", "born"}
+
+// birthDate returns a player payload's birth date as YYYY-MM-DD.
+func birthDate(data interface{}) string {
+	v, _ := findKey(data, birthDateKeys...)
+	s := scalarString(v)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
+type milestone struct {
+	Stat        string `json:"stat"`  // goals or appearances
+	Scope       string `json:"scope"` // career, or a club
+	Current     int    `json:"current"`
+	Next        int    `json:"next"`
+	Remaining   int    `json:"remaining"`
+	Approaching bool   `json:"approaching"`
+}
+
+func nextMilestone(stat, scope string, current int) milestone {
+	step, window := appearanceStep, appearanceWindow
+	if stat == "goals" {
+		step, window = goalStep, goalWindow
+		if current < goalStep {
+			step = 10
+		}
+	}
+	next := (current/step + 1) * step
+	return milestone{Stat: stat, Scope: scope, Current: current, Next: next, Remaining: next - current, Approaching: next-current <= window}
+}
+
+// playerMilestones sums the career by club and overall and returns the
+// next milestone of each, approaching ones first.
+func playerMilestones(career []careerRow) []milestone {
+	type totals struct{ apps, goals int }
+	total := totals{}
+	clubs := map[string]*totals{}
+	var order []string
+	for _, r := range career {
+		club := clubs[r.Team]
+		if club == nil {
+			club = &totals{}
+			clubs[r.Team] = club
+			order = append(order, r.Team)
+		}
+		if r.Appearances != nil {
+			total.apps += *r.Appearances
+			club.apps += *r.Appearances
+		}
+		if r.Goals != nil {
+			total.goals += *r.Goals
+			club.goals += *r.Goals
+		}
+	}
+	out := []milestone{
+		nextMilestone("goals", "career", total.goals),
+		nextMilestone("appearances", "career", total.apps),
+	}
+	if len(order) > 1 {
+		for _, team := range order {
+			out = append(out, nextMilestone("goals", team, clubs[team].goals), nextMilestone("appearances", team, clubs[team].apps))
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Approaching && !out[j].Approaching })
+	return out
+}
+
+// nextBirthday returns the date of the next birthday on or after today and
+// the age turned.
+func nextBirthday(born string, today time.Time) (time.Time, int, bool) {
+	b, err := time.Parse("2006-01-02", born)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	next := time.Date(today.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	if next.Before(today) {
+		next = next.AddDate(1, 0, 0)
+	}
+	return next, next.Year() - b.Year(), true
+}
+
+func registerMilestoneTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_player_milestones",
+			mcp.WithDescription("Get a player's next career and club milestones (e.g. 100th goal, 300th appearance) from the recorded career, flagging those within reach, and an upcoming birthday"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Player ID (e.g. 474972)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "id", "")
+			data, err := source.Player(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			career := extractCareer(data)
+			if len(career) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no career history found for player %s", id)), nil
+			}
+			report := map[string]interface{}{
+				"player_id":  id,
+				"name":       entityName(data, "player"),
+				"milestones": playerMilestones(career),
+			}
+			today := time.Now().UTC().Truncate(24 * time.Hour)
+			if next, age, ok := nextBirthday(birthDate(data), today); ok && next.Sub(today) <= birthdayWindow*24*time.Hour {
+				report["birthday"] = map[string]interface{}{"date": next.Format("2006-01-02"), "turns": age}
+			}
+			return jsonResult(fmt.Sprintf("Milestones for player %s (totals of the seasons upstream records)", id), report), nil
+		},
+	)
+
+	s.AddTool(
+		mcp.NewTool("get_birthdays",
+			mcp.WithDescription("List notable players (top-tier squads in the offline index) born on a day, with the age they turn"),
+			mcp.WithString("date", mcp.Description("Date in DD/MM/YYYY format. Default: today (UTC)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			day := time.Now().UTC()
+			if date := getStr(req.Params.Arguments, "date", ""); date != "" {
+				t, err := time.Parse("02/01/2006", date)
+				if err != nil {
+					return toolErrorResult(codeInvalidArgument, fmt.Sprintf("invalid date %q: expected DD/MM/YYYY", date)), nil
+				}
+				day = t
+			}
+			if len(builtinIndex.Players) == 0 {
+				te := &toolError{Code: codeNotFound, Message: "the offline index has no player birth dates",
+					Remediation: "The server operator can add them by regenerating the index with build-index."}
+				return te.result(), nil
+			}
+			type birthday struct {
+				indexedPlayer
+				Turns int `json:"turns"`
+			}
+			found := []birthday{}
+			for _, p := range builtinIndex.Players {
+				b, err := time.Parse("2006-01-02", p.BirthDate)
+				if err == nil && b.Month() == day.Month() && b.Day() == day.Day() {
+					found = append(found, birthday{p, day.Year() - b.Year()})
+				}
+			}
+			sort.Slice(found, func(i, j int) bool { return found[i].Turns > found[j].Turns })
+			return jsonResult(fmt.Sprintf("Players born on %s (offline index of %s)", day.Format("2 January"), builtinIndex.Generated), found), nil
+		},
+	)
+}
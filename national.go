@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- National Teams ---
+//
+// National sides are ordinary teams upstream, named after their country, so
+// get_national_team finds one through search and splits its team page into
+// the squad, the matches still to play (qualifiers, friendlies, finals) and
+// recent results.
+
+const nationalMatches = 10 // upcoming and recent matches listed each
+
+func registerNationalTeamTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_national_team",
+			mcp.WithDescription("Get a country's national team: its upcoming qualifiers and friendlies, current squad and recent results. All timestamps are GMT/UTC."),
+			mcp.WithString("country", mcp.Required(), mcp.Description("Country name (e.g. Netherlands, England)")),
+			genderOption,
+			womensOption,
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			country := strings.TrimSpace(getStr(req.Params.Arguments, "country", ""))
+			if country == "" {
+				return toolErrorResult(codeInvalidArgument, "country is required"), nil
+			}
+			gender, err := genderArg(req.Params.Arguments)
+			if err != nil {
+				return toolErrorResult(codeInvalidArgument, err.Error()), nil
+			}
+			report, err := nationalTeam(ctx, country, gender == genderWomen, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			return jsonResult(fmt.Sprintf("National team of %s", country), report), nil
+		},
+	)
+}
+
+// nationalTeam looks up the country's side and reports its squad and
+// matches.
+func nationalTeam(ctx context.Context, country string, womens bool, q sourceQuery) (map[string]interface{}, error) {
+	results, err := source.Search(ctx, country, "", q)
+	if err != nil {
+		return nil, err
+	}
+	id, name := nationalTeamID(results, country, womens)
+	if id == "" {
+		return nil, notFound("no national team found for %q", country)
+	}
+	data, err := source.Team(ctx, id, q)
+	if err != nil {
+		return nil, err
+	}
+
+	type dated struct {
+		match   feedMatch
+		kickoff time.Time
+	}
+	var upcoming, recent []dated
+	seen := map[string]bool{}
+	for _, fm := range extractMatches(data) {
+		if !fm.involvesTeam(id, name) || seen[fm.ID] {
+			continue
+		}
+		seen[fm.ID] = true
+		kickoff, _ := fm.kickoff()
+		if fm.finished() {
+			recent = append(recent, dated{fm, kickoff})
+		} else {
+			upcoming = append(upcoming, dated{fm, kickoff})
+		}
+	}
+	sort.Slice(upcoming, func(i, j int) bool { return upcoming[i].kickoff.Before(upcoming[j].kickoff) })
+	sort.Slice(recent, func(i, j int) bool { return recent[i].kickoff.After(recent[j].kickoff) })
+	raw := func(list []dated) []map[string]interface{} {
+		out := []map[string]interface{}{}
+		for _, d := range list[:min(nationalMatches, len(list))] {
+			out = append(out, d.match.Raw)
+		}
+		return out
+	}
+
+	report := map[string]interface{}{
+		"team_id":  id,
+		"name":     name,
+		"upcoming": raw(upcoming),
+		"recent":   raw(recent),
+	}
+	if squad, ok := findKey(data, "squad", "players"); ok {
+		report["squad"] = squad
+	}
+	return report, nil
+}
+
+// nationalTeamID picks the team named after country from search results:
+// "Netherlands", or for women's football "Netherlands W" and the like.
+func nationalTeamID(results interface{}, country string, womens bool) (id, name string) {
+	root, _ := results.(map[string]interface{})
+	teams, _ := lookup(root, "teams")
+	list, _ := teams.([]interface{})
+	for _, item := range list {
+		t, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		teamName := lookupStr(t, teamNameKeys...)
+		if womensName.MatchString(teamName) != womens {
+			continue
+		}
+		base := strings.TrimSpace(womensName.ReplaceAllString(teamName, ""))
+		if strings.EqualFold(base, country) {
+			return lookupStr(t, teamIDKeys...), teamName
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- OAuth ---
+//
+// With OAUTH_ISSUER set the server acts as an OAuth 2.1 resource server as
+// described in the MCP authorization spec: it publishes protected resource
+// metadata (RFC 9728) pointing at the authorization server and accepts JWT
+// access tokens signed by it (RS256 or ES256, keys from its JWKS). Tokens
+// must be issued for this server (aud) and carry the required scope, if any.
+
+const (
+	oauthMetadataPath = "/.well-known/oauth-protected-resource"
+	jwksMinRefresh    = 5 * time.Minute
+)
+
+type oauthVerifier struct {
+	issuer        string
+	audience      string
+	jwksURL       string
+	requiredScope string
+	required      bool
+	metadataURL   string
+	client        *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey // kid -> key
+	fetched time.Time
+}
+
+// newOAuthVerifier configures OAuth from cfg, or returns nil if no issuer is
+// set.
+func newOAuthVerifier(cfg oauthConfig, publicURL string) *oauthVerifier {
+	issuer := strings.TrimRight(cfg.Issuer, "/")
+	if issuer == "" {
+		return nil
+	}
+	ov := &oauthVerifier{
+		issuer:        issuer,
+		audience:      cfg.Audience,
+		jwksURL:       cfg.JWKSURL,
+		requiredScope: cfg.RequiredScope,
+		required:      cfg.Required,
+		metadataURL:   strings.TrimRight(publicURL, "/") + oauthMetadataPath,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		keys:          make(map[string]crypto.PublicKey),
+	}
+	if ov.audience == "" {
+		ov.audience = publicURL
+	}
+	log.Printf("OAuth: accepting tokens from %s for %s", ov.issuer, ov.audience)
+	return ov
+}
+
+// metadataHandler serves the protected resource metadata document.
+func (ov *oauthVerifier) metadataHandler(w http.ResponseWriter, r *http.Request) {
+	meta := map[string]interface{}{
+		"resource":                 ov.audience,
+		"authorization_servers":    []string{ov.issuer},
+		"bearer_methods_supported": []string{"header"},
+		"resource_name":            "LiveScore MCP",
+	}
+	if ov.requiredScope != "" {
+		meta["scopes_supported"] = []string{ov.requiredScope}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(meta)
+}
+
+func (ov *oauthVerifier) challenge() string {
+	return fmt.Sprintf(`Bearer resource_metadata="%s"`, ov.metadataURL)
+}
+
+type jwtClaims struct {
+	Issuer    string          `json:"iss"`
+	Subject   string          `json:"sub"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt int64           `json:"exp"`
+	NotBefore int64           `json:"nbf"`
+	Scope     string          `json:"scope"`
+	Scopes    []string        `json:"scp"`
+}
+
+func (c jwtClaims) audiences() []string {
+	var one string
+	if json.Unmarshal(c.Audience, &one) == nil {
+		return []string{one}
+	}
+	var many []string
+	json.Unmarshal(c.Audience, &many)
+	return many
+}
+
+func (c jwtClaims) hasScope(scope string) bool {
+	return slices.Contains(strings.Fields(c.Scope), scope) || slices.Contains(c.Scopes, scope)
+}
+
+// verify checks a JWT access token and returns the client it identifies.
+func (ov *oauthVerifier) verify(token string) (apiKey, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return apiKey{}, fmt.Errorf("not a JWT")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return apiKey{}, fmt.Errorf("bad header: %v", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return apiKey{}, fmt.Errorf("bad signature encoding: %v", err)
+	}
+	key, err := ov.key(header.Kid)
+	if err != nil {
+		return apiKey{}, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if header.Alg != "RS256" || rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) != nil {
+			return apiKey{}, fmt.Errorf("invalid signature")
+		}
+	case *ecdsa.PublicKey:
+		if header.Alg != "ES256" || len(sig) != 64 ||
+			!ecdsa.Verify(pub, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+			return apiKey{}, fmt.Errorf("invalid signature")
+		}
+	default:
+		return apiKey{}, fmt.Errorf("unsupported key type")
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return apiKey{}, fmt.Errorf("bad claims: %v", err)
+	}
+	now := time.Now().Unix()
+	switch {
+	case strings.TrimRight(claims.Issuer, "/") != ov.issuer:
+		return apiKey{}, fmt.Errorf("wrong issuer %q", claims.Issuer)
+	case !slices.Contains(claims.audiences(), ov.audience):
+		return apiKey{}, fmt.Errorf("token not issued for %s", ov.audience)
+	case claims.ExpiresAt == 0 || now >= claims.ExpiresAt:
+		return apiKey{}, fmt.Errorf("token expired")
+	case claims.NotBefore != 0 && now < claims.NotBefore:
+		return apiKey{}, fmt.Errorf("token not yet valid")
+	case ov.requiredScope != "" && !claims.hasScope(ov.requiredScope):
+		return apiKey{}, fmt.Errorf("missing scope %s", ov.requiredScope)
+	}
+	return apiKey{Name: "oauth:" + claims.Subject, Tier: tierFree}, nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// key returns the signing key for kid, refreshing the JWKS when the key is
+// unknown (at most every jwksMinRefresh).
+func (ov *oauthVerifier) key(kid string) (crypto.PublicKey, error) {
+	ov.mu.Lock()
+	defer ov.mu.Unlock()
+	if k, ok := ov.keys[kid]; ok {
+		return k, nil
+	}
+	if time.Since(ov.fetched) < jwksMinRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	ov.fetched = time.Now()
+	keys, err := ov.fetchJWKS()
+	if err != nil {
+		return nil, fmt.Errorf("JWKS: %v", err)
+	}
+	ov.keys = keys
+	if k, ok := ov.keys[kid]; ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (ov *oauthVerifier) getJSON(target string, v interface{}) error {
+	resp, err := ov.client.Get(target)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", target, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// fetchJWKS loads the issuer's signing keys, discovering the JWKS URL from
+// the authorization server metadata when OAUTH_JWKS_URL is not set.
+func (ov *oauthVerifier) fetchJWKS() (map[string]crypto.PublicKey, error) {
+	if ov.jwksURL == "" {
+		var meta struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		for _, path := range []string{"/.well-known/oauth-authorization-server", "/.well-known/openid-configuration"} {
+			if ov.getJSON(ov.issuer+path, &meta) == nil && meta.JWKSURI != "" {
+				ov.jwksURL = meta.JWKSURI
+				break
+			}
+		}
+		if ov.jwksURL == "" {
+			return nil, fmt.Errorf("no jwks_uri in metadata of %s", ov.issuer)
+		}
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := ov.getJSON(ov.jwksURL, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey)
+	b64 := func(s string) *big.Int {
+		data, _ := base64.RawURLEncoding.DecodeString(s)
+		return new(big.Int).SetBytes(data)
+	}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch {
+		case k.Kty == "RSA":
+			keys[k.Kid] = &rsa.PublicKey{N: b64(k.N), E: int(b64(k.E).Int64())}
+		case k.Kty == "EC" && k.Crv == "P-256":
+			keys[k.Kid] = &ecdsa.PublicKey{Curve: elliptic.P256(), X: b64(k.X), Y: b64(k.Y)}
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// --- Open Graph Images ---
+//
+// /og/match/{id}.png renders a 1200×630 share image with both team logos,
+// the score and the match status, for og:image and twitter:image tags on
+// pages that link to a match. Images of live matches are re-rendered every
+// minute; finished matches are kept for a day.
+
+const (
+	ogWidth       = 1200
+	ogHeight      = 630
+	ogBadgeSize   = 220
+	ogLiveTTL     = time.Minute
+	ogUpcomingTTL = 15 * time.Minute
+)
+
+var (
+	ogBackground = color.NRGBA{0x0b, 0x3d, 0x2e, 0xff}
+	ogAccent     = color.NRGBA{0x12, 0x5c, 0x45, 0xff}
+	ogText       = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+	ogMuted      = color.NRGBA{0xb8, 0xd8, 0xc8, 0xff}
+)
+
+var ogFonts struct {
+	once    sync.Once
+	regular *opentype.Font
+	bold    *opentype.Font
+}
+
+func ogFace(bold bool, size float64) font.Face {
+	ogFonts.once.Do(func() {
+		ogFonts.regular, _ = opentype.Parse(goregular.TTF)
+		ogFonts.bold, _ = opentype.Parse(gobold.TTF)
+	})
+	f := ogFonts.regular
+	if bold {
+		f = ogFonts.bold
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		panic(err) // the embedded Go fonts always parse
+	}
+	return face
+}
+
+// drawCentered writes text centered on x with its baseline at y, shrinking
+// the font until it fits maxWidth.
+func drawCentered(dst draw.Image, text string, x, y, maxWidth int, bold bool, size float64, c color.Color) {
+	face := ogFace(bold, size)
+	for size > 12 && font.MeasureString(face, text).Ceil() > maxWidth {
+		face.Close()
+		size -= 4
+		face = ogFace(bold, size)
+	}
+	defer face.Close()
+	d := &font.Drawer{Dst: dst, Src: image.NewUniform(c), Face: face}
+	d.Dot = fixed.P(x-d.MeasureString(text).Ceil()/2, y)
+	d.DrawString(text)
+}
+
+func fillDisc(dst *image.NRGBA, cx, cy, r int, c color.NRGBA) {
+	for y := cy - r; y <= cy+r; y++ {
+		for x := cx - r; x <= cx+r; x++ {
+			if dx, dy := x-cx, y-cy; dx*dx+dy*dy <= r*r {
+				dst.SetNRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+// drawBadge draws the team logo centered on (cx, cy), or a disc with the
+// team's initials when no logo is available.
+func drawBadge(ctx context.Context, dst *image.NRGBA, ic *imageCache, id, name string, cx, cy int) {
+	if id != "" && imageIDPattern.MatchString(id) {
+		if logo, err := ic.teamLogo(ctx, id, ogBadgeSize); err == nil && logo.data != nil {
+			if src, err := png.Decode(bytes.NewReader(logo.data)); err == nil {
+				b := src.Bounds()
+				at := image.Pt(cx-b.Dx()/2, cy-b.Dy()/2)
+				draw.Draw(dst, image.Rectangle{at, at.Add(b.Size())}, src, b.Min, draw.Over)
+				return
+			}
+		}
+	}
+	fillDisc(dst, cx, cy, ogBadgeSize/2, ogAccent)
+	var initials []rune
+	for _, word := range strings.Fields(name) {
+		initials = append(initials, []rune(strings.ToUpper(word))[0])
+		if len(initials) == 3 {
+			break
+		}
+	}
+	drawCentered(dst, string(initials), cx, cy+28, ogBadgeSize-40, true, 80, ogText)
+}
+
+// renderMatchOG draws the share image for a match.
+func renderMatchOG(ctx context.Context, ic *imageCache, fm feedMatch, footer string) ([]byte, error) {
+	img := image.NewNRGBA(image.Rect(0, 0, ogWidth, ogHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(ogBackground), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, ogHeight-70, ogWidth, ogHeight), image.NewUniform(ogAccent), image.Point{}, draw.Src)
+
+	header := fm.LeagueName
+	if header == "" {
+		header = fm.LeagueKey
+	}
+	if fm.Round != "" {
+		header = strings.TrimSpace(header + " · Round " + fm.Round)
+	}
+	drawCentered(img, header, ogWidth/2, 80, ogWidth-120, false, 40, ogMuted)
+
+	const badgeY = 280
+	drawBadge(ctx, img, ic, fm.HomeID, fm.HomeName, 250, badgeY)
+	drawBadge(ctx, img, ic, fm.AwayID, fm.AwayName, ogWidth-250, badgeY)
+	drawCentered(img, fm.HomeName, 250, badgeY+ogBadgeSize/2+70, 400, true, 44, ogText)
+	drawCentered(img, fm.AwayName, ogWidth-250, badgeY+ogBadgeSize/2+70, 400, true, 44, ogText)
+
+	center := "vs"
+	if fm.HasScore {
+		center = fmt.Sprintf("%d - %d", fm.HomeGoals, fm.AwayGoals)
+	}
+	drawCentered(img, center, ogWidth/2, badgeY+50, 380, true, 140, ogText)
+
+	status := fm.Status
+	if kickoff, ok := fm.kickoff(); ok && (status == "" || strings.EqualFold(status, "NS")) {
+		status = kickoff.Format("2 Jan 15:04") + " UTC"
+	}
+	drawCentered(img, status, ogWidth/2, badgeY+130, 380, false, 44, ogMuted)
+	drawCentered(img, footer, ogWidth/2, ogHeight-22, ogWidth-120, false, 30, ogText)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func registerOGRoutes(mux *http.ServeMux, ic *imageCache, publicURL string, limit func(http.HandlerFunc) http.HandlerFunc) {
+	footer := strings.TrimPrefix(strings.TrimPrefix(publicURL, "https://"), "http://")
+
+	mux.HandleFunc("GET /og/match/{file}", limit(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := strings.CutSuffix(r.PathValue("file"), ".png")
+		if !ok || !imageIDPattern.MatchString(id) {
+			http.NotFound(w, r)
+			return
+		}
+		key := "og:match:" + id
+		img, ok := ic.get(key, time.Now())
+		if !ok {
+			data, err := publicMatches.fetch(r.Context(), id)
+			if err != nil {
+				http.Error(w, "match unavailable", http.StatusBadGateway)
+				return
+			}
+			fm, found := primaryMatch(data)
+			if !found {
+				http.NotFound(w, r)
+				return
+			}
+			rendered, err := renderMatchOG(r.Context(), ic, fm, footer)
+			if err != nil {
+				log.Printf("OG image: match %s: %v", id, err)
+				http.Error(w, "render failed", http.StatusInternalServerError)
+				return
+			}
+			img = &cachedImage{data: rendered, fetched: time.Now(), ttl: ogLiveTTL}
+			if fm.finished() {
+				img.ttl = imageCacheTTL
+			} else if !fm.HasScore {
+				img.ttl = ogUpcomingTTL
+			}
+			ic.put(key, img)
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(img.ttl.Seconds())))
+		w.Header().Set("Cross-Origin-Resource-Policy", "cross-origin")
+		w.Write(img.data)
+	}))
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Player Statistics ---
+//
+// The player endpoint returns a profile, the current season's numbers, a
+// career list whose fields vary by player and, for some players, their
+// transfers. The tools here turn it into fixed rows. Numbers upstream does
+// not give are null rather than 0, so "no assists recorded" is not mistaken
+// for "no assists". Goals and assists also come per 90 minutes where the
+// minutes are known, so starters and substitutes compare fairly.
+
+var (
+	seasonKeys      = []string{"season", "year", "seasonname"}
+	competitionKeys = []string{"competition", "league", "competitionname", "leaguename", "tournament"}
+	appsKeys        = []string{"appearances", "apps", "matches", "played", "gamesplayed"}
+	goalsKeys       = []string{"goals", "goalsscored"}
+	assistsKeys     = []string{"assists"}
+	minutesKeys     = []string{"minutes", "minutesplayed", "mins"}
+)
+
+// per90Minimum is the playing time below which per-90 rates are left out,
+// as one goal in a 10-minute cameo would read as 9 per 90.
+const per90Minimum = 90
+
+// careerRow is one season at one club, and competition where upstream
+// splits them.
+type careerRow struct {
+	Season       string   `json:"season"`
+	TeamID       string   `json:"team_id,omitempty"`
+	Team         string   `json:"team"`
+	Competition  string   `json:"competition,omitempty"`
+	Appearances  *int     `json:"appearances"`
+	Goals        *int     `json:"goals"`
+	Assists      *int     `json:"assists"`
+	Minutes      *int     `json:"minutes"`
+	GoalsPer90   *float64 `json:"goals_per_90"`
+	AssistsPer90 *float64 `json:"assists_per_90"`
+}
+
+// per90 returns stat per 90 minutes played, or nil when either is unknown
+// or the minutes are below per90Minimum.
+func per90(stat, minutes *int) *float64 {
+	if stat == nil || minutes == nil || *minutes < per90Minimum {
+		return nil
+	}
+	rate := math.Round(float64(*stat)*90/float64(*minutes)*100) / 100
+	return &rate
+}
+
+// optInt is lookupInt as a pointer, nil when the field is missing.
+func optInt(m map[string]interface{}, keys ...string) *int {
+	if n, ok := lookupInt(m, keys...); ok {
+		return &n
+	}
+	return nil
+}
+
+// nameOrString reads a field given either as a name or as an object with one.
+func nameOrString(m map[string]interface{}, keys ...string) (id, name string) {
+	v, ok := lookup(m, keys...)
+	if !ok {
+		return "", ""
+	}
+	if obj, ok := v.(map[string]interface{}); ok {
+		return lookupStr(obj, teamIDKeys...), lookupStr(obj, teamNameKeys...)
+	}
+	return "", scalarString(v)
+}
+
+// extractCareer returns a player's season-by-season rows, in upstream order
+// (latest first). The current season's row is completed from the season
+// summary, which has the assists and minutes the career list often lacks.
+func extractCareer(data interface{}) []careerRow {
+	root, _ := data.(map[string]interface{})
+	current, _ := lookup(root, "season", "currentseason", "stats")
+	summary, _ := current.(map[string]interface{})
+	_, currentTeam := nameOrString(root, "team", "club")
+
+	v, _ := findKey(data, "career", "history", "seasons")
+	items, _ := v.([]interface{})
+	rows := []careerRow{}
+	for _, item := range items {
+		e, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		teamID, team := nameOrString(e, "team", "club", "teamname")
+		_, comp := nameOrString(e, competitionKeys...)
+		row := careerRow{
+			Season:      lookupStr(e, seasonKeys...),
+			TeamID:      teamID,
+			Team:        team,
+			Competition: comp,
+			Appearances: optInt(e, appsKeys...),
+			Goals:       optInt(e, goalsKeys...),
+			Assists:     optInt(e, assistsKeys...),
+			Minutes:     optInt(e, minutesKeys...),
+		}
+		if summary != nil && row.Season == lookupStr(summary, seasonKeys...) && team == currentTeam {
+			if row.Assists == nil {
+				row.Assists = optInt(summary, assistsKeys...)
+			}
+			if row.Minutes == nil {
+				row.Minutes = optInt(summary, minutesKeys...)
+			}
+		}
+		row.GoalsPer90, row.AssistsPer90 = per90(row.Goals, row.Minutes), per90(row.Assists, row.Minutes)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// transferRow is one move between clubs. Date and fee are empty when
+// upstream has no transfer list and the move is read off the career, where
+// Season is the first season at the new club.
+type transferRow struct {
+	Date   string `json:"date,omitempty"`
+	Season string `json:"season,omitempty"`
+	FromID string `json:"from_id,omitempty"`
+	From   string `json:"from"`
+	ToID   string `json:"to_id,omitempty"`
+	To     string `json:"to"`
+	Fee    string `json:"fee,omitempty"`
+	Type   string `json:"type,omitempty"` // transfer, loan, free...
+}
+
+// extractTransfers returns a player's moves, oldest first, and whether they
+// came from upstream's transfer list rather than the career.
+func extractTransfers(data interface{}) (rows []transferRow, listed bool) {
+	rows = []transferRow{}
+	if v, ok := findKey(data, "transfers", "transferhistory"); ok {
+		items, _ := v.([]interface{})
+		for _, item := range items {
+			e, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fromID, from := nameOrString(e, "from", "fromteam", "fromclub")
+			toID, to := nameOrString(e, "to", "toteam", "toclub")
+			rows = append(rows, transferRow{
+				Date:   lookupStr(e, "date", "transferdate"),
+				Season: lookupStr(e, seasonKeys...),
+				FromID: fromID,
+				From:   from,
+				ToID:   toID,
+				To:     to,
+				Fee:    lookupStr(e, "fee", "transferfee", "amount"),
+				Type:   lookupStr(e, "type", "transfertype"),
+			})
+		}
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].Date < rows[j].Date })
+		return rows, true
+	}
+
+	career := extractCareer(data)
+	for i := len(career) - 1; i > 0; i-- {
+		prev, next := career[i], career[i-1]
+		if prev.Team == next.Team || prev.Team == "" || next.Team == "" {
+			continue
+		}
+		rows = append(rows, transferRow{Season: next.Season, FromID: prev.TeamID, From: prev.Team, ToID: next.TeamID, To: next.Team})
+	}
+	return rows, false
+}
+
+func careerTable(data interface{}, format string) ([]string, [][]string) {
+	num := func(n interface{}) string {
+		if f, ok := n.(float64); ok {
+			return strconv.Itoa(int(f))
+		}
+		return ""
+	}
+	rate := func(n interface{}) string {
+		if f, ok := n.(float64); ok {
+			return strconv.FormatFloat(f, 'f', 2, 64)
+		}
+		return ""
+	}
+	list, _ := data.([]interface{})
+	var rows [][]string
+	for _, item := range list {
+		r, _ := item.(map[string]interface{})
+		team := scalarString(r["team"])
+		if format == "markdown" {
+			if comp := scalarString(r["competition"]); comp != "" {
+				team += " (" + comp + ")"
+			}
+			rows = append(rows, []string{scalarString(r["season"]), team,
+				num(r["appearances"]), num(r["goals"]), num(r["assists"]), num(r["minutes"]), rate(r["goals_per_90"]), rate(r["assists_per_90"])})
+			continue
+		}
+		rows = append(rows, []string{scalarString(r["season"]), scalarString(r["team_id"]), team,
+			scalarString(r["competition"]), num(r["appearances"]), num(r["goals"]), num(r["assists"]), num(r["minutes"]),
+			rate(r["goals_per_90"]), rate(r["assists_per_90"])})
+	}
+	if format == "markdown" {
+		return []string{"Season", "Club", "Apps", "Goals", "Assists", "Minutes", "G/90", "A/90"}, rows
+	}
+	return []string{"season", "team_id", "team", "competition", "appearances", "goals", "assists", "minutes", "goals_per_90", "assists_per_90"}, rows
+}
+
+func registerPlayerTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_player_career_stats",
+			mcp.WithDescription("Get a player's career season by season: club, competition, appearances, goals, assists and minutes. Numbers upstream does not record are null"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Player ID (e.g. 474972)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+			formatOption,
+		),
+		withFormats(careerTable, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "id", "")
+			data, err := source.Player(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			rows := extractCareer(data)
+			if len(rows) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no career history found for player %s", id)), nil
+			}
+			return jsonResult(fmt.Sprintf("Career stats for player %s", id), rows), nil
+		}),
+	)
+
+	s.AddTool(
+		mcp.NewTool("get_player_transfer_history",
+			mcp.WithDescription("Get a player's moves between clubs, oldest first, with dates, fees and loan or free transfer type where upstream has them. Without upstream transfer data the moves are read off the career by season"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Player ID (e.g. 474972)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "id", "")
+			data, err := source.Player(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			rows, listed := extractTransfers(data)
+			title := fmt.Sprintf("Transfer history for player %s", id)
+			if !listed {
+				title += " (from career seasons; dates and fees not available)"
+			}
+			return jsonResult(title, rows), nil
+		},
+	)
+}
+
+// withPer90 adds goals and assists per 90 minutes to the season summary of
+// a player payload.
+func withPer90(data interface{}) interface{} {
+	root, _ := data.(map[string]interface{})
+	current, _ := lookup(root, "season", "currentseason", "stats")
+	summary, ok := current.(map[string]interface{})
+	if !ok {
+		return data
+	}
+	minutes := optInt(summary, minutesKeys...)
+	summary["per_90"] = map[string]*float64{
+		"goals":   per90(optInt(summary, goalsKeys...), minutes),
+		"assists": per90(optInt(summary, assistsKeys...), minutes),
+	}
+	return data
+}
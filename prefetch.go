@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// --- Prefetcher ---
+//
+// The live feed and today's fixtures are what most tool calls ask for, so
+// they are refreshed in the background every few seconds and fetchUpstream
+// answers them from memory instead of waiting on upstream. Both feeds are
+// kept warm in the default language from startup; other supported languages
+// and whole-quarter timezone offsets are added when a client asks for them
+// and dropped again once nobody has for a while. Other variants (unknown
+// languages, API versions, odd offsets) are fetched as usual rather than
+// kept warm, and at most maxPrefetchFeeds feeds are refreshed at once.
+
+const (
+	prefetchInterval = 15 * time.Second
+	prefetchIdle     = 10 * time.Minute // drop variants nobody asked for since
+	prefetchTimeout  = 10 * time.Second
+	maxPrefetchFeeds = 32
+)
+
+type prefetchedFeed struct {
+	body      []byte
+	fetched   time.Time
+	requested time.Time
+}
+
+type prefetcher struct {
+	mu       sync.Mutex
+	feeds    map[string]*prefetchedFeed // by upstream URL
+	interval atomic.Int64               // refresh interval, a time.Duration
+	served   atomic.Int64               // requests answered from memory
+}
+
+// hotFeeds is shared by every upstream call, like upstreamClient.
+var hotFeeds = newPrefetcher(prefetchInterval)
+
+func newPrefetcher(interval time.Duration) *prefetcher {
+	p := &prefetcher{feeds: make(map[string]*prefetchedFeed)}
+	p.setInterval(interval)
+	return p
+}
+
+func (p *prefetcher) setInterval(d time.Duration) { p.interval.Store(int64(d)) }
+
+// isHotFeed reports whether apiURL is the live feed or today's fixtures, in
+// a supported language, the default API version and, for the fixtures, a
+// timezone offset of whole quarters of an hour.
+func isHotFeed(apiURL string, now time.Time) bool {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return false
+	}
+	q := u.Query()
+	if !supportedLanguage(q.Get("lang")) || q.Get("version") != strconv.Itoa(defaultVersion) {
+		return false
+	}
+	switch path.Base(u.Path) {
+	case "feed_livenow.json":
+		return len(q) == 2
+	case "feed_matches_aggregated.json":
+		offset, err := strconv.Atoi(q.Get("tzoffset"))
+		return len(q) == 4 && q.Get("date") == now.UTC().Format("02/01/2006") &&
+			err == nil && offset%15 == 0 && offset >= -12*60 && offset <= 14*60
+	}
+	return false
+}
+
+// get returns the prefetched body of a hot feed if it is fresh, and keeps
+// the feed on the refresh list either way.
+func (p *prefetcher) get(apiURL string, now time.Time) ([]byte, bool) {
+	if !isHotFeed(apiURL, now) {
+		return nil, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f, ok := p.feeds[apiURL]
+	if !ok {
+		if len(p.feeds) >= maxPrefetchFeeds {
+			return nil, false
+		}
+		f = &prefetchedFeed{}
+		p.feeds[apiURL] = f
+	}
+	f.requested = now
+	if f.body == nil || now.Sub(f.fetched) > 2*time.Duration(p.interval.Load()) {
+		return nil, false
+	}
+	p.served.Add(1)
+	return f.body, true
+}
+
+func (p *prefetcher) run() {
+	for {
+		p.refresh(time.Now())
+		time.Sleep(time.Duration(p.interval.Load()))
+	}
+}
+
+// refresh re-fetches every feed on the refresh list.
+func (p *prefetcher) refresh(now time.Time) {
+	defaults := []string{
+		buildURL("fixtures/feed_livenow.json", nil),
+		buildURL("fixtures/feed_matches_aggregated.json", nil, "date", now.UTC().Format("02/01/2006"), "tzoffset", "0"),
+	}
+	p.mu.Lock()
+	for _, u := range defaults {
+		if f, ok := p.feeds[u]; ok {
+			f.requested = now
+		} else {
+			p.feeds[u] = &prefetchedFeed{requested: now}
+		}
+	}
+	var urls []string
+	for u, f := range p.feeds {
+		if now.Sub(f.requested) > prefetchIdle || !isHotFeed(u, now) {
+			delete(p.feeds, u)
+			continue
+		}
+		urls = append(urls, u)
+	}
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, u := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), prefetchTimeout)
+			defer cancel()
+			body, err := requestUpstream(ctx, u)
+			if err != nil {
+				log.Printf("Prefetch: %v", err)
+				return
+			}
+			p.mu.Lock()
+			if f, ok := p.feeds[u]; ok {
+				f.body, f.fetched = body, time.Now()
+			}
+			p.mu.Unlock()
+		}()
+	}
+	wg.Wait()
+}
+
+// stats reports the number of feeds kept warm and how many requests they
+// answered.
+func (p *prefetcher) stats() (feeds int, served int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.feeds), p.served.Load()
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Prompts ---
+
+// maxPromptSection caps each data section embedded in a prompt so a single
+// oversized upstream payload cannot crowd out the rest of the context.
+const maxPromptSection = 20000
+
+// promptSection renders data as an indented JSON block under a heading.
+func promptSection(heading string, data interface{}) string {
+	pretty, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("## %s\n\n(unavailable: %v)\n", heading, err)
+	}
+	text := string(pretty)
+	if len(text) > maxPromptSection {
+		text = text[:maxPromptSection] + "\n... (truncated)"
+	}
+	return fmt.Sprintf("## %s\n\n```json\n%s\n```\n", heading, text)
+}
+
+// fetchSection renders fetched data as a prompt section, or a short note if
+// the fetch failed.
+func fetchSection(heading string, data interface{}, err error) string {
+	if err != nil {
+		return fmt.Sprintf("## %s\n\n(unavailable: %v)\n", heading, err)
+	}
+	return promptSection(heading, data)
+}
+
+func promptArgs(req mcp.GetPromptRequest) map[string]interface{} {
+	args := make(map[string]interface{}, len(req.Params.Arguments))
+	for k, v := range req.Params.Arguments {
+		args[k] = v
+	}
+	return args
+}
+
+func registerPrompts(s *server.MCPServer) {
+	// Match preview
+	s.AddPrompt(
+		mcp.NewPrompt("match_preview",
+			mcp.WithPromptDescription("Pre-match preview built from head-to-head record, both teams' form, injuries and standings"),
+			mcp.WithArgument("match_id", mcp.RequiredArgument(), mcp.ArgumentDescription("Match ID from fixtures or live scores")),
+			mcp.WithArgument("language", mcp.ArgumentDescription("Language code for upstream data (en, nl, de, etc.). Default: en")),
+		),
+		func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			id := req.Params.Arguments["match_id"]
+			if id == "" {
+				return nil, fmt.Errorf("match_id is required")
+			}
+			args := promptArgs(req)
+
+			match, err := source.Match(ctx, id, true, queryOf(args))
+			if err != nil {
+				return nil, fmt.Errorf("match %s: %v", id, err)
+			}
+
+			var b strings.Builder
+			b.WriteString("Write a pre-match preview for the football match below. Cover:\n")
+			b.WriteString("1. Head-to-head record and notable past meetings\n")
+			b.WriteString("2. Current form of both teams (recent results)\n")
+			b.WriteString("3. Injuries, suspensions and likely absentees\n")
+			b.WriteString("4. League standing and what is at stake\n")
+			b.WriteString("5. A short prediction with reasoning\n\n")
+			b.WriteString("Only use facts present in the data. All timestamps are GMT/UTC.\n\n")
+			b.WriteString(promptSection("Match and head-to-head", match))
+
+			if m, ok := primaryMatch(match); ok {
+				for _, side := range []struct{ label, id, name string }{
+					{"Home team", m.HomeID, m.HomeName},
+					{"Away team", m.AwayID, m.AwayName},
+				} {
+					if side.id == "" {
+						continue
+					}
+					heading := fmt.Sprintf("%s: %s (form, squad, injuries, standings)", side.label, side.name)
+					b.WriteString("\n")
+					team, err := source.Team(ctx, side.id, queryOf(args))
+					b.WriteString(fetchSection(heading, team, err))
+				}
+			}
+
+			return mcp.NewGetPromptResult(
+				fmt.Sprintf("Match preview for match %s", id),
+				[]mcp.PromptMessage{
+					mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+				},
+			), nil
+		},
+	)
+
+	// Daily digest
+	s.AddPrompt(
+		mcp.NewPrompt("daily_digest",
+			mcp.WithPromptDescription("Morning football briefing: today's fixtures and yesterday's results for a set of leagues"),
+			mcp.WithArgument("leagues", mcp.ArgumentDescription("Comma-separated league keys (e.g. EnglandPremierLeague,NetherlandsEredivisie). Default: all leagues")),
+			mcp.WithArgument("date", mcp.ArgumentDescription("Digest date in DD/MM/YYYY format. Default: today (UTC)")),
+			mcp.WithArgument("language", mcp.ArgumentDescription("Language code for upstream data (en, nl, de, etc.). Default: en")),
+		),
+		func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			args := promptArgs(req)
+			day := time.Now().UTC()
+			if d := req.Params.Arguments["date"]; d != "" {
+				parsed, err := time.Parse("02/01/2006", d)
+				if err != nil {
+					return nil, fmt.Errorf("invalid date %q: expected DD/MM/YYYY", d)
+				}
+				day = parsed
+			}
+
+			var leagues []string
+			for _, l := range strings.Split(req.Params.Arguments["leagues"], ",") {
+				if l = strings.TrimSpace(l); l != "" {
+					leagues = append(leagues, l)
+				}
+			}
+			inLeagues := func(m feedMatch) bool {
+				if len(leagues) == 0 {
+					return true
+				}
+				for _, l := range leagues {
+					if m.inLeague(l) {
+						return true
+					}
+				}
+				return false
+			}
+
+			daySection := func(heading string, d time.Time) string {
+				data, err := source.Fixtures(ctx, d.Format("02/01/2006"), 0, queryOf(args))
+				if err != nil {
+					return fmt.Sprintf("## %s\n\n(unavailable: %v)\n", heading, err)
+				}
+				return promptSection(heading, filterFeed(data, inLeagues))
+			}
+
+			scope := "all leagues"
+			if len(leagues) > 0 {
+				scope = strings.Join(leagues, ", ")
+			}
+
+			var b strings.Builder
+			fmt.Fprintf(&b, "Write a concise morning football briefing for %s covering %s.\n\n", day.Format("Monday 2 January 2006"), scope)
+			b.WriteString("Structure it as:\n")
+			b.WriteString("1. Yesterday's results - final scores grouped by league, with the standout results first\n")
+			b.WriteString("2. Today's fixtures - kickoff times (UTC) grouped by league, highlighting the biggest matches\n")
+			b.WriteString("3. One or two storylines worth watching today\n\n")
+			b.WriteString("Only use facts present in the data. All timestamps are GMT/UTC.\n\n")
+			b.WriteString(daySection(fmt.Sprintf("Yesterday's results (%s)", day.AddDate(0, 0, -1).Format("02/01/2006")), day.AddDate(0, 0, -1)))
+			b.WriteString("\n")
+			b.WriteString(daySection(fmt.Sprintf("Today's fixtures (%s)", day.Format("02/01/2006")), day))
+
+			return mcp.NewGetPromptResult(
+				fmt.Sprintf("Daily digest for %s", day.Format("02/01/2006")),
+				[]mcp.PromptMessage{
+					mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+				},
+			), nil
+		},
+	)
+
+	// League roundup
+	s.AddPrompt(
+		mcp.NewPrompt("league_roundup",
+			mcp.WithPromptDescription("Matchweek roundup for a league: the round's results, scorers and the updated table"),
+			mcp.WithArgument("league_key", mcp.RequiredArgument(), mcp.ArgumentDescription("League key from search results (e.g. NetherlandsEredivisie)")),
+			mcp.WithArgument("matchweek", mcp.RequiredArgument(), mcp.ArgumentDescription("Round / matchweek number (e.g. 24)")),
+			mcp.WithArgument("language", mcp.ArgumentDescription("Language code for upstream data (en, nl, de, etc.). Default: en")),
+		),
+		func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			key := req.Params.Arguments["league_key"]
+			round := req.Params.Arguments["matchweek"]
+			if key == "" || round == "" {
+				return nil, fmt.Errorf("league_key and matchweek are required")
+			}
+
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(promptArgs(req)))
+			if err != nil {
+				return nil, fmt.Errorf("league %s: %v", key, err)
+			}
+
+			var b strings.Builder
+			fmt.Fprintf(&b, "Write a roundup of matchweek %s in %s. Cover:\n", round, key)
+			b.WriteString("1. Every result, leading with the most significant\n")
+			b.WriteString("2. Goal scorers and standout performers (from the match events)\n")
+			b.WriteString("3. How the table changed at the top and bottom\n")
+			b.WriteString("4. A look ahead to the next round\n\n")
+			b.WriteString("Only use facts present in the data. All timestamps are GMT/UTC.\n\n")
+			b.WriteString(promptSection(fmt.Sprintf("Matchweek %s results and events", round), filterFeed(data, func(m feedMatch) bool {
+				return m.inRound(round)
+			})))
+			if table, ok := findKey(data, "standings", "table", "tables", "leaguetable"); ok {
+				b.WriteString("\n")
+				b.WriteString(promptSection("League table", table))
+			} else {
+				b.WriteString("\n## League table\n\n(not included in the league feed - derive it from results only if the user asks)\n")
+			}
+
+			return mcp.NewGetPromptResult(
+				fmt.Sprintf("Matchweek %s roundup for %s", round, key),
+				[]mcp.PromptMessage{
+					mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+				},
+			), nil
+		},
+	)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Usage Metering ---
+
+// rateClient is the rate limit identity of a request, as chosen by the rate
+// limiter: the API key id or the client IP, the name shown for it, and its
+// tier.
+type rateClient struct {
+	ID   string
+	Name string
+	Tier string
+}
+
+type rateClientKey struct{}
+
+type usageCount struct {
+	Allowed  int64 `json:"allowed"`
+	Rejected int64 `json:"rejected"`
+}
+
+// usageMeter counts requests per client for the current UTC day.
+type usageMeter struct {
+	mu     sync.Mutex
+	day    string
+	counts map[string]*usageCount
+}
+
+func newUsageMeter() *usageMeter {
+	return &usageMeter{counts: make(map[string]*usageCount)}
+}
+
+// today must be called with um.mu held.
+func (um *usageMeter) today() map[string]*usageCount {
+	if day := time.Now().UTC().Format("2006-01-02"); day != um.day {
+		um.day = day
+		um.counts = make(map[string]*usageCount)
+	}
+	return um.counts
+}
+
+func (um *usageMeter) record(id string, allowed bool) {
+	um.mu.Lock()
+	defer um.mu.Unlock()
+	counts := um.today()
+	c := counts[id]
+	if c == nil {
+		c = &usageCount{}
+		counts[id] = c
+	}
+	if allowed {
+		c.Allowed++
+	} else {
+		c.Rejected++
+	}
+}
+
+func (um *usageMeter) get(id string) usageCount {
+	um.mu.Lock()
+	defer um.mu.Unlock()
+	if c := um.today()[id]; c != nil {
+		return *c
+	}
+	return usageCount{}
+}
+
+type quotaStatus struct {
+	Client         string     `json:"client"`
+	Tier           string     `json:"tier"`
+	LimitPerMinute float64    `json:"limit_per_minute"`
+	Burst          int        `json:"burst"`
+	Remaining      int        `json:"remaining"`
+	ResetAt        string     `json:"reset_at"`
+	Today          usageCount `json:"today"`
+	TodayResetsAt  string     `json:"today_resets_at"`
+}
+
+// quota reports the rate limit state of a client without consuming from it.
+func (rl *rateLimiter) quota(c rateClient) quotaStatus {
+	limiter := rl.getLimiter(c.ID, c.Tier)
+	now := time.Now().UTC()
+	remaining, full := bucketState(limiter, now)
+	return quotaStatus{
+		Client:         c.Name,
+		Tier:           c.Tier,
+		LimitPerMinute: float64(limiter.Limit()) * 60,
+		Burst:          limiter.Burst(),
+		Remaining:      remaining,
+		ResetAt:        full.Format(time.RFC3339),
+		Today:          rl.usage.get(c.ID),
+		TodayResetsAt:  now.Truncate(24 * time.Hour).Add(24 * time.Hour).Format(time.RFC3339),
+	}
+}
+
+func registerQuotaTools(s *server.MCPServer, rl *rateLimiter) {
+	// Quota
+	s.AddTool(
+		mcp.NewTool("get_my_quota",
+			mcp.WithDescription("Report this client's rate limit tier, remaining requests, when the allowance is fully restored and today's request counts, so calls can be paced instead of hitting 429 errors"),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			c, ok := ctx.Value(rateClientKey{}).(rateClient)
+			if !ok {
+				return toolErrorResult(codeSessionRequired, "quota is only tracked for requests over the /message endpoint"), nil
+			}
+			if c.Tier == tierExempt {
+				return mcp.NewToolResultText(fmt.Sprintf("Client %s is on a trusted network and not rate limited", c.Name)), nil
+			}
+			return jsonResult("Quota", rl.quota(c)), nil
+		},
+	)
+}
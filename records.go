@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- League Records ---
+//
+// get_league_records goes through a season's finished matches for its
+// records: biggest wins and highest-scoring match from the scores, fastest
+// and latest goals and the most cards from the events, which come from the
+// match pages of the latest maxSeasonMatches matches where the feed has
+// none. A record shared by several matches goes to the first to set it.
+
+type leagueRecord struct {
+	matchScore
+	Detail string `json:"detail"`
+}
+
+type seasonTotals struct {
+	Matches       int     `json:"matches"`
+	Goals         int     `json:"goals"`
+	GoalsPerMatch float64 `json:"goals_per_match"`
+	HomeWins      int     `json:"home_wins"`
+	Draws         int     `json:"draws"`
+	AwayWins      int     `json:"away_wins"`
+	GoallessDraws int     `json:"goalless_draws"`
+}
+
+// scoreRecords returns the season totals and the records set by scores.
+func scoreRecords(matches []feedMatch) (seasonTotals, map[string]leagueRecord) {
+	var totals seasonTotals
+	records := map[string]leagueRecord{}
+	best := map[string]int{}
+	set := func(name string, value int, fm feedMatch, detail string) {
+		if _, ok := records[name]; !ok || value > best[name] {
+			best[name] = value
+			records[name] = leagueRecord{scoreOf(fm), detail}
+		}
+	}
+	for _, fm := range matches {
+		goals := fm.HomeGoals + fm.AwayGoals
+		totals.Matches++
+		totals.Goals += goals
+		switch {
+		case fm.HomeGoals > fm.AwayGoals:
+			totals.HomeWins++
+		case fm.HomeGoals < fm.AwayGoals:
+			totals.AwayWins++
+		case goals == 0:
+			totals.GoallessDraws++
+			totals.Draws++
+		default:
+			totals.Draws++
+		}
+		margin := fm.HomeGoals - fm.AwayGoals
+		winner := fm.HomeName
+		if margin < 0 {
+			margin, winner = -margin, fm.AwayName
+			set("biggest_away_win", margin, fm, fmt.Sprintf("%s by %d", winner, margin))
+		}
+		if margin > 0 {
+			set("biggest_win", margin, fm, fmt.Sprintf("%s by %d", winner, margin))
+		}
+		set("highest_scoring", goals, fm, fmt.Sprintf("%d goals", goals))
+	}
+	if totals.Matches > 0 {
+		totals.GoalsPerMatch = round2(float64(totals.Goals) / float64(totals.Matches))
+	}
+	return totals, records
+}
+
+// eventRecords returns the records set by match events.
+func eventRecords(matches []feedMatch) map[string]leagueRecord {
+	records := map[string]leagueRecord{}
+	var fastest, latest, cards int
+	for _, fm := range matches {
+		yellow, red := fm.cardCounts()
+		if n := yellow + red; n > cards {
+			cards = n
+			records["most_cards"] = leagueRecord{scoreOf(fm), fmt.Sprintf("%d yellow, %d red", yellow, red)}
+		}
+		for _, e := range fm.events() {
+			minute, added, ok := e.minute()
+			if !e.goal() || !ok {
+				continue
+			}
+			// Added time sorts after the minute it is added to.
+			at := minute*100 + added
+			_, team := fm.eventTeam(e)
+			clock := strings.Trim(e.Minute, "' ")
+			detail := fmt.Sprintf("%s %s'", team, clock)
+			if e.Player != "" {
+				detail = fmt.Sprintf("%s (%s) %s'", e.Player, team, clock)
+			}
+			if _, ok := records["fastest_goal"]; !ok || at < fastest {
+				fastest = at
+				records["fastest_goal"] = leagueRecord{scoreOf(fm), detail}
+			}
+			if at > latest {
+				latest = at
+				records["latest_goal"] = leagueRecord{scoreOf(fm), detail}
+			}
+		}
+	}
+	return records
+}
+
+// byKickoff orders matches oldest first.
+func byKickoff(matches []feedMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		ti, _ := matches[i].kickoff()
+		tj, _ := matches[j].kickoff()
+		return ti.Before(tj)
+	})
+}
+
+func registerRecordTools(s *server.MCPServer, catalog *competitionCatalog) {
+	s.AddTool(
+		mcp.NewTool("get_league_records",
+			mcp.WithDescription("Get a league season's records: biggest win and away win, highest-scoring match, fastest and latest goal and most cards in a match, with season totals (goals per match, home wins, draws, away wins)"),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithString("season", mcp.Description("Season, e.g. 2024/2025, where upstream archives it. Default: the current season")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			season := getStr(req.Params.Arguments, "season", "")
+			data, err := source.Competition(ctx, leagueFeed(key), seasonQuery(req.Params.Arguments, season))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			if err := archivedSeason(data, key, season); err != nil {
+				return errorResult(err), nil
+			}
+			var scored []feedMatch
+			seen := map[string]bool{}
+			for _, fm := range extractMatches(data) {
+				if fm.finished() && fm.HasScore && (fm.ID == "" || !seen[fm.ID]) {
+					seen[fm.ID] = true
+					scored = append(scored, fm)
+				}
+			}
+			if len(scored) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no finished matches found for %s", key)), nil
+			}
+			withEvents, _ := finishedWith(ctx, req, data, maxSeasonMatches, func(fm feedMatch) bool {
+				_, ok := lookup(fm.Raw, "events", "incidents", "timeline")
+				return ok
+			})
+			byKickoff(scored)
+			byKickoff(withEvents)
+			totals, records := scoreRecords(scored)
+			for name, r := range eventRecords(withEvents) {
+				records[name] = r
+			}
+			root, _ := data.(map[string]interface{})
+			return jsonResult(fmt.Sprintf("Records of %s %s over %d finished matches (goal and card records from the %d with events)",
+				key, lookupStr(root, "season"), len(scored), len(withEvents)),
+				map[string]interface{}{"season": lookupStr(root, "season"), "totals": totals, "records": records}), nil
+		},
+	)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Red Cards ---
+//
+// get_red_cards lists the sendings-off of a day, or of the matches in play,
+// across leagues: straight reds and second yellows, leaving out cards
+// cancelled after a video review. A score says nothing about cards, so the
+// match pages of every started match without events in the feed are fetched,
+// as for get_goals, and the matches left without events are counted.
+
+type dismissal struct {
+	MatchID      string `json:"match_id"`
+	Match        string `json:"match"`
+	URI          string `json:"uri"`
+	League       string `json:"league,omitempty"`
+	Minute       string `json:"minute"`
+	Player       string `json:"player,omitempty"`
+	PlayerID     string `json:"player_id,omitempty"`
+	TeamID       string `json:"team_id,omitempty"`
+	Team         string `json:"team,omitempty"`
+	SecondYellow bool   `json:"second_yellow,omitempty"`
+
+	at time.Time
+}
+
+// dismissals lists the red cards of matches, in the order they were shown.
+func dismissals(matches []feedMatch) []dismissal {
+	out := []dismissal{}
+	for _, fm := range matches {
+		kickoff, _ := fm.kickoff()
+		for _, e := range fm.events() {
+			if !e.redCard() || e.VAR == varRedCardCancelled {
+				continue
+			}
+			teamID, team := fm.eventTeam(e)
+			out = append(out, dismissal{
+				MatchID:      fm.ID,
+				Match:        fmt.Sprintf("%s - %s", fm.HomeName, fm.AwayName),
+				URI:          "match://" + fm.ID,
+				League:       fm.LeagueName,
+				Minute:       e.Minute,
+				Player:       e.Player,
+				PlayerID:     e.PlayerID,
+				TeamID:       teamID,
+				Team:         team,
+				SecondYellow: strings.Contains(e.Type, "yellow"),
+				at:           eventTime(kickoff, e),
+			})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].at.Before(out[j].at) })
+	return out
+}
+
+func registerRedCardTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_red_cards",
+			mcp.WithDescription("Get every sending-off (straight red or second yellow) on a day across all leagues, or in the matches being played now with live=true: player, team, minute and a match:// link, in the order shown. Cards overturned by VAR are left out."),
+			mcp.WithString("date", mcp.Description("Date in DD/MM/YYYY format. Default: today (UTC)")),
+			mcp.WithBoolean("live", mcp.Description("Only matches in play now instead of a whole day. Default: false")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args := req.Params.Arguments
+			live := toMap(args)["live"] == true
+			var (
+				data interface{}
+				err  error
+				when = "in live matches"
+			)
+			if live {
+				data, err = source.LiveScores(ctx, queryOf(args))
+			} else {
+				day, dayErr := parseDay(getStr(args, "date", ""))
+				if dayErr != nil {
+					return toolErrorResult(codeInvalidArgument, dayErr.Error()), nil
+				}
+				date := day.Format("02/01/2006")
+				when = "on " + date
+				data, err = source.Fixtures(ctx, date, 0, queryOf(args))
+			}
+			if err != nil {
+				return errorResult(err), nil
+			}
+			matches, missing := withEvents(ctx, req, data, func(fm feedMatch) bool {
+				return fm.HasScore && (!live || !fm.finished())
+			})
+			cards := dismissals(matches)
+			return jsonResult(fmt.Sprintf("%d red cards %s (%d started matches had no events)", len(cards), when, missing),
+				map[string]interface{}{"red_cards": cards, "matches_without_events": missing}), nil
+		},
+	)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// --- Record / Replay ---
+//
+// MODE=record passes upstream requests through and saves every response
+// under RECORD_DIR; MODE=replay serves those recordings back without network
+// access. A recording is keyed by method, upstream path and query, so the
+// same tool call always finds the same response, which makes end-to-end runs
+// of every tool reproducible.
+
+const defaultRecordDir = "recordings"
+
+// recording is one upstream response as stored on disk.
+type recording struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// recordingPath maps a request to its file: the upstream path plus a short
+// hash of the method and query, e.g. fixtures/feed_livenow.json.3f9a2c1b.json.
+func recordingPath(dir string, r *http.Request) string {
+	sum := sha256.Sum256([]byte(r.Method + " " + r.URL.Query().Encode()))
+	rel := strings.TrimPrefix(path.Clean("/"+upstreamPath(r.URL)), "/") // stays inside dir
+	if rel == "" {
+		rel = "index"
+	}
+	return filepath.Join(dir, filepath.FromSlash(rel)+"."+hex.EncodeToString(sum[:4])+".json")
+}
+
+type recordTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+func (t recordTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	rec := recording{
+		Method:      r.Method,
+		URL:         r.URL.String(),
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        string(body),
+	}
+	if err := writeRecording(recordingPath(t.dir, r), rec); err != nil {
+		log.Printf("Record: %s: %v", r.URL, err)
+	}
+	return resp, nil
+}
+
+func writeRecording(file string, rec recording) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+type replayTransport struct {
+	dir string
+}
+
+func (t replayTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	file := recordingPath(t.dir, r)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		log.Printf("Replay: no recording for %s %s", r.Method, r.URL)
+		return cannedResponse(r, http.StatusNotFound, "application/json", []byte(`{"error":"no recording for this request"}`)), nil
+	}
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("replay %s: %v", file, err)
+	}
+	return cannedResponse(r, rec.Status, rec.ContentType, []byte(rec.Body)), nil
+}
+
+// setUpstreamMode points upstreamClient at the transport for mode. dir is
+// where record and replay keep their files.
+func setUpstreamMode(mode, dir string) error {
+	if dir == "" {
+		dir = defaultRecordDir
+	}
+	switch strings.ToLower(mode) {
+	case "", modeLive:
+	case modeSandbox:
+		upstreamClient.Transport = sandboxTransport{}
+		log.Printf("Sandbox: serving canned data instead of %s", baseURL)
+	case modeRecord:
+		upstreamClient.Transport = recordTransport{dir: dir, next: upstreamClient.Transport}
+		log.Printf("Record: saving upstream responses to %s", dir)
+	case modeReplay:
+		if _, err := os.Stat(dir); err != nil {
+			return fmt.Errorf("replay: %v", err)
+		}
+		upstreamClient.Transport = replayTransport{dir: dir}
+		log.Printf("Replay: serving recorded responses from %s", dir)
+	default:
+		return fmt.Errorf("unknown mode %q", mode)
+	}
+	return nil
+}
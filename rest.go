@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- REST API ---
+//
+// Every tool that does not depend on an MCP session can also be called over
+// plain HTTP: POST /api/tools/{name} with the arguments as a JSON object.
+// /api/openapi.json describes these endpoints, generated from the tool
+// definitions, so clients can be generated from it or the tools plugged into
+// other LLM frameworks. API keys and rate limits apply as on /message.
+
+// sessionTools keep per-session state and are only offered over MCP.
+var sessionTools = map[string]bool{
+	"add_favorite_team":    true,
+	"remove_favorite_team": true,
+	"list_favorites":       true,
+	"get_my_live_scores":   true,
+	"set_language":         true,
+}
+
+func restTools(s *server.MCPServer) []mcp.Tool {
+	var tools []mcp.Tool
+	for name, t := range s.ListTools() {
+		if !sessionTools[name] {
+			tools = append(tools, t.Tool)
+		}
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+func registerRESTRoutes(mux *http.ServeMux, s *server.MCPServer, publicURL string, wrap func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("GET /api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		writeJSON(w, http.StatusOK, openAPISpec(restTools(s), publicURL))
+	})
+
+	mux.HandleFunc("POST /api/tools/{name}", wrap(func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		tool := s.GetTool(name)
+		if tool == nil || sessionTools[name] {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("unknown tool %q", name)})
+			return
+		}
+		args := map[string]interface{}{}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&args); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "body must be a JSON object of tool arguments"})
+				return
+			}
+		}
+
+		var req mcp.CallToolRequest
+		req.Params.Name = name
+		req.Params.Arguments = args
+		res, err := tool.Handler(r.Context(), req)
+		if err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+			return
+		}
+
+		var text strings.Builder
+		for _, c := range res.Content {
+			if tc, ok := mcp.AsTextContent(c); ok {
+				text.WriteString(tc.Text)
+			}
+		}
+		if res.IsError {
+			te := resultError(res)
+			writeJSON(w, errorStatus[te.Code], map[string]string{"error": text.String(), "code": te.Code, "remediation": te.Remediation})
+			return
+		}
+		// Tool results are "Title:\n\n{json}"; REST clients get the JSON.
+		if _, body, ok := strings.Cut(text.String(), "\n\n"); ok && json.Valid([]byte(body)) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(body))
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(text.String()))
+	}))
+}
+
+// openAPISpec builds an OpenAPI 3.1 document with one operation per tool,
+// taking the request schema from the tool's input schema.
+func openAPISpec(tools []mcp.Tool, publicURL string) map[string]interface{} {
+	errorResponse := func(desc string) map[string]interface{} {
+		return map[string]interface{}{
+			"description": desc,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"},
+				},
+			},
+		}
+	}
+
+	paths := make(map[string]interface{}, len(tools))
+	for _, t := range tools {
+		var encoded struct {
+			InputSchema map[string]interface{} `json:"inputSchema"`
+		}
+		if data, err := json.Marshal(t); err == nil {
+			json.Unmarshal(data, &encoded)
+		}
+		schema := encoded.InputSchema
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object"}
+		}
+		required, _ := schema["required"].([]interface{})
+		summary, _, _ := strings.Cut(t.Description, ". ")
+
+		paths["/api/tools/"+t.Name] = map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": t.Name,
+				"summary":     strings.TrimSuffix(summary, "."),
+				"description": t.Description,
+				"requestBody": map[string]interface{}{
+					"required": len(required) > 0,
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": schema},
+					},
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "Tool result, JSON when the upstream data is JSON",
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{"schema": map[string]interface{}{}},
+							"text/plain":       map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+						},
+					},
+					"400": errorResponse("Invalid arguments (INVALID_ARGUMENT, INVALID_LEAGUE_KEY)"),
+					"401": errorResponse("Invalid API key or token"),
+					"404": errorResponse("No such team, player or match (NOT_FOUND)"),
+					"429": errorResponse("Rate limit exceeded; see Retry-After"),
+					"500": errorResponse("Internal error (INTERNAL_ERROR)"),
+					"502": errorResponse("The data provider failed (UPSTREAM_ERROR)"),
+					"504": errorResponse("The data provider timed out (UPSTREAM_TIMEOUT)"),
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":       "LiveScore REST API",
+			"version":     serverVersion,
+			"description": "Football live scores, fixtures, teams, players and matches. Each operation runs the MCP tool of the same name. All timestamps are GMT/UTC.",
+		},
+		"servers": []map[string]string{{"url": publicURL}},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"error":       map[string]interface{}{"type": "string"},
+						"code":        map[string]interface{}{"type": "string", "description": "Stable error code, e.g. NOT_FOUND or INVALID_LEAGUE_KEY"},
+						"remediation": map[string]interface{}{"type": "string"},
+					},
+					"required": []string{"error"},
+				},
+			},
+			"securitySchemes": map[string]interface{}{
+				"bearer": map[string]interface{}{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "Optional API key or OAuth access token; anonymous calls get the anonymous rate limit",
+				},
+			},
+		},
+		"security": []map[string][]string{{}, {"bearer": {}}},
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Rivalries ---
+//
+// data/rivalries.json is a curated list of the best-known derbies, each with
+// the names upstream uses for both clubs. Matches between them carry a derby
+// field (the rivalry's name) in live scores and fixtures. get_rivalry gives
+// the record between two teams from their meetings: those on the first
+// team's page plus the head-to-head of one of their matches. Names are
+// matched whole, so "Inter" does not match "Inter Miami" and women's and
+// youth sides ("Ajax U19") are not derbies of the senior teams.
+
+//go:embed data/rivalries.json
+var embeddedRivalries []byte
+
+const (
+	rivalryMeetingsListed = 10
+	maxH2HMeetings        = 25
+)
+
+type rivalry struct {
+	Name    string     `json:"name"`
+	Country string     `json:"country"`
+	Teams   [][]string `json:"teams"` // the names of each club
+}
+
+// rivalries maps a pair of team name keys, in either order, to a rivalry.
+var rivalries = loadRivalries(embeddedRivalries)
+
+// teamNameKey folds a team name for matching: lower case, letters and
+// digits only.
+func teamNameKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+func loadRivalries(data []byte) map[[2]string]*rivalry {
+	var list []*rivalry
+	if err := json.Unmarshal(data, &list); err != nil {
+		log.Printf("Rivalries: embedded dataset unreadable: %v", err)
+		return nil
+	}
+	pairs := map[[2]string]*rivalry{}
+	for _, r := range list {
+		if len(r.Teams) != 2 {
+			continue
+		}
+		for _, a := range r.Teams[0] {
+			for _, b := range r.Teams[1] {
+				ka, kb := teamNameKey(a), teamNameKey(b)
+				pairs[[2]string{ka, kb}] = r
+				pairs[[2]string{kb, ka}] = r
+			}
+		}
+	}
+	return pairs
+}
+
+// rivalryOf returns the rivalry between two teams, or nil.
+func rivalryOf(a, b string) *rivalry {
+	return rivalries[[2]string{teamNameKey(a), teamNameKey(b)}]
+}
+
+// markDerbies sets the derby field on the matches of a feed that are
+// derbies. The field only depends on the teams, so marking a cached feed
+// is harmless.
+func markDerbies(data interface{}) interface{} {
+	for _, fm := range extractMatches(data) {
+		if r := rivalryOf(fm.HomeName, fm.AwayName); r != nil && fm.Raw != nil {
+			fm.Raw["derby"] = r.Name
+		}
+	}
+	return data
+}
+
+type rivalryRecord struct {
+	Derby        string       `json:"derby,omitempty"`
+	TeamA        string       `json:"team_a"`
+	TeamB        string       `json:"team_b"`
+	Played       int          `json:"played"`
+	WinsA        int          `json:"wins_a"`
+	Draws        int          `json:"draws"`
+	WinsB        int          `json:"wins_b"`
+	GoalsA       int          `json:"goals_a"`
+	GoalsB       int          `json:"goals_b"`
+	AtHome       venueRecord  `json:"a_at_home"`
+	Away         venueRecord  `json:"a_away"`
+	LastMeetings []matchScore `json:"last_meetings,omitempty"`
+	Next         *matchScore  `json:"next,omitempty"`
+}
+
+// venueRecord is team a's record at one venue: at its ground or away.
+type venueRecord struct {
+	Played       int `json:"played"`
+	Won          int `json:"won"`
+	Drawn        int `json:"drawn"`
+	Lost         int `json:"lost"`
+	GoalsFor     int `json:"goals_for"`
+	GoalsAgainst int `json:"goals_against"`
+}
+
+func (v *venueRecord) add(scored, conceded int) {
+	v.Played++
+	v.GoalsFor += scored
+	v.GoalsAgainst += conceded
+	switch {
+	case scored > conceded:
+		v.Won++
+	case scored < conceded:
+		v.Lost++
+	default:
+		v.Drawn++
+	}
+}
+
+type matchScore struct {
+	MatchID   string `json:"match_id"`
+	Date      string `json:"date,omitempty"`
+	Home      string `json:"home"`
+	Away      string `json:"away"`
+	Score     string `json:"score,omitempty"`
+	LeagueKey string `json:"league_key,omitempty"`
+}
+
+func scoreOf(fm feedMatch) matchScore {
+	ms := matchScore{MatchID: fm.ID, Home: fm.HomeName, Away: fm.AwayName, LeagueKey: fm.LeagueKey}
+	if kickoff, ok := fm.kickoff(); ok {
+		ms.Date = kickoff.Format("2006-01-02")
+	}
+	if fm.HasScore {
+		ms.Score = fmt.Sprintf("%d-%d", fm.HomeGoals, fm.AwayGoals)
+	}
+	return ms
+}
+
+// meetings adds the matches between teams a and b in data to into, by ID.
+func meetings(data interface{}, a, b string, into map[string]feedMatch) {
+	for _, fm := range extractMatches(data) {
+		if fm.ID != "" && ((fm.HomeID == a && fm.AwayID == b) || (fm.HomeID == b && fm.AwayID == a)) {
+			into[fm.ID] = fm
+		}
+	}
+}
+
+// matchMeetings adds the head-to-head history of a match page to found.
+func matchMeetings(match interface{}, found map[string]feedMatch) {
+	fm, ok := primaryMatch(match)
+	if !ok {
+		return
+	}
+	h2h, _ := lookup(fm.Raw, "h2h", "headtohead")
+	meetings(h2h, fm.HomeID, fm.AwayID, found)
+}
+
+// moreMeetings adds the meetings on the pages of teams, when found has
+// fewer finished ones than wanted.
+func moreMeetings(ctx context.Context, q sourceQuery, a, b string, want int, found map[string]feedMatch, teams ...string) {
+	for _, id := range teams {
+		finished := 0
+		for _, fm := range found {
+			if fm.finished() {
+				finished++
+			}
+		}
+		if finished >= want {
+			return
+		}
+		if team, err := source.Team(ctx, id, q); err == nil {
+			meetings(team, a, b, found)
+		}
+	}
+}
+
+// withH2H gives a copy of a match page the limit latest meetings of its
+// teams, from its head-to-head and, when that has fewer, their team pages,
+// and adds their record from the home team's side as h2h_summary. The page
+// itself may be the cached one.
+func withH2H(ctx context.Context, q sourceQuery, data interface{}, limit int) interface{} {
+	cached, ok := data.(map[string]interface{})
+	fm, isMatch := primaryMatch(data)
+	if !ok || !isMatch || fm.HomeID == "" || fm.AwayID == "" {
+		return data
+	}
+	found := map[string]feedMatch{}
+	matchMeetings(cached, found)
+	if limit > 0 {
+		moreMeetings(ctx, q, fm.HomeID, fm.AwayID, limit, found, fm.HomeID, fm.AwayID)
+	}
+	delete(found, fm.ID)
+	m := make(map[string]interface{}, len(cached)+1)
+	key := "h2h"
+	for k, v := range cached {
+		m[k] = v
+		if nk := normKey(k); nk == "h2h" || nk == "headtohead" {
+			key = k
+		}
+	}
+	if limit > 0 {
+		var list []feedMatch
+		for _, meeting := range found {
+			if meeting.finished() {
+				list = append(list, meeting)
+			}
+		}
+		byKickoff(list)
+		slices.Reverse(list)
+		list, older := list[:min(limit, len(list))], list[min(limit, len(list)):]
+		for _, meeting := range older {
+			delete(found, meeting.ID)
+		}
+		h2h := []interface{}{}
+		for _, meeting := range list {
+			h2h = append(h2h, meeting.Raw)
+		}
+		m[key] = h2h
+	}
+	m["h2h_summary"] = rivalryRecordOf(fm.HomeID, fm.AwayID, found, 0)
+	return m
+}
+
+// rivalryRecordOf tallies the finished meetings from team a's side, overall
+// and split by venue, lists the latest of them and names the next one.
+func rivalryRecordOf(a, b string, found map[string]feedMatch, listed int) rivalryRecord {
+	var played, upcoming []feedMatch
+	for _, fm := range found {
+		switch {
+		case fm.finished() && fm.HasScore:
+			played = append(played, fm)
+		case !fm.finished() && fm.Status != "" && !fm.HasScore:
+			upcoming = append(upcoming, fm)
+		}
+	}
+	byDate := func(list []feedMatch) {
+		sort.Slice(list, func(i, j int) bool {
+			ti, _ := list[i].kickoff()
+			tj, _ := list[j].kickoff()
+			return ti.After(tj)
+		})
+	}
+	byDate(played)
+	byDate(upcoming)
+
+	var rec rivalryRecord
+	for _, fm := range played {
+		if rec.TeamA == "" {
+			rec.TeamA, rec.TeamB = fm.HomeName, fm.AwayName
+			if fm.HomeID != a {
+				rec.TeamA, rec.TeamB = rec.TeamB, rec.TeamA
+			}
+		}
+		goalsA, goalsB := fm.HomeGoals, fm.AwayGoals
+		if fm.HomeID != a {
+			goalsA, goalsB = goalsB, goalsA
+			rec.Away.add(goalsA, goalsB)
+		} else {
+			rec.AtHome.add(goalsA, goalsB)
+		}
+		rec.Played++
+		rec.GoalsA += goalsA
+		rec.GoalsB += goalsB
+		switch {
+		case goalsA > goalsB:
+			rec.WinsA++
+		case goalsA < goalsB:
+			rec.WinsB++
+		default:
+			rec.Draws++
+		}
+		if len(rec.LastMeetings) < listed {
+			rec.LastMeetings = append(rec.LastMeetings, scoreOf(fm))
+		}
+	}
+	if len(upcoming) > 0 {
+		next := scoreOf(upcoming[len(upcoming)-1])
+		rec.Next = &next
+		if rec.TeamA == "" {
+			rec.TeamA, rec.TeamB = next.Home, next.Away
+			if upcoming[len(upcoming)-1].HomeID != a {
+				rec.TeamA, rec.TeamB = rec.TeamB, rec.TeamA
+			}
+		}
+	}
+	if r := rivalryOf(rec.TeamA, rec.TeamB); r != nil {
+		rec.Derby = r.Name
+	}
+	return rec
+}
+
+func registerRivalryTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_rivalry",
+			mcp.WithDescription("Get the record between two teams: meetings played, wins each, draws and goals, split by venue (team_a at home and away), the last meetings and the next one, and the derby's name for well-known rivalries (El Clásico, Derby della Madonnina...)"),
+			mcp.WithString("team_a", mcp.Required(), mcp.Description("Team ID from search results (e.g. 13183 for Ajax); the record is from this team's side")),
+			mcp.WithString("team_b", mcp.Required(), mcp.Description("Team ID of the opponent")),
+			mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Number of last meetings listed, 1 to %d. Default: %d", maxH2HMeetings, rivalryMeetingsListed))),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			a := getStr(req.Params.Arguments, "team_a", "")
+			b := getStr(req.Params.Arguments, "team_b", "")
+			if a == "" || b == "" || a == b {
+				return toolErrorResult(codeInvalidArgument, "team_a and team_b must be two different team IDs"), nil
+			}
+			limit := getInt(req.Params.Arguments, "limit", rivalryMeetingsListed)
+			if limit < 1 || limit > maxH2HMeetings {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("limit must be between 1 and %d", maxH2HMeetings)), nil
+			}
+			q := queryOf(req.Params.Arguments)
+			team, err := source.Team(ctx, a, q)
+			if err != nil {
+				return errorResult(err), nil
+			}
+			found := map[string]feedMatch{}
+			meetings(team, a, b, found)
+			// One of their matches brings the longer head-to-head history.
+			for id := range found {
+				if match, err := source.Match(ctx, id, true, q); err == nil {
+					matchMeetings(match, found)
+				}
+				break
+			}
+			moreMeetings(ctx, q, a, b, limit, found, b)
+			if len(found) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no meetings between teams %s and %s found", a, b)), nil
+			}
+			rec := rivalryRecordOf(a, b, found, limit)
+			return jsonResult(fmt.Sprintf("Record of team %s against team %s over %d meetings", a, b, rec.Played), rec), nil
+		},
+	)
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Run-in ---
+//
+// The end of the season is read from a league's table and the unfinished
+// matches of its fixtures feed. A team's games left are what a double round
+// robin leaves it (two games against every other team), or the number of its
+// matches still in the feed when that is more. get_title_race lists the teams
+// that can still catch the leader, with the fixtures they have left and the
+// meetings between them still to play.
+//
+// get_relegation_battle adds how hard each team's fixtures are, the average
+// points per game of its opponents, and its chance of staying up from
+// seasonSimulations playthroughs of the remaining matches. Each match is won
+// by a side in proportion to its points per game, after drawRate of draws;
+// games left beyond those in the feed are played against an average team.
+// The playthroughs use a fixed seed, so the same table gives the same
+// percentages. get_points_projection runs the same playthroughs for every
+// team and reports the middle 80% of its final points as the range.
+
+const (
+	titleContenders         = 5
+	relegationListed        = 8
+	defaultRelegationPlaces = 3
+	seasonSimulations       = 2000
+	drawRate                = 0.26
+	minStrength             = 0.2 // points per game, so the bottom team still wins some
+	projectionLow           = 0.1 // percentiles of the projected range
+	projectionHigh          = 0.9
+)
+
+// runInTeam is a team's position in the run-in.
+type runInTeam struct {
+	standingRow
+	GamesLeft int          `json:"games_left"`
+	MaxPoints int          `json:"max_points"`
+	Gap       int          `json:"gap"`
+	Remaining []matchScore `json:"remaining_fixtures"`
+
+	Difficulty *float64 `json:"fixture_difficulty,omitempty"`
+	Survival   *float64 `json:"survival_percent,omitempty"`
+}
+
+// runIn is a league's table with every team's matches left in the feed,
+// in kickoff order, by team ID.
+type runIn struct {
+	rows      []standingRow
+	open      []feedMatch
+	remaining map[string][]feedMatch
+}
+
+func leagueRunIn(data interface{}) runIn {
+	ri := runIn{rows: extractStandings(data), remaining: map[string][]feedMatch{}}
+	seen := map[string]bool{}
+	var open []feedMatch
+	for _, fm := range extractMatches(data) {
+		if fm.finished() || fm.ID == "" || seen[fm.ID] {
+			continue
+		}
+		seen[fm.ID] = true
+		open = append(open, fm)
+	}
+	sort.SliceStable(open, func(i, j int) bool {
+		ti, _ := open[i].kickoff()
+		tj, _ := open[j].kickoff()
+		return ti.Before(tj)
+	})
+	ri.open = open
+	for _, fm := range open {
+		for _, id := range []string{fm.HomeID, fm.AwayID} {
+			if id != "" {
+				ri.remaining[id] = append(ri.remaining[id], fm)
+			}
+		}
+	}
+	return ri
+}
+
+// team returns a row's run-in, with the gap to target points.
+func (ri runIn) team(row standingRow, target int) runInTeam {
+	t := runInTeam{standingRow: row, Gap: target - row.Points, Remaining: []matchScore{}}
+	t.GamesLeft = max(0, 2*(len(ri.rows)-1)-row.Played, len(ri.remaining[row.TeamID]))
+	t.MaxPoints = row.Points + 3*t.GamesLeft
+	for _, fm := range ri.remaining[row.TeamID] {
+		t.Remaining = append(t.Remaining, scoreOf(fm))
+	}
+	return t
+}
+
+// meetingsLeft returns the matches left between the given teams.
+func (ri runIn) meetingsLeft(teams []runInTeam) []matchScore {
+	in := map[string]bool{}
+	for _, t := range teams {
+		in[t.TeamID] = true
+	}
+	out := []matchScore{}
+	seen := map[string]bool{}
+	for _, t := range teams {
+		for _, fm := range ri.remaining[t.TeamID] {
+			if in[fm.HomeID] && in[fm.AwayID] && !seen[fm.ID] {
+				seen[fm.ID] = true
+				out = append(out, scoreOf(fm))
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out
+}
+
+// strengths returns the points per game of every team by ID, and the
+// league's average.
+func (ri runIn) strengths() (map[string]float64, float64) {
+	ppg := map[string]float64{}
+	total := 0.0
+	for _, row := range ri.rows {
+		s := 1.0
+		if row.Played > 0 {
+			s = float64(row.Points) / float64(row.Played)
+		}
+		ppg[row.TeamID] = max(minStrength, s)
+		total += ppg[row.TeamID]
+	}
+	return ppg, total / float64(len(ri.rows))
+}
+
+// difficulty is the average points per game of a team's opponents in the
+// fixtures it has left, or nil when it has none in the feed.
+func (ri runIn) difficulty(teamID string, ppg map[string]float64) *float64 {
+	sum, n := 0.0, 0
+	for _, fm := range ri.remaining[teamID] {
+		opponent := fm.AwayID
+		if opponent == teamID {
+			opponent = fm.HomeID
+		}
+		if s, ok := ppg[opponent]; ok {
+			sum += s
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	d := round2(sum / float64(n))
+	return &d
+}
+
+// simulate plays out the rest of the season n times and calls outcome with
+// each final table, points by team ID.
+func (ri runIn) simulate(n int, outcome func(points map[string]int)) {
+	ppg, average := ri.strengths()
+	rng := rand.New(rand.NewPCG(1, 2))
+	// result returns the points of each side of one match.
+	result := func(home, away float64) (int, int) {
+		r := rng.Float64()
+		switch {
+		case r < drawRate:
+			return 1, 1
+		case r < drawRate+(1-drawRate)*home/(home+away):
+			return 3, 0
+		}
+		return 0, 3
+	}
+	extra := map[string]int{}
+	for _, row := range ri.rows {
+		extra[row.TeamID] = ri.team(row, 0).GamesLeft - len(ri.remaining[row.TeamID])
+	}
+	for range n {
+		points := map[string]int{}
+		for _, row := range ri.rows {
+			points[row.TeamID] = row.Points
+		}
+		for _, fm := range ri.open {
+			home, homeOK := ppg[fm.HomeID]
+			away, awayOK := ppg[fm.AwayID]
+			if !homeOK || !awayOK {
+				continue
+			}
+			h, a := result(home, away)
+			points[fm.HomeID] += h
+			points[fm.AwayID] += a
+		}
+		for id, games := range extra {
+			for range games {
+				p, _ := result(ppg[id], average)
+				points[id] += p
+			}
+		}
+		outcome(points)
+	}
+}
+
+// survival returns the percentage of playthroughs in which each team
+// finishes above the bottom places, ties going to goal difference.
+func (ri runIn) survival(places int) map[string]float64 {
+	safe := map[string]int{}
+	order := make([]standingRow, len(ri.rows))
+	ri.simulate(seasonSimulations, func(points map[string]int) {
+		copy(order, ri.rows)
+		sort.SliceStable(order, func(i, j int) bool {
+			pi, pj := points[order[i].TeamID], points[order[j].TeamID]
+			if pi != pj {
+				return pi > pj
+			}
+			return order[i].GoalDifference() > order[j].GoalDifference()
+		})
+		for _, row := range order[:len(order)-places] {
+			safe[row.TeamID]++
+		}
+	})
+	percent := map[string]float64{}
+	for _, row := range ri.rows {
+		percent[row.TeamID] = math.Round(float64(safe[row.TeamID])*1000/seasonSimulations) / 10
+	}
+	return percent
+}
+
+// relegationBattle returns the teams in the bottom places and those above
+// that the highest of them can still catch, at most relegationListed, with
+// their gap to the first safe team.
+func (ri runIn) relegationBattle(places int) []runInTeam {
+	n := len(ri.rows)
+	safety := ri.rows[n-places-1].Points
+	reach := ri.team(ri.rows[n-places], safety).MaxPoints
+	ppg, _ := ri.strengths()
+	chances := ri.survival(places)
+	var battle []runInTeam
+	for i := n - 1; i >= 0 && len(battle) < relegationListed; i-- {
+		row := ri.rows[i]
+		if i < n-places && row.Points > reach {
+			break
+		}
+		t := ri.team(row, safety)
+		t.Difficulty = ri.difficulty(row.TeamID, ppg)
+		chance := chances[row.TeamID]
+		t.Survival = &chance
+		battle = append(battle, t)
+	}
+	slices.Reverse(battle)
+	return battle
+}
+
+// pointsProjection is a team's projected final points.
+type pointsProjection struct {
+	Position   int      `json:"position"`
+	TeamID     string   `json:"team_id,omitempty"`
+	TeamName   string   `json:"team"`
+	Points     int      `json:"points"`
+	PerGame    float64  `json:"points_per_game"`
+	GamesLeft  int      `json:"games_left"`
+	AtRate     int      `json:"projected_at_current_rate"`
+	Median     int      `json:"projected"`
+	Low        int      `json:"range_low"`
+	High       int      `json:"range_high"`
+	MaxPoints  int      `json:"max_points"`
+	Difficulty *float64 `json:"fixture_difficulty,omitempty"`
+}
+
+// projections returns every team's projected points, highest first.
+func (ri runIn) projections() []pointsProjection {
+	finals := map[string][]int{}
+	ri.simulate(seasonSimulations, func(points map[string]int) {
+		for id, p := range points {
+			finals[id] = append(finals[id], p)
+		}
+	})
+	ppg, _ := ri.strengths()
+	out := make([]pointsProjection, 0, len(ri.rows))
+	for _, row := range ri.rows {
+		t := ri.team(row, 0)
+		p := pointsProjection{
+			Position:   row.Position,
+			TeamID:     row.TeamID,
+			TeamName:   row.TeamName,
+			Points:     row.Points,
+			GamesLeft:  t.GamesLeft,
+			MaxPoints:  t.MaxPoints,
+			Difficulty: ri.difficulty(row.TeamID, ppg),
+		}
+		if row.Played > 0 {
+			p.PerGame = round2(float64(row.Points) / float64(row.Played))
+		}
+		p.AtRate = row.Points + int(math.Round(p.PerGame*float64(t.GamesLeft)))
+		final := finals[row.TeamID]
+		slices.Sort(final)
+		at := func(q float64) int { return final[int(q*float64(len(final)-1))] }
+		p.Low, p.Median, p.High = at(projectionLow), at(0.5), at(projectionHigh)
+		out = append(out, p)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Median > out[j].Median })
+	return out
+}
+
+// titleRace returns the leader and the teams that can still reach its
+// points, at most titleContenders of them.
+func (ri runIn) titleRace() []runInTeam {
+	leader := ri.rows[0]
+	var race []runInTeam
+	for _, row := range ri.rows {
+		t := ri.team(row, leader.Points)
+		if len(race) > 0 && (t.MaxPoints < leader.Points || len(race) == titleContenders) {
+			break
+		}
+		race = append(race, t)
+	}
+	return race
+}
+
+func registerRunInTools(s *server.MCPServer, catalog *competitionCatalog) {
+	s.AddTool(
+		mcp.NewTool("get_title_race",
+			mcp.WithDescription("Get a league's title race: the leader and the teams that can still catch it, with points, gap to the leader, games left, maximum reachable points, the fixtures each has left and the meetings between contenders still to play"),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			ri := leagueRunIn(data)
+			if len(ri.rows) < 2 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no standings found for %s", key)), nil
+			}
+			race := ri.titleRace()
+			result := map[string]interface{}{
+				"league_key":         key,
+				"contenders":         race,
+				"head_to_heads_left": ri.meetingsLeft(race),
+			}
+			summary := fmt.Sprintf("Title race in %s: %s leads", key, race[0].TeamName)
+			if len(race) == 1 {
+				summary += " and can no longer be caught"
+			} else {
+				summary += fmt.Sprintf(" with %d teams able to catch them", len(race)-1)
+			}
+			return jsonResult(summary, result), nil
+		},
+	)
+
+	s.AddTool(
+		mcp.NewTool("get_relegation_battle",
+			mcp.WithDescription("Get a league's relegation battle: the teams in and near the bottom places with points, gap to safety, games left, the fixtures each has left, their difficulty (opponents' average points per game) and a simple survival probability from simulating the remaining matches"),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithNumber("relegation_places", mcp.Description(fmt.Sprintf("Number of places at the bottom that go down. Default: %d", defaultRelegationPlaces))),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			ri := leagueRunIn(data)
+			if len(ri.rows) < 2 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no standings found for %s", key)), nil
+			}
+			places := getInt(req.Params.Arguments, "relegation_places", defaultRelegationPlaces)
+			if places < 1 || places >= len(ri.rows) {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("relegation_places must be between 1 and %d for %s", len(ri.rows)-1, key)), nil
+			}
+			result := map[string]interface{}{
+				"league_key":        key,
+				"relegation_places": places,
+				"safety_points":     ri.rows[len(ri.rows)-places-1].Points,
+				"teams":             ri.relegationBattle(places),
+			}
+			return jsonResult(fmt.Sprintf("Relegation battle in %s (bottom %d go down; gap is points behind the first safe team, negative when above it; survival from %d simulated run-ins)",
+				key, places, seasonSimulations), result), nil
+		},
+	)
+
+	s.AddTool(
+		mcp.NewTool("get_points_projection",
+			mcp.WithDescription("Project every team's end-of-season points in a league: at its current points per game, and as a median with a likely range (10th to 90th percentile) from simulating the remaining fixtures against opponents of their strength"),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			ri := leagueRunIn(data)
+			if len(ri.rows) < 2 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no standings found for %s", key)), nil
+			}
+			return jsonResult(fmt.Sprintf("Projected final points in %s, by projected (median of %d simulated run-ins); range_low to range_high holds 80%% of outcomes",
+				key, seasonSimulations), ri.projections()), nil
+		},
+	)
+}
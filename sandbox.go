@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// --- Sandbox Mode ---
+//
+// MODE=sandbox answers every upstream request from the canned responses in
+// sandbox/, laid out like the upstream paths (fixtures/feed_livenow.json,
+// team_gs/101.json, ...). Tools, resources and prompts behave as usual, so
+// demos, client integration tests and development run offline without
+// touching the upstream rate limits. Query parameters other than the search
+// term are ignored, and {{yesterday}}, {{today}} and {{tomorrow}} in the
+// files become the current dates.
+
+// Upstream modes; see setUpstreamMode.
+const (
+	modeLive    = "live"
+	modeSandbox = "sandbox"
+	modeRecord  = "record"
+	modeReplay  = "replay"
+)
+
+//go:embed sandbox
+var sandboxFiles embed.FS
+
+type sandboxTransport struct{}
+
+func (sandboxTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	rel := upstreamPath(r.URL)
+
+	// Team logos exist for the teams in the sandbox.
+	if id, ok := strings.CutPrefix(rel, "images/teams_gs/"); ok {
+		id = strings.TrimSuffix(id, ".png")
+		if _, err := fs.Stat(sandboxFiles, path.Join("sandbox/team_gs", id+".json")); err != nil {
+			return cannedResponse(r, http.StatusNotFound, "", nil), nil
+		}
+		return cannedResponse(r, http.StatusOK, "image/png", sandboxLogo(id)), nil
+	}
+
+	name := path.Join("sandbox", rel)
+	if path.Ext(name) == "" {
+		name += ".json"
+	}
+	data, err := sandboxFiles.ReadFile(name)
+	if err != nil {
+		return cannedResponse(r, http.StatusNotFound, "application/json", []byte(`{"error":"not in sandbox data"}`)), nil
+	}
+	data = expandDates(data, time.Now().UTC())
+	if rel == "search_v3" {
+		data = filterSearch(data, r.URL.Query().Get("q"))
+	}
+	return cannedResponse(r, http.StatusOK, "application/json", data), nil
+}
+
+// upstreamPath returns the path of u relative to the upstream base URL.
+func upstreamPath(u *url.URL) string {
+	base, _ := url.Parse(baseURL)
+	return strings.TrimPrefix(strings.TrimPrefix(u.Path, base.Path), "/")
+}
+
+func cannedResponse(r *http.Request, status int, contentType string, body []byte) *http.Response {
+	resp := &http.Response{
+		StatusCode:    status,
+		Status:        http.StatusText(status),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+	if contentType != "" {
+		resp.Header.Set("Content-Type", contentType)
+	}
+	return resp
+}
+
+func expandDates(data []byte, now time.Time) []byte {
+	day := func(offset int) string { return now.AddDate(0, 0, offset).Format("2006-01-02") }
+	return []byte(strings.NewReplacer(
+		"{{yesterday}}", day(-1),
+		"{{today}}", day(0),
+		"{{tomorrow}}", day(1),
+	).Replace(string(data)))
+}
+
+// filterSearch keeps the search results whose name contains q.
+func filterSearch(data []byte, q string) []byte {
+	var results map[string][]map[string]interface{}
+	if q == "" || json.Unmarshal(data, &results) != nil {
+		return data
+	}
+	q = strings.ToLower(q)
+	for group, items := range results {
+		kept := []map[string]interface{}{}
+		for _, item := range items {
+			name := strings.ToLower(lookupStr(item, "name", "leaguename"))
+			if strings.Contains(name, q) {
+				kept = append(kept, item)
+			}
+		}
+		results[group] = kept
+	}
+	out, err := json.Marshal(results)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// sandboxLogo draws a placeholder badge: a disc in a colour derived from the
+// team ID.
+func sandboxLogo(id string) []byte {
+	const size = 128
+	sum := sha256.Sum256([]byte(id))
+	fill := color.NRGBA{sum[0] | 0x40, sum[1] | 0x40, sum[2] | 0x40, 0xff}
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := x-size/2, y-size/2
+			if dx*dx+dy*dy <= (size/2-4)*(size/2-4) {
+				img.SetNRGBA(x, y, fill)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// --- Seasons ---
+//
+// Tools that take a season ask upstream for that season's competition feed
+// with a season parameter. Not every league has its past seasons archived,
+// and upstream then answers with the current season's feed, which
+// archivedSeason reports instead of passing it off as the season asked for.
+// Seasons are written "2024/2025"; "2024-25" and "2024/25" mean the same, and
+// a single year means the season starting in it (or that calendar year, for
+// leagues played in one).
+
+// normSeason writes a season as "2024/2025", or "2024" for a single year. It
+// returns s unchanged when it is neither.
+func normSeason(s string) string {
+	s = strings.TrimSpace(s)
+	start, end, two := strings.Cut(strings.NewReplacer("-", "/", "_", "/").Replace(s), "/")
+	from, err := strconv.Atoi(start)
+	if err != nil || len(start) != 4 {
+		return s
+	}
+	if !two {
+		return start
+	}
+	to, err := strconv.Atoi(end)
+	switch {
+	case err != nil:
+		return s
+	case len(end) == 2:
+		to += from / 100 * 100
+		if to < from {
+			to += 100
+		}
+	case len(end) != 4:
+		return s
+	}
+	return fmt.Sprintf("%d/%d", from, to)
+}
+
+// seasonQuery is the query of a tool call for a season, "" for the current.
+func seasonQuery(args any, season string) sourceQuery {
+	q := queryOf(args)
+	if season != "" {
+		q.Season = normSeason(season)
+	}
+	return q
+}
+
+// archivedSeason checks that a competition feed is of the season asked for.
+func archivedSeason(data interface{}, key, season string) error {
+	if season == "" {
+		return nil
+	}
+	want := normSeason(season)
+	root, _ := data.(map[string]interface{})
+	got := normSeason(lookupStr(root, "season"))
+	if got == "" || got == want || strings.HasPrefix(got, want+"/") {
+		return nil
+	}
+	return notFound("upstream has no archive of %s season %s; its feed is for %s", key, want, got)
+}
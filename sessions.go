@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Session State ---
+//
+// sessionStore holds everything remembered per MCP client (favorites,
+// language). A client with an API key or OAuth token keeps its state under
+// that key across reconnects; an anonymous session's state is dropped when
+// the session ends. Either is dropped after being idle for the configured
+// TTL. When a path is configured the state of keyed clients and of the stdio
+// session, the ones a restart can find again, is loaded on startup and
+// written back after every change.
+
+const (
+	defaultSessionIdleTTL = 24 * time.Hour
+	sessionSweepInterval  = time.Minute
+)
+
+type sessionState struct {
+	Favorites map[string]favoriteTeam `json:"favorites,omitempty"`
+	Language  string                  `json:"language,omitempty"`
+	LastSeen  time.Time               `json:"last_seen"`
+}
+
+func (st *sessionState) empty() bool {
+	return len(st.Favorites) == 0 && st.Language == ""
+}
+
+// stdioSession is the ID of the stdio transport's only session, the same
+// across restarts.
+const stdioSession = "stdio"
+
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*sessionState // owner -> state
+	idleTTL  time.Duration
+	path     string
+	keys     *keyRing // nil when sessions are not bound to keys
+}
+
+func newSessionStore(path string, idleTTL time.Duration) *sessionStore {
+	ss := &sessionStore{
+		sessions: make(map[string]*sessionState),
+		idleTTL:  idleTTL,
+		path:     path,
+	}
+	if path != "" {
+		ss.load()
+	}
+	return ss
+}
+
+func (ss *sessionStore) load() {
+	data, err := os.ReadFile(ss.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Sessions: cannot read %s: %v", ss.path, err)
+		}
+		return
+	}
+	var file struct {
+		Sessions map[string]*sessionState `json:"sessions"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		log.Printf("Sessions: cannot parse %s: %v", ss.path, err)
+		return
+	}
+	if file.Sessions == nil {
+		// Files written before the session store held favorites only.
+		var legacy map[string]map[string]favoriteTeam
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			log.Printf("Sessions: cannot parse %s: %v", ss.path, err)
+			return
+		}
+		file.Sessions = make(map[string]*sessionState, len(legacy))
+		for session, favs := range legacy {
+			file.Sessions[session] = &sessionState{Favorites: favs}
+		}
+	}
+	// Restored sessions get a full idle period from startup.
+	now := time.Now()
+	for session, st := range file.Sessions {
+		if st == nil || !persistent(session) {
+			continue // files written before state was kept per key
+		}
+		st.LastSeen = now
+		ss.sessions[session] = st
+	}
+}
+
+// owner returns the name a caller's state is kept under: the id of its API
+// key or OAuth subject, or else its session ID.
+func (ss *sessionStore) owner(ctx context.Context) string {
+	session := sessionIDFromContext(ctx)
+	k, ok := apiKeyFromContext(ctx)
+	if !ok && ss.keys != nil {
+		k, ok = ss.keys.bound(session)
+	}
+	if ok {
+		return k.id()
+	}
+	return session
+}
+
+// persistent reports whether owner can be found again after a restart.
+func persistent(owner string) bool {
+	return strings.HasPrefix(owner, "key:") || strings.HasPrefix(owner, "oauth:") || owner == stdioSession
+}
+
+// view calls fn with the session's state, or with an empty state if the
+// session has none. fn must not keep or modify the state.
+func (ss *sessionStore) view(session string, fn func(*sessionState)) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	st := ss.sessions[session]
+	if st == nil {
+		st = &sessionState{}
+	} else {
+		st.LastSeen = time.Now()
+	}
+	fn(st)
+}
+
+// update calls fn with the session's state, creating it if needed, and
+// persists the store if fn reports a change.
+func (ss *sessionStore) update(session string, fn func(*sessionState) bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	st := ss.sessions[session]
+	if st == nil {
+		st = &sessionState{}
+		ss.sessions[session] = st
+	}
+	st.LastSeen = time.Now()
+	changed := fn(st)
+	if st.empty() {
+		delete(ss.sessions, session)
+	}
+	if changed {
+		ss.persist()
+	}
+}
+
+func (ss *sessionStore) count() int {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return len(ss.sessions)
+}
+
+func (ss *sessionStore) drop(session string) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if _, ok := ss.sessions[session]; !ok {
+		return
+	}
+	delete(ss.sessions, session)
+	ss.persist()
+}
+
+func (ss *sessionStore) setIdleTTL(d time.Duration) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.idleTTL = d
+}
+
+// sweep drops sessions idle since before now minus the idle TTL.
+func (ss *sessionStore) sweep(now time.Time) int {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	cutoff := now.Add(-ss.idleTTL)
+	dropped := 0
+	for session, st := range ss.sessions {
+		if st.LastSeen.Before(cutoff) {
+			delete(ss.sessions, session)
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		ss.persist()
+	}
+	return dropped
+}
+
+func (ss *sessionStore) run() {
+	for {
+		time.Sleep(sessionSweepInterval)
+		if n := ss.sweep(time.Now()); n > 0 {
+			log.Printf("Sessions: evicted %d idle session(s)", n)
+		}
+	}
+}
+
+// persist must be called with ss.mu held.
+func (ss *sessionStore) persist() {
+	if ss.path == "" {
+		return
+	}
+	saved := make(map[string]*sessionState, len(ss.sessions))
+	for owner, st := range ss.sessions {
+		if persistent(owner) {
+			saved[owner] = st
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{"sessions": saved})
+	if err != nil {
+		log.Printf("Sessions: encode error: %v", err)
+		return
+	}
+	tmp := ss.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		log.Printf("Sessions: write error: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, ss.path); err != nil {
+		log.Printf("Sessions: rename error: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Sport Modules ---
+//
+// Football is the core of the server and its tools are unprefixed. Other
+// sports from the same data family are optional modules: each is enabled by
+// giving its upstream root under sports: in the config (or in SPORTS), and
+// then registers its own tools namespaced by the sport, such as
+// basketball_get_live_scores. A module's upstream serves the footapi's
+// paths and shapes, so the tools share footAPI and the feed helpers.
+
+// sportModule describes an optional sport.
+type sportModule struct {
+	Name      string // tool prefix and config key
+	Label     string // for tool descriptions
+	TeamSport bool   // has teams and league tables; tennis has players only
+}
+
+var sportModules = map[string]sportModule{
+	"basketball": {Name: "basketball", Label: "basketball", TeamSport: true},
+	"tennis":     {Name: "tennis", Label: "tennis"},
+}
+
+// registerSports registers the tools of every sport configured in sports, a
+// map of sport name to upstream root.
+func registerSports(s *server.MCPServer, sports map[string]string) {
+	var enabled []string
+	for name, root := range sports {
+		mod, ok := sportModules[name]
+		if !ok {
+			log.Printf("Sports: unknown sport %q, known are %s", name, strings.Join(knownSports(), ", "))
+			continue
+		}
+		if root == "" {
+			log.Printf("Sports: %s has no upstream URL, not enabled", name)
+			continue
+		}
+		registerSportTools(s, mod, footAPI{base: strings.TrimRight(root, "/")})
+		enabled = append(enabled, name)
+	}
+	sort.Strings(enabled)
+	if len(enabled) > 0 {
+		log.Printf("Sports: enabled %s", strings.Join(enabled, ", "))
+	}
+}
+
+func knownSports() []string {
+	names := make([]string, 0, len(sportModules))
+	for name := range sportModules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func registerSportTools(s *server.MCPServer, mod sportModule, src DataSource) {
+	tool := func(name string) string { return mod.Name + "_" + name }
+	title := strings.ToUpper(mod.Label[:1]) + mod.Label[1:]
+
+	s.AddTool(
+		mcp.NewTool(tool("get_live_scores"),
+			mcp.WithDescription(fmt.Sprintf("Get all currently live %s matches with real-time scores. All timestamps are GMT/UTC.", mod.Label)),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.). Default: en")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			data, err := src.LiveScores(ctx, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			return jsonResult(title+" Live Scores", data), nil
+		},
+	)
+
+	s.AddTool(
+		mcp.NewTool(tool("get_day_fixtures"),
+			mcp.WithDescription(fmt.Sprintf("Get all %s fixtures for a date. All timestamps are GMT/UTC.", mod.Label)),
+			mcp.WithString("date", mcp.Required(), mcp.Description("Date in DD/MM/YYYY format (e.g. 30/08/2025)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+			mcp.WithNumber("tzoffset", mcp.Description("Timezone offset in minutes (e.g. 120 for UTC+2). Default: 0")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			date := getStr(req.Params.Arguments, "date", "")
+			data, err := src.Fixtures(ctx, date, getInt(req.Params.Arguments, "tzoffset", 0), queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			return jsonResult(fmt.Sprintf("%s fixtures for %s", title, date), data), nil
+		},
+	)
+
+	s.AddTool(
+		mcp.NewTool(tool("get_match"),
+			mcp.WithDescription(fmt.Sprintf("Get details of a %s match by match ID", mod.Label)),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Match ID from live scores or fixtures")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "id", "")
+			data, err := src.Match(ctx, id, false, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			return jsonResult(fmt.Sprintf("%s match info for ID %s", title, id), data), nil
+		},
+	)
+
+	s.AddTool(
+		mcp.NewTool(tool("search"),
+			mcp.WithDescription(fmt.Sprintf("Search %s teams, players or competitions by name", mod.Label)),
+			mcp.WithString("q", mcp.Required(), mcp.Description("Search term")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			query := getStr(req.Params.Arguments, "q", "")
+			data, err := src.Search(ctx, query, "", queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			return jsonResult(fmt.Sprintf("%s search results for '%s'", title, query), data), nil
+		},
+	)
+
+	if !mod.TeamSport {
+		s.AddTool(
+			mcp.NewTool(tool("get_player"),
+				mcp.WithDescription(fmt.Sprintf("Get a %s player's profile and results by player ID", mod.Label)),
+				mcp.WithString("id", mcp.Required(), mcp.Description("Player ID from search results")),
+				mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+			),
+			func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				id := getStr(req.Params.Arguments, "id", "")
+				data, err := src.Player(ctx, id, queryOf(req.Params.Arguments))
+				if err != nil {
+					return errorResult(err), nil
+				}
+				return jsonResult(fmt.Sprintf("%s player info for ID %s", title, id), data), nil
+			},
+		)
+		return
+	}
+
+	s.AddTool(
+		mcp.NewTool(tool("get_team"),
+			mcp.WithDescription(fmt.Sprintf("Get a %s team's details (roster, fixtures) by team ID", mod.Label)),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Team ID from search results")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "id", "")
+			data, err := src.Team(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			return jsonResult(fmt.Sprintf("%s team info for ID %s", title, id), data), nil
+		},
+	)
+
+	s.AddTool(
+		mcp.NewTool(tool("get_standings"),
+			mcp.WithDescription(fmt.Sprintf("Get the current table of a %s league by league key", mod.Label)),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := getStr(req.Params.Arguments, "league_key", "")
+			data, err := src.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				if errorCode(err) == codeNotFound {
+					return toolErrorResult(codeInvalidLeagueKey, fmt.Sprintf("unknown %s league key %q", mod.Label, key)), nil
+				}
+				return errorResult(err), nil
+			}
+			rows := extractStandings(data)
+			if len(rows) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no standings found for %s", key)), nil
+			}
+			return jsonResult(fmt.Sprintf("%s standings for %s", title, key), rows), nil
+		},
+	)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Squads ---
+//
+// get_team returns the team page as upstream sends it, plus aggregates of the
+// squad (size, average age, foreigners, market value, most capped players).
+// get_squad lists just the players as fixed rows, with contract end dates
+// where upstream has them, and can narrow the list to contracts running out
+// soon, for transfer window questions.
+
+var contractKeys = []string{"contractuntil", "contractend", "contractexpiry", "contractexpires", "contract"}
+
+// squadPlayer is one player of a team's squad.
+type squadPlayer struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Position      string   `json:"position,omitempty"`
+	Number        *int     `json:"number,omitempty"`
+	Age           *int     `json:"age,omitempty"`
+	Nationality   string   `json:"nationality,omitempty"`
+	ContractUntil string   `json:"contract_until,omitempty"` // YYYY-MM-DD
+	MarketValue   *float64 `json:"market_value,omitempty"`
+	Caps          *int     `json:"caps,omitempty"` // international appearances
+}
+
+// extractSquad returns the players of a team payload.
+func extractSquad(data interface{}) []squadPlayer {
+	v, _ := findKey(data, "squad", "players")
+	items, _ := v.([]interface{})
+	squad := []squadPlayer{}
+	for _, item := range items {
+		p, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		player := squadPlayer{
+			ID:            lookupStr(p, "id", "playerid"),
+			Name:          lookupStr(p, "name", "playername"),
+			Position:      lookupStr(p, "position", "pos"),
+			Number:        optInt(p, "number", "shirtnumber", "jersey"),
+			Age:           optInt(p, "age"),
+			Nationality:   lookupStr(p, "nationality", "country"),
+			ContractUntil: contractEnd(lookupStr(p, contractKeys...)),
+			Caps:          optInt(p, "caps", "internationalcaps"),
+		}
+		if value, _, _, ok := parseValue(p); ok {
+			player.MarketValue = &value
+		}
+		squad = append(squad, player)
+	}
+	return squad
+}
+
+// contractEnd normalises a contract end date to YYYY-MM-DD. A bare year is
+// taken as the end of June, when most contracts run out.
+func contractEnd(s string) string {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	if year, err := strconv.Atoi(s); err == nil && year > 1900 {
+		return fmt.Sprintf("%d-06-30", year)
+	}
+	return ""
+}
+
+// expiresWithin reports whether the contract ends before now plus months.
+func (p squadPlayer) expiresWithin(months int, now time.Time) bool {
+	end, err := time.Parse("2006-01-02", p.ContractUntil)
+	return err == nil && end.Before(now.AddDate(0, months, 0))
+}
+
+// squadStats are the aggregates get_team adds to the team page. Each figure
+// covers the players upstream gives it for; the counts say how many.
+type squadStats struct {
+	Size             int            `json:"size"`
+	AverageAge       *float64       `json:"average_age,omitempty"`
+	WithAge          int            `json:"players_with_age"`
+	Foreigners       int            `json:"foreigners"`
+	WithNationality  int            `json:"players_with_nationality"`
+	TotalMarketValue *float64       `json:"total_market_value,omitempty"`
+	WithMarketValue  int            `json:"players_with_market_value"`
+	MostCapped       []cappedPlayer `json:"most_capped,omitempty"`
+}
+
+type cappedPlayer struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Caps int    `json:"caps"`
+}
+
+const mostCappedListed = 3
+
+// aggregateSquad computes squadStats; country is the team's, for counting
+// foreigners.
+func aggregateSquad(squad []squadPlayer, country string) squadStats {
+	st := squadStats{Size: len(squad)}
+	ages, value := 0, 0.0
+	var capped []cappedPlayer
+	for _, p := range squad {
+		if p.Age != nil {
+			ages += *p.Age
+			st.WithAge++
+		}
+		if p.Nationality != "" && country != "" {
+			st.WithNationality++
+			if !strings.EqualFold(p.Nationality, country) {
+				st.Foreigners++
+			}
+		}
+		if p.MarketValue != nil {
+			value += *p.MarketValue
+			st.WithMarketValue++
+		}
+		if p.Caps != nil && *p.Caps > 0 {
+			capped = append(capped, cappedPlayer{p.ID, p.Name, *p.Caps})
+		}
+	}
+	if st.WithAge > 0 {
+		avg := math.Round(float64(ages)/float64(st.WithAge)*10) / 10
+		st.AverageAge = &avg
+	}
+	if st.WithMarketValue > 0 {
+		st.TotalMarketValue = &value
+	}
+	sort.Slice(capped, func(i, j int) bool { return capped[i].Caps > capped[j].Caps })
+	st.MostCapped = capped[:min(mostCappedListed, len(capped))]
+	return st
+}
+
+// withSquadStats adds squad_stats to a team payload.
+func withSquadStats(data interface{}) interface{} {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+	country := ""
+	if team, ok := lookup(m, "team"); ok {
+		if tm, ok := team.(map[string]interface{}); ok {
+			country = lookupStr(tm, "country")
+		}
+	}
+	m["squad_stats"] = aggregateSquad(extractSquad(data), country)
+	return m
+}
+
+func registerSquadTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_squad",
+			mcp.WithDescription("Get a team's squad as player rows (position, number, age, nationality, contract end date where known), optionally only the players whose contracts run out soon"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Team ID from search results (e.g. 13183 for Ajax)")),
+			mcp.WithNumber("expiring_contracts", mcp.Description("Only players whose contract ends within this many months (e.g. 12)")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "id", "")
+			months := getInt(req.Params.Arguments, "expiring_contracts", 0)
+			if months < 0 {
+				return toolErrorResult(codeInvalidArgument, "expiring_contracts must be a number of months"), nil
+			}
+			data, err := source.Team(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			squad := extractSquad(data)
+			if months == 0 {
+				return jsonResult(fmt.Sprintf("Squad of team %s", id), squad), nil
+			}
+			now := time.Now().UTC()
+			expiring := []squadPlayer{}
+			for _, p := range squad {
+				if p.expiresWithin(months, now) {
+					expiring = append(expiring, p)
+				}
+			}
+			return jsonResult(fmt.Sprintf("Players of team %s whose contracts end within %d months (%d of %d have a known end date)",
+				id, months, knownContracts(squad), len(squad)), expiring), nil
+		},
+	)
+}
+
+func knownContracts(squad []squadPlayer) int {
+	n := 0
+	for _, p := range squad {
+		if p.ContractUntil != "" {
+			n++
+		}
+	}
+	return n
+}
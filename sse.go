@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// --- SSE Connections ---
+//
+// Proxies and load balancers close connections that stay quiet for too long,
+// which drops idle SSE sessions after a few minutes. Every keepalive interval
+// without other traffic, the stream gets an SSE comment line, which MCP
+// clients ignore but which keeps every hop in between from timing it out.
+//
+// The keepalive also keeps streams open whose client is long gone, so each
+// client IP, as the IP filter resolves it behind trusted proxies, may hold
+// only a limited number of streams at once (ALLOW_CIDRS are exempt), and a
+// stream whose session has not posted a message for the idle
+// timeout is closed.
+
+const (
+	defaultSSEKeepAlive   = 25 * time.Second
+	defaultSSEMaxPerIP    = 10
+	defaultSSEIdleTimeout = 30 * time.Minute
+	sseReapInterval       = time.Minute
+)
+
+type sseGuard struct {
+	keepAlive   atomic.Int64 // heartbeat interval, a time.Duration
+	idleTimeout atomic.Int64 // a time.Duration
+	maxPerIP    atomic.Int64
+	ips         *ipFilter
+
+	mu    sync.Mutex
+	conns map[string]*sseConn // by session ID
+	perIP map[string]int
+}
+
+// sseConn is one open SSE stream.
+type sseConn struct {
+	ip         string
+	cancel     context.CancelFunc
+	lastActive time.Time
+}
+
+type sseConnKey struct{}
+
+func newSSEGuard(cfg *serverConfig, ips *ipFilter) *sseGuard {
+	g := &sseGuard{ips: ips, conns: make(map[string]*sseConn), perIP: make(map[string]int)}
+	g.reconfigure(cfg)
+	return g
+}
+
+func (g *sseGuard) reconfigure(cfg *serverConfig) {
+	g.keepAlive.Store(int64(orDefault(cfg.SSE.KeepAlive, defaultSSEKeepAlive)))
+	g.idleTimeout.Store(int64(orDefault(cfg.SSE.IdleTimeout, defaultSSEIdleTimeout)))
+	maxPerIP := int64(defaultSSEMaxPerIP)
+	if cfg.SSE.MaxPerIP > 0 {
+		maxPerIP = int64(cfg.SSE.MaxPerIP)
+	}
+	g.maxPerIP.Store(maxPerIP)
+}
+
+// acquire counts a new stream for ip, or reports false when ip is at its
+// limit.
+func (g *sseGuard) acquire(ip string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.ips.exempt(ip) && int64(g.perIP[ip]) >= g.maxPerIP.Load() {
+		return false
+	}
+	g.perIP[ip]++
+	return true
+}
+
+func (g *sseGuard) release(ip string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.perIP[ip]--; g.perIP[ip] <= 0 {
+		delete(g.perIP, ip)
+	}
+}
+
+// newSessionID generates the session ID of a new stream and links the two,
+// so messages posted to the session count as activity on the stream.
+func (g *sseGuard) newSessionID(ctx context.Context, r *http.Request) (string, error) {
+	id := uuid.NewString()
+	if conn, ok := ctx.Value(sseConnKey{}).(*sseConn); ok {
+		g.mu.Lock()
+		g.conns[id] = conn
+		g.mu.Unlock()
+	}
+	return id, nil
+}
+
+// touch records activity on the session a message is posted to.
+func (g *sseGuard) touch(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.mu.Lock()
+		if conn, ok := g.conns[r.URL.Query().Get("sessionId")]; ok {
+			conn.lastActive = time.Now()
+		}
+		g.mu.Unlock()
+		next(w, r)
+	}
+}
+
+// run closes streams that have been idle for longer than the idle timeout.
+func (g *sseGuard) run() {
+	for {
+		time.Sleep(sseReapInterval)
+		cutoff := time.Now().Add(-time.Duration(g.idleTimeout.Load()))
+		g.mu.Lock()
+		for id, conn := range g.conns {
+			if conn.lastActive.Before(cutoff) {
+				log.Printf("SSE: closing idle session %s from %s", id, conn.ip)
+				conn.cancel()
+				delete(g.conns, id)
+			}
+		}
+		g.mu.Unlock()
+	}
+}
+
+func (g *sseGuard) forget(conn *sseConn) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for id, c := range g.conns {
+		if c == conn {
+			delete(g.conns, id)
+		}
+	}
+}
+
+// heartbeatWriter serializes the SSE handler's writes with the heartbeat.
+type heartbeatWriter struct {
+	http.ResponseWriter
+	mu        sync.Mutex
+	lastWrite time.Time // zero until the stream has started
+	closed    bool
+}
+
+func (hw *heartbeatWriter) Write(b []byte) (int, error) {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+	hw.lastWrite = time.Now()
+	return hw.ResponseWriter.Write(b)
+}
+
+func (hw *heartbeatWriter) Flush() {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+	if f, ok := hw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (hw *heartbeatWriter) Unwrap() http.ResponseWriter { return hw.ResponseWriter }
+
+// beat writes a comment whenever the stream has been quiet for interval,
+// until stop is closed.
+func (hw *heartbeatWriter) beat(interval func() time.Duration, stop <-chan struct{}) {
+	timer := time.NewTimer(interval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+		}
+		wait := interval()
+		hw.mu.Lock()
+		if hw.closed {
+			hw.mu.Unlock()
+			return
+		}
+		if idle := time.Since(hw.lastWrite); !hw.lastWrite.IsZero() && idle >= wait {
+			hw.ResponseWriter.Write([]byte(": keepalive\n\n"))
+			if f, ok := hw.ResponseWriter.(http.Flusher); ok {
+				f.Flush()
+			}
+			hw.lastWrite = time.Now()
+		} else if !hw.lastWrite.IsZero() {
+			wait -= idle
+		}
+		hw.mu.Unlock()
+		timer.Reset(wait)
+	}
+}
+
+// middleware enforces the per-IP limit and idle timeout on the SSE stream
+// served by next, and adds heartbeats to it.
+func (g *sseGuard) middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ip := g.ips.clientIP(r)
+		if !g.acquire(ip) {
+			limit := g.maxPerIP.Load()
+			log.Printf("SSE: %s is at its limit of %d sessions", ip, limit)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", strconv.Itoa(int(sseReapInterval.Seconds())))
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintf(w, `{"error":"too many open sessions from this address","code":"RATE_LIMITED","limit":%d}`, limit)
+			return
+		}
+		defer g.release(ip)
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		conn := &sseConn{ip: ip, cancel: cancel, lastActive: time.Now()}
+		defer g.forget(conn)
+		r = r.WithContext(context.WithValue(ctx, sseConnKey{}, conn))
+
+		hw := &heartbeatWriter{ResponseWriter: w}
+		stop := make(chan struct{})
+		go hw.beat(func() time.Duration { return time.Duration(g.keepAlive.Load()) }, stop)
+		defer func() {
+			close(stop)
+			hw.mu.Lock()
+			hw.closed = true
+			hw.mu.Unlock()
+		}()
+		next(hw, r)
+	}
+}
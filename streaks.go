@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Streaks ---
+//
+// get_streaks counts each team's current runs in a league from its finished
+// matches in the fixtures feed, most recent first: wins, unbeaten, scoring,
+// clean sheets, and on the other side defeats and matches without a win. The
+// feed only goes back so far, so a run that covers every match of a team in
+// it is marked as at least that long.
+
+var streakKinds = []struct {
+	name  string
+	holds func(scored, conceded int) bool
+}{
+	{"winning", func(s, c int) bool { return s > c }},
+	{"unbeaten", func(s, c int) bool { return s >= c }},
+	{"scoring", func(s, c int) bool { return s > 0 }},
+	{"clean_sheets", func(s, c int) bool { return c == 0 }},
+	{"losing", func(s, c int) bool { return s < c }},
+	{"winless", func(s, c int) bool { return s <= c }},
+}
+
+type streak struct {
+	Length  int  `json:"length"`
+	AtLeast bool `json:"at_least,omitempty"` // runs back past the feed
+}
+
+type teamStreaks struct {
+	TeamID  string            `json:"team_id,omitempty"`
+	Team    string            `json:"team"`
+	Matches int               `json:"matches_in_feed"`
+	Streaks map[string]streak `json:"streaks"`
+}
+
+type longestStreak struct {
+	streak
+	TeamID string `json:"team_id,omitempty"`
+	Team   string `json:"team"`
+}
+
+// leagueStreaks returns the current streaks of every team with finished
+// matches in data.
+func leagueStreaks(data interface{}) []teamStreaks {
+	type result struct {
+		scored, conceded int
+	}
+	type history struct {
+		id, name string
+		played   []feedMatch
+	}
+	teams := map[string]*history{}
+	var order []string
+	seen := map[string]bool{}
+	for _, fm := range extractMatches(data) {
+		if !fm.finished() || !fm.HasScore || seen[fm.ID] {
+			continue
+		}
+		seen[fm.ID] = true
+		for _, side := range [][2]string{{fm.HomeID, fm.HomeName}, {fm.AwayID, fm.AwayName}} {
+			key := side[0]
+			if key == "" {
+				key = side[1]
+			}
+			if teams[key] == nil {
+				teams[key] = &history{id: side[0], name: side[1]}
+				order = append(order, key)
+			}
+			teams[key].played = append(teams[key].played, fm)
+		}
+	}
+	out := make([]teamStreaks, 0, len(order))
+	for _, key := range order {
+		h := teams[key]
+		sort.SliceStable(h.played, func(i, j int) bool {
+			ti, _ := h.played[i].kickoff()
+			tj, _ := h.played[j].kickoff()
+			return ti.After(tj)
+		})
+		results := make([]result, len(h.played))
+		for i, fm := range h.played {
+			results[i] = result{fm.HomeGoals, fm.AwayGoals}
+			if fm.HomeID != h.id || (h.id == "" && fm.HomeName != h.name) {
+				results[i] = result{fm.AwayGoals, fm.HomeGoals}
+			}
+		}
+		ts := teamStreaks{TeamID: h.id, Team: h.name, Matches: len(results), Streaks: map[string]streak{}}
+		for _, kind := range streakKinds {
+			n := 0
+			for n < len(results) && kind.holds(results[n].scored, results[n].conceded) {
+				n++
+			}
+			if n > 0 {
+				ts.Streaks[kind.name] = streak{Length: n, AtLeast: n == len(results)}
+			}
+		}
+		out = append(out, ts)
+	}
+	return out
+}
+
+// longestStreaks returns the longest current streak of each kind.
+func longestStreaks(teams []teamStreaks) map[string]longestStreak {
+	longest := map[string]longestStreak{}
+	for _, ts := range teams {
+		for kind, st := range ts.Streaks {
+			if st.Length > longest[kind].Length {
+				longest[kind] = longestStreak{st, ts.TeamID, ts.Team}
+			}
+		}
+	}
+	return longest
+}
+
+func registerStreakTools(s *server.MCPServer, catalog *competitionCatalog) {
+	s.AddTool(
+		mcp.NewTool("get_streaks",
+			mcp.WithDescription("Get the active streaks of every team in a league (e.g. \"Leverkusen unbeaten in 28\"): winning, unbeaten, scoring, clean sheets, losing and winless runs from the most recent results, and the longest of each"),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			teams := leagueStreaks(data)
+			if len(teams) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no finished matches found for %s", key)), nil
+			}
+			return jsonResult(fmt.Sprintf("Current streaks in %s from the fixtures feed (at_least: the run goes back past the feed's first match)", key),
+				map[string]interface{}{"longest": longestStreaks(teams), "teams": teams}), nil
+		},
+	)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Resource Subscriptions ---
+//
+// mcp-go advertises the subscribe capability but does not route
+// resources/subscribe, so those requests are answered here before they reach
+// the SSE server. Events from the live engine are turned into
+// notifications/resources/updated for the subscribed sessions.
+
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+)
+
+var liveMatchURI = regexp.MustCompile(`^match://([^/]+)/live$`)
+
+type subscriptionManager struct {
+	mu     sync.Mutex
+	subs   map[string]map[string]bool // uri -> session IDs
+	server *server.MCPServer
+}
+
+func newSubscriptionManager(s *server.MCPServer) *subscriptionManager {
+	return &subscriptionManager{
+		subs:   make(map[string]map[string]bool),
+		server: s,
+	}
+}
+
+func (sm *subscriptionManager) subscribe(session, uri string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.subs[uri] == nil {
+		sm.subs[uri] = make(map[string]bool)
+	}
+	sm.subs[uri][session] = true
+}
+
+func (sm *subscriptionManager) unsubscribe(session, uri string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	delete(sm.subs[uri], session)
+	if len(sm.subs[uri]) == 0 {
+		delete(sm.subs, uri)
+	}
+}
+
+func (sm *subscriptionManager) dropSession(session string) {
+	sm.mu.Lock()
+	uris := make([]string, 0)
+	for uri, sessions := range sm.subs {
+		if sessions[session] {
+			uris = append(uris, uri)
+		}
+	}
+	sm.mu.Unlock()
+	for _, uri := range uris {
+		sm.unsubscribe(session, uri)
+	}
+}
+
+func (sm *subscriptionManager) notify(uri string) {
+	sm.mu.Lock()
+	sessions := make([]string, 0, len(sm.subs[uri]))
+	for session := range sm.subs[uri] {
+		sessions = append(sessions, session)
+	}
+	sm.mu.Unlock()
+
+	for _, session := range sessions {
+		err := sm.server.SendNotificationToSpecificClient(session, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		if err != nil {
+			log.Printf("Subscriptions: notify %s for %s failed: %v", session, uri, err)
+		}
+	}
+}
+
+// handle notifies subscribers of every match touched by events.
+func (sm *subscriptionManager) handle(events []matchEvent) {
+	seen := make(map[string]bool)
+	for _, e := range events {
+		uri := liveMatchResourceURI(e.MatchID)
+		if !seen[uri] {
+			seen[uri] = true
+			sm.notify(uri)
+		}
+	}
+}
+
+// middleware answers resources/subscribe and resources/unsubscribe on the
+// message endpoint and passes everything else through to next.
+func (sm *subscriptionManager) middleware(sse *server.SSEServer, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next(w, r)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "read error", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var msg struct {
+			ID     mcp.RequestId `json:"id"`
+			Method string        `json:"method"`
+			Params struct {
+				URI string `json:"uri"`
+			} `json:"params"`
+		}
+		if json.Unmarshal(body, &msg) != nil ||
+			(msg.Method != methodResourcesSubscribe && msg.Method != methodResourcesUnsubscribe) {
+			next(w, r)
+			return
+		}
+
+		session := r.URL.Query().Get("sessionId")
+		var reply any = mcp.NewJSONRPCResultResponse(msg.ID, mcp.EmptyResult{})
+		switch {
+		case !liveMatchURI.MatchString(msg.Params.URI):
+			reply = mcp.NewJSONRPCError(msg.ID, mcp.INVALID_PARAMS,
+				fmt.Sprintf("subscriptions are only supported for match://{id}/live, got %q", msg.Params.URI), nil)
+		case msg.Method == methodResourcesSubscribe:
+			sm.subscribe(session, msg.Params.URI)
+		default:
+			sm.unsubscribe(session, msg.Params.URI)
+		}
+
+		if err := sse.SendEventToSession(session, reply); err != nil {
+			sm.dropSession(session)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(mcp.NewJSONRPCError(msg.ID, mcp.INVALID_PARAMS, "Invalid session ID", nil))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func registerLiveMatchResources(s *server.MCPServer) {
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(
+			"match://{id}/live",
+			"Live match",
+			mcp.WithTemplateDescription("Current state of a match; subscribe to receive updates as the score changes"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		upstreamResource(fetchMatchNoH2H),
+	)
+}
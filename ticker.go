@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Live Ticker ---
+//
+// liveTicker keeps livescore://ticker, a plain-text line per live match
+// ("Eredivisie | Ajax 2-1 PSV | 67'"), rebuilt by the live tracker after
+// every poll. Reading it costs no upstream request, so a client can re-read
+// it as often as it likes; the first line says when the feed was polled.
+
+type liveTicker struct {
+	mu   sync.Mutex
+	text string
+}
+
+func newLiveTicker() *liveTicker {
+	return &liveTicker{text: "no live feed polled yet\n"}
+}
+
+// tickerLine formats one live match.
+func tickerLine(m feedMatch) string {
+	score := "-"
+	if m.HasScore {
+		score = fmt.Sprintf("%d-%d", m.HomeGoals, m.AwayGoals)
+	}
+	league := m.LeagueName
+	if league == "" {
+		league = m.LeagueKey
+	}
+	return fmt.Sprintf("%s | %s %s %s | %s", league, m.HomeName, score, m.AwayName, m.Status)
+}
+
+// update rebuilds the ticker from the matches of a poll, grouped by league
+// and in kickoff order within one.
+func (t *liveTicker) update(matches []feedMatch) {
+	live := make([]feedMatch, 0, len(matches))
+	for _, m := range matches {
+		if m.ID != "" {
+			live = append(live, m)
+		}
+	}
+	sort.SliceStable(live, func(i, j int) bool {
+		if live[i].LeagueName != live[j].LeagueName {
+			return live[i].LeagueName < live[j].LeagueName
+		}
+		ti, _ := live[i].kickoff()
+		tj, _ := live[j].kickoff()
+		return ti.Before(tj)
+	})
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d live matches, updated %s\n", len(live), time.Now().UTC().Format(time.RFC3339))
+	for _, m := range live {
+		b.WriteString(tickerLine(m) + "\n")
+	}
+	t.mu.Lock()
+	t.text = b.String()
+	t.mu.Unlock()
+}
+
+func (t *liveTicker) register(s *server.MCPServer) {
+	s.AddResource(
+		mcp.NewResource("livescore://ticker", "Live ticker",
+			mcp.WithResourceDescription("One line per live match (league | home score away | minute), refreshed after every poll of the live feed"),
+			mcp.WithMIMEType("text/plain"),
+		),
+		func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			t.mu.Lock()
+			text := t.text
+			t.mu.Unlock()
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "text/plain", Text: text},
+			}, nil
+		},
+	)
+}
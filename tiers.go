@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// --- Competition Tiers ---
+//
+// A day feed lists hundreds of matches, most of them club friendlies and
+// lower leagues nobody asking about "today's matches" means. Competitions
+// are put in one of three tiers from their keys and names:
+//
+//	1  top flights of the major football countries, continental club
+//	   competitions and the big international tournaments
+//	2  other top flights, second divisions and domestic cups
+//	3  lower leagues, regional and amateur football, youth and reserve
+//	   football, and friendlies
+//
+// competitions_tier keeps the tiers up to the given one and
+// exclude_friendlies drops friendlies of any kind.
+
+const lowestTier = 3
+
+var tierOption = mcp.WithNumber("competitions_tier",
+	mcp.Description("Only competitions up to this tier: 1 major leagues and tournaments, 2 adds other top flights, second divisions and cups, 3 everything. Default: 3"),
+)
+
+var friendliesOption = mcp.WithBoolean("exclude_friendlies",
+	mcp.Description("Leave out club and international friendlies. Default: false"),
+)
+
+// topFlights are the tier 1 domestic leagues.
+var topFlights = map[string]bool{
+	"englandpremierleague":     true,
+	"spainlaliga":              true,
+	"italyseriea":              true,
+	"germanybundesliga":        true,
+	"franceligue1":             true,
+	"netherlandseredivisie":    true,
+	"portugalprimeiraliga":     true,
+	"belgiumjupilerproleague":  true,
+	"brazilseriea":             true,
+	"argentinaligaprofesional": true,
+	"usamls":                   true,
+}
+
+var (
+	majorCompetitionName = regexp.MustCompile(`(?i)\b(champions league|europa league|conference league|world cup|euro(pean championship)?|copa am[eé]rica|africa cup of nations|asian cup|nations league|club world cup|copa libertadores)\b`)
+	friendlyName         = regexp.MustCompile(`(?i)\b(friendl(y|ies)|club friendlies|test match(es)?|vriendschappelijk|freundschaftsspiel)`)
+	lowerLeagueName      = regexp.MustCompile(`(?i)\b(regional|amateur|division [3-9]|[3-9](rd|th) division|third|fourth|liga [3-9]|national league (north|south)|oberliga|regionalliga|tweede divisie|derde divisie|serie d|tercera|national 2|national 3)\b`)
+	secondTierName       = regexp.MustCompile(`(?i)\b(championship|2\. bundesliga|segunda|serie b|ligue 2|eerste divisie|cup|pokal|coupe|copa|coppa|beker|ta[cç]a)\b`)
+)
+
+// isFriendly reports whether a competition is a friendly.
+func isFriendly(key, name string) bool {
+	return friendlyName.MatchString(name) || strings.Contains(strings.ToLower(key), "friendl")
+}
+
+// competitionTier places a competition in tier 1, 2 or 3.
+func competitionTier(key, name string) int {
+	id := strings.ToLower(strings.TrimSuffix(key, "_small"))
+	switch {
+	case isFriendly(key, name) || lowerLeagueName.MatchString(name) || youthLevel(key, name) != "":
+		return 3
+	case topFlights[id] || majorCompetitionName.MatchString(name) || strings.HasPrefix(id, "eurocups"):
+		return 1
+	case secondTierName.MatchString(name):
+		return 2
+	case strings.Contains(id, "cup"):
+		return 2
+	}
+	// A league of unknown standing: a country's top flight usually has its
+	// own name (Eredivisie, Allsvenskan), lower leagues carry a number.
+	if strings.IndexAny(name, "0123456789") >= 0 {
+		return 3
+	}
+	return 2
+}
+
+// competitionFilter narrows feeds to the competitions a tool call asks for.
+type competitionFilter struct {
+	gender         string
+	maxTier        int
+	skipFriendlies bool
+	youth          string
+}
+
+// competitionFilterArg reads the gender, womens, competitions_tier,
+// exclude_friendlies and youth arguments.
+func competitionFilterArg(args any) (competitionFilter, error) {
+	gender, err := genderArg(args)
+	if err != nil {
+		return competitionFilter{}, err
+	}
+	youth, err := youthArg(args)
+	if err != nil {
+		return competitionFilter{}, err
+	}
+	f := competitionFilter{gender: gender, youth: youth, maxTier: getInt(args, "competitions_tier", lowestTier)}
+	if f.maxTier < 1 || f.maxTier > lowestTier {
+		return competitionFilter{}, fmt.Errorf("competitions_tier must be between 1 and %d", lowestTier)
+	}
+	if m, ok := args.(map[string]any); ok && m["exclude_friendlies"] == true {
+		f.skipFriendlies = true
+	}
+	return f, nil
+}
+
+// active reports whether the filter drops anything.
+func (f competitionFilter) active() bool {
+	return f.gender != "" || f.maxTier < lowestTier || f.skipFriendlies || f.youth != youthInclude
+}
+
+func (f competitionFilter) keep(fm feedMatch) bool {
+	if f.skipFriendlies && isFriendly(fm.LeagueKey, fm.LeagueName) {
+		return false
+	}
+	if f.youth != youthInclude && fm.youth() != (f.youth == youthOnly) {
+		return false
+	}
+	return fm.ofGender(f.gender) && competitionTier(fm.LeagueKey, fm.LeagueName) <= f.maxTier
+}
+
+// apply prunes a decoded feed to the matches the filter keeps.
+func (f competitionFilter) apply(data interface{}) interface{} {
+	if !f.active() {
+		return data
+	}
+	return filterFeed(data, f.keep)
+}
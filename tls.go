@@ -0,0 +1,42 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// --- TLS ---
+//
+// With TLS domains configured (TLS_DOMAINS or tls.domains) the server
+// obtains Let's Encrypt certificates itself and serves HTTPS on :443, with :80
+// answering ACME challenges and redirecting everything else to HTTPS.
+// Certificates are cached in the cache directory (default "certs") so
+// restarts do not hit issuance limits.
+
+func listenAndServeAutocert(srv *http.Server, cfg tlsConfig) error {
+	domains := cfg.Domains
+	cacheDir := cfg.CacheDir
+	if cacheDir == "" {
+		cacheDir = "certs"
+	}
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      cfg.Email,
+	}
+
+	go func() {
+		if err := http.ListenAndServe(":80", m.HTTPHandler(nil)); err != nil {
+			log.Printf("TLS: HTTP challenge listener: %v", err)
+		}
+	}()
+
+	srv.Addr = ":443"
+	srv.TLSConfig = m.TLSConfig()
+	log.Printf("TLS: serving HTTPS for %s", strings.Join(domains, ", "))
+	return srv.ListenAndServeTLS("", "")
+}
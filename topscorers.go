@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Top Scorers ---
+//
+// get_top_scorers ranks a league's scorers from the goal events of its
+// finished matches, fetched as for the discipline table: upstream has no
+// scorers feed, so the ranking covers the matches with events, at most
+// maxSeasonMatches of them, and the title says how many. Own goals count
+// for no one. It takes format=csv and format=markdown like the other list
+// tools.
+
+const defaultScorersListed = 20
+
+type topScorer struct {
+	Rank     int    `json:"rank"`
+	PlayerID string `json:"player_id,omitempty"`
+	Player   string `json:"player"`
+	TeamID   string `json:"team_id,omitempty"`
+	Team     string `json:"team,omitempty"`
+	Goals    int    `json:"goals"`
+}
+
+// topScorers tallies the goals of matches per player, most goals first;
+// players level on goals share a rank.
+func topScorers(matches []feedMatch) []topScorer {
+	players := map[string]*topScorer{}
+	var order []string
+	for _, fm := range matches {
+		for _, e := range fm.events() {
+			if !e.goal() || e.Player == "" || strings.Contains(e.Type, "own") {
+				continue
+			}
+			key := e.PlayerID
+			if key == "" {
+				key = e.Player
+			}
+			p := players[key]
+			if p == nil {
+				teamID, team := fm.eventTeam(e)
+				p = &topScorer{PlayerID: e.PlayerID, Player: e.Player, TeamID: teamID, Team: team}
+				players[key] = p
+				order = append(order, key)
+			}
+			p.Goals++
+		}
+	}
+	out := make([]topScorer, 0, len(order))
+	for _, key := range order {
+		out = append(out, *players[key])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Goals > out[j].Goals })
+	for i := range out {
+		out[i].Rank = i + 1
+		if i > 0 && out[i].Goals == out[i-1].Goals {
+			out[i].Rank = out[i-1].Rank
+		}
+	}
+	return out
+}
+
+func registerTopScorerTools(s *server.MCPServer, catalog *competitionCatalog) {
+	s.AddTool(
+		mcp.NewTool("get_top_scorers",
+			mcp.WithDescription("Get a league's top scorers this season from the goal events of its finished matches: rank, player, team and goals (own goals not counted)"),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Number of scorers listed. Default: %d", defaultScorersListed))),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+			formatOption,
+		),
+		withFormats(topScorersTable, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			limit := getInt(req.Params.Arguments, "limit", defaultScorersListed)
+			if limit < 1 {
+				return toolErrorResult(codeInvalidArgument, "limit must be at least 1"), nil
+			}
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			matches, finished := finishedWith(ctx, req, data, maxSeasonMatches, func(fm feedMatch) bool {
+				_, ok := lookup(fm.Raw, "events", "incidents", "timeline")
+				return ok
+			})
+			scorers := topScorers(matches)
+			if len(scorers) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no goal events found for %s", key)), nil
+			}
+			scorers = scorers[:min(limit, len(scorers))]
+			return jsonResult(fmt.Sprintf("Top scorers of %s (goals from %d of %d finished matches)", key, len(matches), finished), scorers), nil
+		}),
+	)
+}
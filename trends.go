@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Team Trends ---
+//
+// get_team_trends looks at a team's last N finished matches: goals for and
+// against, possession and shots per match with a rolling average over the
+// trailing rollingWindow matches, and the averages of the later half against
+// the earlier half, so "are they improving" has a number behind it.
+// Possession and shots come from the match pages and are averaged over the
+// matches that have them.
+
+const (
+	defaultTrendMatches = 10
+	rollingWindow       = 5
+)
+
+var trendStats = []struct {
+	name string
+	keys []string
+}{
+	{"possession", []string{"possession", "ballpossession"}},
+	{"shots", []string{"shots", "totalshots"}},
+	{"shots_on_target", []string{"shotsontarget", "ontarget"}},
+}
+
+// trendMatch is one match from the team's side; stats are nil when the
+// match page has none.
+type trendMatch struct {
+	MatchID      string              `json:"match_id"`
+	Date         string              `json:"date,omitempty"`
+	Opponent     string              `json:"opponent"`
+	Home         bool                `json:"home"`
+	GoalsFor     int                 `json:"goals_for"`
+	GoalsAgainst int                 `json:"goals_against"`
+	Stats        map[string]*float64 `json:"stats"`
+	Rolling      map[string]*float64 `json:"rolling_average"`
+}
+
+// teamSide returns the team's view of a match.
+func teamSide(fm feedMatch, teamID string) trendMatch {
+	home := fm.HomeID == teamID
+	tm := trendMatch{MatchID: fm.ID, Home: home, Opponent: fm.AwayName, GoalsFor: fm.HomeGoals, GoalsAgainst: fm.AwayGoals, Stats: map[string]*float64{}}
+	if !home {
+		tm.Opponent, tm.GoalsFor, tm.GoalsAgainst = fm.HomeName, fm.AwayGoals, fm.HomeGoals
+	}
+	if kickoff, ok := fm.kickoff(); ok {
+		tm.Date = kickoff.Format("2006-01-02")
+	}
+	v, _ := lookup(fm.Raw, "stats", "statistics")
+	stats, _ := v.(map[string]interface{})
+	for _, st := range trendStats {
+		tm.Stats[st.name] = nil
+		raw, ok := lookup(stats, st.keys...)
+		if !ok {
+			continue
+		}
+		if pair, ok := asSidePair(raw); ok {
+			value := pair.Home
+			if !home {
+				value = pair.Away
+			}
+			tm.Stats[st.name] = &value
+		}
+	}
+	return tm
+}
+
+// trendAverages averages goals and the stats over matches; a stat no match
+// has is nil.
+func trendAverages(matches []trendMatch) map[string]*float64 {
+	avg := map[string]*float64{}
+	if len(matches) == 0 {
+		return avg
+	}
+	goalsFor, goalsAgainst := 0, 0
+	for _, m := range matches {
+		goalsFor += m.GoalsFor
+		goalsAgainst += m.GoalsAgainst
+	}
+	gf, ga := round2(float64(goalsFor)/float64(len(matches))), round2(float64(goalsAgainst)/float64(len(matches)))
+	avg["goals_for"], avg["goals_against"] = &gf, &ga
+	for _, st := range trendStats {
+		sum, n := 0.0, 0
+		for _, m := range matches {
+			if v := m.Stats[st.name]; v != nil {
+				sum += *v
+				n++
+			}
+		}
+		avg[st.name] = nil
+		if n > 0 {
+			a := round2(sum / float64(n))
+			avg[st.name] = &a
+		}
+	}
+	return avg
+}
+
+func registerTrendTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_team_trends",
+			mcp.WithDescription("Get a team's form over its last N finished matches: goals for and against, possession and shots per match with rolling averages, overall averages, and the recent half against the earlier half to show whether the team is improving"),
+			mcp.WithString("team_id", mcp.Required(), mcp.Description("Team ID from search results (e.g. 13183 for Ajax)")),
+			mcp.WithNumber("last_n", mcp.Description(fmt.Sprintf("Number of recent matches, 2 to %d. Default: %d", maxBulkMatches, defaultTrendMatches))),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "team_id", "")
+			n := getInt(req.Params.Arguments, "last_n", defaultTrendMatches)
+			if n < 2 || n > maxBulkMatches {
+				return toolErrorResult(codeInvalidArgument, fmt.Sprintf("last_n must be between 2 and %d", maxBulkMatches)), nil
+			}
+			data, err := source.Team(ctx, id, queryOf(req.Params.Arguments))
+			if err != nil {
+				return errorResult(err), nil
+			}
+			recent := recentFinished(data, id, n)
+			if len(recent) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no finished matches found for team %s", id)), nil
+			}
+			details := matchDetails(ctx, req, recent)
+			// Oldest first, so the list reads as a timeline.
+			matches := make([]trendMatch, 0, len(details))
+			for i := len(details) - 1; i >= 0; i-- {
+				if details[i].HasScore {
+					matches = append(matches, teamSide(details[i], id))
+				}
+			}
+			for i := range matches {
+				matches[i].Rolling = trendAverages(matches[max(0, i-rollingWindow+1) : i+1])
+			}
+			half := len(matches) / 2
+			trends := map[string]interface{}{
+				"team_id": id,
+				"team":    entityName(data, "team"),
+				"matches": matches,
+				"average": trendAverages(matches),
+				"earlier": trendAverages(matches[:half]),
+				"recent":  trendAverages(matches[half:]),
+			}
+			return jsonResult(fmt.Sprintf("Trends of team %s over its last %d finished matches (earlier: first %d, recent: last %d)",
+				id, len(matches), half, len(matches)-half), trends), nil
+		},
+	)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Market Values ---
+//
+// Player valuations come from a ValueSource. By default that reads a market
+// value field from the player payload, which the footapi fills for some
+// players only. With market_value_url set, values come from a secondary
+// service instead: the URL is a template with {id} for the player ID, and
+// the response is a JSON object with the value (and optionally the currency
+// and date) at its root or under market_value.
+
+// ValueSource provides player market valuations, like DataSource provides
+// the football data.
+type ValueSource interface {
+	PlayerValue(ctx context.Context, id string, q sourceQuery) (*marketValue, error)
+}
+
+type marketValue struct {
+	PlayerID string  `json:"player_id"`
+	Player   string  `json:"player,omitempty"`
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency,omitempty"`
+	Date     string  `json:"date,omitempty"` // of the valuation
+	Source   string  `json:"source"`
+}
+
+var (
+	valueKeys    = []string{"marketvalue", "value", "valuation", "amount"}
+	currencyKeys = []string{"currency", "marketvaluecurrency"}
+)
+
+// values is the ValueSource the value tools use, like source.
+var values ValueSource = payloadValues{}
+
+// setupMarketValues switches values to the secondary service when one is
+// configured.
+func setupMarketValues(urlTemplate string) {
+	if urlTemplate == "" {
+		return
+	}
+	if !strings.Contains(urlTemplate, "{id}") {
+		log.Printf("Values: market_value_url %q has no {id}, ignored", urlTemplate)
+		return
+	}
+	values = valueAPI{template: urlTemplate}
+	log.Printf("Values: market values from %s", urlTemplate)
+}
+
+// parseValue reads a valuation from an object, which may hold it directly
+// or under a market_value object. Values given as text ("€45.5m") are
+// understood with k, m and bn suffixes.
+func parseValue(m map[string]interface{}) (value float64, currency, date string, ok bool) {
+	if inner, found := lookup(m, "marketvalue"); found {
+		if obj, isObj := inner.(map[string]interface{}); isObj {
+			m = obj
+		}
+	}
+	value, ok = parseAmount(lookupStr(m, valueKeys...))
+	return value, lookupStr(m, currencyKeys...), lookupStr(m, "date", "updated", "valuedate"), ok
+}
+
+func parseAmount(s string) (float64, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimLeft(s, "€$£ ")
+	mult := 1.0
+	for _, suf := range []struct {
+		suffix string
+		mult   float64
+	}{{"bn", 1e9}, {"m", 1e6}, {"k", 1e3}} {
+		if strings.HasSuffix(s, suf.suffix) {
+			s, mult = strings.TrimSuffix(s, suf.suffix), suf.mult
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n * mult, true
+}
+
+// payloadValues reads values from the player payload of source.
+type payloadValues struct{}
+
+func (payloadValues) PlayerValue(ctx context.Context, id string, q sourceQuery) (*marketValue, error) {
+	data, err := source.Player(ctx, id, q)
+	if err != nil {
+		return nil, err
+	}
+	root, _ := data.(map[string]interface{})
+	player, _ := lookup(root, "player")
+	profile, _ := player.(map[string]interface{})
+	for _, m := range []map[string]interface{}{profile, root} {
+		if value, currency, date, ok := parseValue(m); ok {
+			return &marketValue{PlayerID: id, Player: lookupStr(profile, "name"), Value: value, Currency: currency, Date: date, Source: "upstream"}, nil
+		}
+	}
+	return nil, notFound("no market value known for player %s", id)
+}
+
+// valueAPI reads values from the secondary service at template.
+type valueAPI struct {
+	template string
+}
+
+func (v valueAPI) PlayerValue(ctx context.Context, id string, q sourceQuery) (*marketValue, error) {
+	apiURL := strings.ReplaceAll(v.template, "{id}", url.PathEscape(id))
+	body, err := requestUpstream(ctx, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, fmt.Errorf("decode error: %v", err)
+	}
+	value, currency, date, ok := parseValue(m)
+	if !ok {
+		return nil, notFound("no market value known for player %s", id)
+	}
+	host := apiURL
+	if u, err := url.Parse(apiURL); err == nil {
+		host = u.Host
+	}
+	return &marketValue{PlayerID: id, Player: lookupStr(m, "name", "playername"), Value: value, Currency: currency, Date: date, Source: host}, nil
+}
+
+func registerValueTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_player_value",
+			mcp.WithDescription(fmt.Sprintf("Get the market value of a player, or compare up to %d players ranked by value", maxBulkTeams)),
+			mcp.WithString("id", mcp.Description("Player ID (e.g. 474972)")),
+			mcp.WithArray("ids", mcp.WithStringItems(), mcp.Description("Several player IDs to compare, instead of id")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			q := queryOf(req.Params.Arguments)
+			if id := getStr(req.Params.Arguments, "id", ""); id != "" {
+				v, err := values.PlayerValue(ctx, id, q)
+				if err != nil {
+					return errorResult(err), nil
+				}
+				return jsonResult(fmt.Sprintf("Market value of player %s", id), v), nil
+			}
+			ids, err := bulkIDs(req.Params.Arguments, maxBulkTeams)
+			if err != nil {
+				return toolErrorResult(codeInvalidArgument, "id or ids is required: "+err.Error()), nil
+			}
+			res := fetchBulk(ctx, req, ids, func(ctx context.Context, id string) (interface{}, error) {
+				return values.PlayerValue(ctx, id, q)
+			})
+			if len(res.Results) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no market values could be found: %v", res.Errors)), nil
+			}
+			ranked := make([]*marketValue, 0, len(res.Results))
+			for _, v := range res.Results {
+				ranked = append(ranked, v.(*marketValue))
+			}
+			sort.Slice(ranked, func(i, j int) bool { return ranked[i].Value > ranked[j].Value })
+			comparison := struct {
+				Ranking []*marketValue    `json:"ranking"`
+				Errors  map[string]string `json:"errors,omitempty"`
+			}{ranked, res.Errors}
+			return jsonResult(fmt.Sprintf("Market values of %d of %d players, highest first", len(ranked), len(ids)), comparison), nil
+		},
+	)
+}
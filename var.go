@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- VAR Decisions ---
+//
+// Feeds report video reviews in their own words: an event type such as
+// "goal_disallowed" or "var", or a detail like "Penalty cancelled after VAR
+// review". varDecision reads them into one of a few decisions, so a
+// disallowed goal is not told as a goal and an overturned penalty not as a
+// penalty. get_match adds the decision to each reviewed event as
+// var_decision, and the live event engine emits a var_decision event when a
+// new one appears in a live match.
+
+const (
+	varGoalDisallowed   = "goal_disallowed"
+	varGoalAwarded      = "goal_awarded"
+	varGoalConfirmed    = "goal_confirmed"
+	varPenaltyAwarded   = "penalty_awarded"
+	varPenaltyCancelled = "penalty_cancelled"
+	varRedCardAwarded   = "red_card_awarded"
+	varRedCardCancelled = "red_card_cancelled"
+	varReview           = "var_review" // a review whose outcome the feed does not say
+)
+
+var (
+	// varReviewWords are matched as whole words, so names such as Vardy or
+	// Álvarez do not read as a review; varReviewPhrases anywhere.
+	varReviewWords   = []string{"var", "review", "reviewed", "overturned", "disallowed"}
+	varReviewPhrases = []string{"ruledout", "nogoal"}
+	varCancelWords   = []string{"disallowed", "cancelled", "canceled", "ruledout", "nogoal", "overturned", "rescinded", "revoked", "downgraded", "nopenalty"}
+	varAwardWords    = []string{"awarded", "given", "upgraded"}
+)
+
+// varDecision returns the review decision of a raw event, or "" when it was
+// not reviewed.
+func varDecision(e map[string]interface{}) string {
+	var text strings.Builder
+	for _, key := range []string{"type", "event", "kind", "eventtype", "detail", "details", "reason", "comment", "var", "decision"} {
+		if v, ok := lookup(e, key); ok {
+			text.WriteString(" " + scalarString(v) + " ")
+		}
+	}
+	// words keeps word boundaries, s drops them so "No goal" and "no_goal"
+	// both read as "nogoal".
+	words := strings.ToLower(strings.NewReplacer("_", " ", "-", " ", ",", " ", ".", " ", ":", " ", ";", " ", "(", " ", ")", " ", "/", " ", "!", " ").Replace(text.String()))
+	s := strings.ReplaceAll(words, " ", "")
+	has := func(phrases []string) bool {
+		for _, w := range phrases {
+			if strings.Contains(s, w) {
+				return true
+			}
+		}
+		return false
+	}
+	hasWord := func(list []string) bool {
+		for _, w := range list {
+			if strings.Contains(words, " "+w+" ") {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasWord(varReviewWords) && !has(varReviewPhrases) {
+		return ""
+	}
+	cancelled, awarded := has(varCancelWords), has(varAwardWords)
+	red := redCardText(words)
+	switch {
+	case strings.Contains(s, "penalty") && cancelled:
+		return varPenaltyCancelled
+	case strings.Contains(s, "penalty") && awarded:
+		return varPenaltyAwarded
+	case red && cancelled:
+		return varRedCardCancelled
+	case red && awarded:
+		return varRedCardAwarded
+	case strings.Contains(s, "goal") && cancelled:
+		return varGoalDisallowed
+	case strings.Contains(s, "goal") && awarded:
+		return varGoalAwarded
+	case strings.Contains(s, "goal") && (strings.Contains(s, "confirmed") || strings.Contains(s, "stands")):
+		return varGoalConfirmed
+	case strings.Contains(s, "nogoal"):
+		return varGoalDisallowed
+	}
+	return varReview
+}
+
+// markVARDecisions sets var_decision on the reviewed events of a match
+// page. The field only depends on the event, so marking a cached page is
+// harmless.
+func markVARDecisions(data interface{}) interface{} {
+	fm, ok := primaryMatch(data)
+	if !ok {
+		return data
+	}
+	v, ok := lookup(fm.Raw, "events", "incidents", "timeline")
+	if !ok {
+		return data
+	}
+	if m, ok := v.(map[string]interface{}); ok {
+		if inner, ok := lookup(m, "event", "events"); ok {
+			v = inner
+		}
+	}
+	items, _ := v.([]interface{})
+	for _, item := range items {
+		if e, ok := item.(map[string]interface{}); ok {
+			if decision := varDecision(e); decision != "" {
+				e["var_decision"] = decision
+			}
+		}
+	}
+	return data
+}
+
+// varDetail describes a reviewed event for the live event engine.
+func varDetail(e feedEvent) string {
+	detail := e.VAR
+	if e.Player != "" {
+		detail += " (" + e.Player + ")"
+	}
+	if e.Minute != "" {
+		detail += fmt.Sprintf(" %s'", strings.Trim(e.Minute, "' "))
+	}
+	return detail
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Venues ---
+//
+// get_venue returns a team's ground, or the venue of a match, as one fixed
+// shape: name, city, country, capacity and coordinates where upstream has
+// them, for maps and travel questions. Given from_team_id as well, it adds
+// the straight-line distance from that team's ground.
+
+const earthRadiusKm = 6371.0
+
+type venueInfo struct {
+	Name      string   `json:"name"`
+	City      string   `json:"city,omitempty"`
+	Country   string   `json:"country,omitempty"`
+	Capacity  *int     `json:"capacity,omitempty"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	TeamID    string   `json:"team_id,omitempty"` // whose ground it is
+	MatchID   string   `json:"match_id,omitempty"`
+}
+
+// extractVenue reads the venue of a team or match payload. A venue without a
+// country takes the team's or match's.
+func extractVenue(data interface{}) (venueInfo, bool) {
+	root, _ := data.(map[string]interface{})
+	holder := root
+	if team, ok := lookup(root, "team"); ok {
+		if tm, ok := team.(map[string]interface{}); ok {
+			holder = tm
+		}
+	}
+	country := lookupStr(holder, "country")
+	v, ok := lookup(holder, "venue", "stadium", "ground")
+	if !ok {
+		return venueInfo{}, false
+	}
+	vm, isObj := v.(map[string]interface{})
+	if !isObj {
+		name := scalarString(v)
+		return venueInfo{Name: name, Country: country}, name != ""
+	}
+	venue := venueInfo{
+		Name:     lookupStr(vm, "name", "venuename"),
+		City:     lookupStr(vm, "city", "town"),
+		Country:  lookupStr(vm, "country"),
+		Capacity: optInt(vm, "capacity", "seats"),
+	}
+	if venue.Country == "" {
+		venue.Country = country
+	}
+	coords := vm
+	if c, ok := lookup(vm, "coordinates", "location", "geo"); ok {
+		if cm, ok := c.(map[string]interface{}); ok {
+			coords = cm
+		}
+	}
+	venue.Latitude = optFloat(coords, "latitude", "lat")
+	venue.Longitude = optFloat(coords, "longitude", "lng", "lon", "long")
+	return venue, venue.Name != ""
+}
+
+func optFloat(m map[string]interface{}, keys ...string) *float64 {
+	f, err := strconv.ParseFloat(lookupStr(m, keys...), 64)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+// distanceKm is the great-circle distance between two venues.
+func distanceKm(a, b venueInfo) (float64, bool) {
+	if a.Latitude == nil || a.Longitude == nil || b.Latitude == nil || b.Longitude == nil {
+		return 0, false
+	}
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat, dLon := rad(*b.Latitude-*a.Latitude), rad(*b.Longitude-*a.Longitude)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad(*a.Latitude))*math.Cos(rad(*b.Latitude))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return math.Round(2*earthRadiusKm*math.Asin(math.Sqrt(h))*10) / 10, true
+}
+
+// teamVenue fetches a team's ground.
+func teamVenue(ctx context.Context, id string, q sourceQuery) (venueInfo, error) {
+	data, err := source.Team(ctx, id, q)
+	if err != nil {
+		return venueInfo{}, err
+	}
+	venue, ok := extractVenue(data)
+	if !ok {
+		return venueInfo{}, notFound("no venue known for team %s", id)
+	}
+	venue.TeamID = id
+	return venue, nil
+}
+
+func registerVenueTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("get_venue",
+			mcp.WithDescription("Get a team's stadium, or a match's venue: name, city, country, capacity, latitude and longitude where known, and optionally the distance from another team's ground"),
+			mcp.WithString("team_id", mcp.Description("Team ID whose home ground to get")),
+			mcp.WithString("match_id", mcp.Description("Match ID whose venue to get, instead of team_id")),
+			mcp.WithString("from_team_id", mcp.Description("Optional team ID to measure the straight-line distance from its ground, e.g. for away travel")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			q := queryOf(req.Params.Arguments)
+			teamID := getStr(req.Params.Arguments, "team_id", "")
+			matchID := getStr(req.Params.Arguments, "match_id", "")
+			var venue venueInfo
+			switch {
+			case matchID != "":
+				data, err := source.Match(ctx, matchID, false, q)
+				if err != nil {
+					return errorResult(err), nil
+				}
+				v, ok := extractVenue(data)
+				if !ok {
+					return toolErrorResult(codeNotFound, fmt.Sprintf("no venue known for match %s", matchID)), nil
+				}
+				venue, venue.MatchID = v, matchID
+			case teamID != "":
+				v, err := teamVenue(ctx, teamID, q)
+				if err != nil {
+					return errorResult(err), nil
+				}
+				venue = v
+			default:
+				return toolErrorResult(codeInvalidArgument, "team_id or match_id is required"), nil
+			}
+
+			from := getStr(req.Params.Arguments, "from_team_id", "")
+			if from == "" {
+				return jsonResult(fmt.Sprintf("Venue: %s", venue.Name), venue), nil
+			}
+			origin, err := teamVenue(ctx, from, q)
+			if err != nil {
+				return errorResult(err), nil
+			}
+			result := map[string]interface{}{"venue": venue, "from": origin}
+			if km, ok := distanceKm(origin, venue); ok {
+				result["distance_km"] = km
+			} else {
+				result["distance_note"] = "upstream has no coordinates for one of the venues"
+			}
+			return jsonResult(fmt.Sprintf("Venue: %s, from %s", venue.Name, origin.Name), result), nil
+		},
+	)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// --- Weather ---
+//
+// With weather_api_key set, get_match adds the weather at the venue around
+// kickoff, from OpenWeatherMap (or a compatible service at weather_url). A
+// match kicking off within weatherNowWindow of now, or under way, gets the
+// current conditions; a later one within the provider's five-day range gets
+// the nearest three-hourly forecast. Weather is an extra: when the provider
+// fails, get_match says so in weather_note and returns the match as usual.
+// Requests use a client of their own rather than the upstream one, so
+// MODE=record never writes the API key into a recording.
+
+const (
+	defaultWeatherURL = "https://api.openweathermap.org/data/2.5"
+	weatherNowWindow  = 3 * time.Hour
+	forecastRange     = 5 * 24 * time.Hour
+	weatherTimeout    = 5 * time.Second
+)
+
+// matchWeather is the weather for a match.
+type matchWeather struct {
+	Kind         string   `json:"kind"` // actual or forecast
+	Time         string   `json:"time"` // of the observation or forecast slot, UTC
+	City         string   `json:"city"`
+	Summary      string   `json:"summary,omitempty"`
+	TemperatureC *float64 `json:"temperature_c,omitempty"`
+	FeelsLikeC   *float64 `json:"feels_like_c,omitempty"`
+	Humidity     *float64 `json:"humidity_percent,omitempty"`
+	WindKmh      *float64 `json:"wind_kmh,omitempty"`
+	RainMm       *float64 `json:"rain_mm,omitempty"` // over the last or next 3 hours
+	Source       string   `json:"source"`
+}
+
+// weather is the configured provider, nil when weather is off.
+var (
+	weather       *weatherAPI
+	weatherClient = &http.Client{Timeout: weatherTimeout}
+)
+
+type weatherAPI struct {
+	base string
+	key  string
+}
+
+// setupWeather enables weather when an API key is configured.
+func setupWeather(baseURL, key string) {
+	if key == "" {
+		return
+	}
+	if baseURL == "" {
+		baseURL = defaultWeatherURL
+	}
+	weather = &weatherAPI{base: strings.TrimRight(baseURL, "/"), key: key}
+	log.Printf("Weather: conditions at kickoff from %s", weather.host())
+}
+
+func (w *weatherAPI) host() string {
+	if u, err := url.Parse(w.base); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return w.base
+}
+
+// get fetches one endpoint. Errors name the provider but not the URL, which
+// holds the API key.
+func (w *weatherAPI) get(ctx context.Context, endpoint, city string, out interface{}) error {
+	q := url.Values{"q": {city}, "units": {"metric"}, "appid": {w.key}}
+	req, err := http.NewRequestWithContext(ctx, "GET", w.base+"/"+endpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("weather provider %s: bad weather_url", w.host())
+	}
+	req.Header.Set("User-Agent", "LiveScore-MCP/1.0")
+	resp, err := weatherClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("weather provider %s unreachable", w.host())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("weather provider %s returned HTTP %d", w.host(), resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("weather provider %s: decode error: %v", w.host(), err)
+	}
+	return nil
+}
+
+// owmReading is a reading of the OpenWeatherMap current weather and forecast
+// endpoints.
+type owmReading struct {
+	Dt   int64 `json:"dt"`
+	Main struct {
+		Temp      *float64 `json:"temp"`
+		FeelsLike *float64 `json:"feels_like"`
+		Humidity  *float64 `json:"humidity"`
+	} `json:"main"`
+	Weather []struct {
+		Description string `json:"description"`
+	} `json:"weather"`
+	Wind struct {
+		Speed *float64 `json:"speed"` // m/s
+	} `json:"wind"`
+	Rain map[string]float64 `json:"rain"`
+}
+
+func (r owmReading) toWeather(kind, city, source string) *matchWeather {
+	mw := &matchWeather{
+		Kind:         kind,
+		Time:         time.Unix(r.Dt, 0).UTC().Format(time.RFC3339),
+		City:         city,
+		TemperatureC: r.Main.Temp,
+		FeelsLikeC:   r.Main.FeelsLike,
+		Humidity:     r.Main.Humidity,
+		Source:       source,
+	}
+	if len(r.Weather) > 0 {
+		mw.Summary = r.Weather[0].Description
+	}
+	if r.Wind.Speed != nil {
+		kmh := math.Round(*r.Wind.Speed*3.6*10) / 10
+		mw.WindKmh = &kmh
+	}
+	for _, period := range []string{"3h", "1h"} {
+		if mm, ok := r.Rain[period]; ok {
+			mw.RainMm = &mm
+			break
+		}
+	}
+	return mw
+}
+
+// Conditions returns the weather in city around kickoff, or nil when
+// kickoff is too far in the past or future for the provider.
+func (w *weatherAPI) Conditions(ctx context.Context, city string, kickoff, now time.Time, live bool) (*matchWeather, error) {
+	switch until := kickoff.Sub(now); {
+	case live || (until > -weatherNowWindow && until < weatherNowWindow):
+		var r owmReading
+		if err := w.get(ctx, "weather", city, &r); err != nil {
+			return nil, err
+		}
+		return r.toWeather("actual", city, w.host()), nil
+	case until > 0 && until <= forecastRange:
+		var f struct {
+			List []owmReading `json:"list"`
+		}
+		if err := w.get(ctx, "forecast", city, &f); err != nil {
+			return nil, err
+		}
+		var best *owmReading
+		for i, r := range f.List {
+			if best == nil || absDuration(time.Unix(r.Dt, 0).Sub(kickoff)) < absDuration(time.Unix(best.Dt, 0).Sub(kickoff)) {
+				best = &f.List[i]
+			}
+		}
+		if best == nil {
+			return nil, fmt.Errorf("weather provider %s has no forecast for %s", w.host(), city)
+		}
+		return best.toWeather("forecast", city, w.host()), nil
+	}
+	return nil, nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// withWeather adds the weather at kickoff to a copy of a match payload, or
+// a weather_note saying why there is none. The payload itself may be the
+// cached one, which must not keep a reading that goes stale.
+func withWeather(ctx context.Context, data interface{}) interface{} {
+	cached, ok := data.(map[string]interface{})
+	if weather == nil || !ok {
+		return data
+	}
+	fm, ok := primaryMatch(cached)
+	if !ok {
+		return data
+	}
+	m := make(map[string]interface{}, len(cached)+1)
+	for k, v := range cached {
+		m[k] = v
+	}
+	venue, _ := lookup(m, "venue", "stadium")
+	vm, _ := venue.(map[string]interface{})
+	city := lookupStr(vm, "city", "town")
+	kickoff, hasKickoff := fm.kickoff()
+	if city == "" || !hasKickoff {
+		m["weather_note"] = "no venue city or kickoff time to look up the weather for"
+		return m
+	}
+	wctx, cancel := context.WithTimeout(ctx, weatherTimeout)
+	defer cancel()
+	live := !fm.finished() && time.Now().After(kickoff)
+	w, err := weather.Conditions(wctx, city, kickoff, time.Now().UTC(), live)
+	switch {
+	case err != nil:
+		m["weather_note"] = err.Error()
+	case w == nil:
+		m["weather_note"] = "weather is available from 3 hours before kickoff to the end of the match, and as a forecast up to 5 days ahead"
+	default:
+		m["weather"] = w
+	}
+	return m
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- Webhooks ---
+
+const (
+	webhookTTL         = 24 * time.Hour
+	webhooksPerSession = 10
+)
+
+var webhookEventTypes = map[string]bool{"goal": true, "card": true, "full_time": true}
+
+type webhook struct {
+	ID        string          `json:"id"`
+	URL       string          `json:"url"`
+	MatchID   string          `json:"match_id,omitempty"`
+	TeamID    string          `json:"team_id,omitempty"`
+	Events    map[string]bool `json:"events"`
+	Session   string          `json:"-"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+func (h *webhook) wants(e matchEvent, kind string) bool {
+	if !h.Events[kind] {
+		return false
+	}
+	if h.MatchID != "" {
+		return e.MatchID == h.MatchID
+	}
+	return e.involvesTeam(h.TeamID)
+}
+
+type webhookPayload struct {
+	SubscriptionID string `json:"subscription_id"`
+	Event          string `json:"event"`
+	MatchID        string `json:"match_id"`
+	Home           string `json:"home"`
+	Away           string `json:"away"`
+	Score          string `json:"score"`
+	Status         string `json:"status,omitempty"`
+	Detail         string `json:"detail,omitempty"`
+	Timestamp      string `json:"timestamp"`
+}
+
+type webhookManager struct {
+	mu     sync.Mutex
+	hooks  map[string]*webhook
+	client *http.Client
+}
+
+func newWebhookManager() *webhookManager {
+	return &webhookManager{
+		hooks: make(map[string]*webhook),
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{Timeout: 5 * time.Second, Control: publicAddressOnly}).DialContext,
+			},
+		},
+	}
+}
+
+// nonPublicPrefixes are the IANA special-purpose address ranges (RFC 6890
+// and its updates) and multicast: loopback, private, shared (CGNAT),
+// link-local, documentation, benchmarking, reserved, and the IPv6
+// translation prefixes that can embed any IPv4 address.
+var nonPublicPrefixes = func() []netip.Prefix {
+	var out []netip.Prefix
+	for _, p := range []string{
+		"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16",
+		"172.16.0.0/12", "192.0.0.0/24", "192.0.2.0/24", "192.31.196.0/24", "192.52.193.0/24",
+		"192.88.99.0/24", "192.168.0.0/16", "192.175.48.0/24", "198.18.0.0/15",
+		"198.51.100.0/24", "203.0.113.0/24", "224.0.0.0/4", "240.0.0.0/4",
+		"::/128", "::1/128", "::ffff:0:0/96", "64:ff9b::/96", "64:ff9b:1::/48", "100::/64",
+		"2001::/23", "2001:db8::/32", "2002::/16", "3fff::/20", "fc00::/7", "fe80::/10",
+		"fec0::/10", "ff00::/8",
+	} {
+		out = append(out, netip.MustParsePrefix(p))
+	}
+	return out
+}()
+
+// publicAddressOnly stops webhook deliveries from reaching anything but
+// public unicast addresses, whatever the hostname resolved to.
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	ip := ap.Addr().WithZone("").Unmap()
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(ip) {
+			return fmt.Errorf("webhook address %s is not public", ip)
+		}
+	}
+	return nil
+}
+
+func (wm *webhookManager) add(h *webhook) error {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	count := 0
+	for _, existing := range wm.hooks {
+		if existing.Session == h.Session {
+			count++
+		}
+	}
+	if count >= webhooksPerSession {
+		return fmt.Errorf("limit of %d webhooks per session reached", webhooksPerSession)
+	}
+	wm.hooks[h.ID] = h
+	return nil
+}
+
+func (wm *webhookManager) remove(session, id string) bool {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	h, ok := wm.hooks[id]
+	if !ok || h.Session != session {
+		return false
+	}
+	delete(wm.hooks, id)
+	return true
+}
+
+// dropSession removes the webhooks of a session that has ended.
+func (wm *webhookManager) dropSession(session string) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	for id, h := range wm.hooks {
+		if h.Session == session {
+			delete(wm.hooks, id)
+		}
+	}
+}
+
+func (wm *webhookManager) active() []*webhook {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	now := time.Now()
+	hooks := make([]*webhook, 0, len(wm.hooks))
+	for id, h := range wm.hooks {
+		if now.After(h.ExpiresAt) {
+			delete(wm.hooks, id)
+			continue
+		}
+		hooks = append(hooks, h)
+	}
+	return hooks
+}
+
+// webhookKinds maps live engine event types onto webhook event types.
+var webhookKinds = map[string]string{
+	"goal":        "goal",
+	"red_card":    "card",
+	"yellow_card": "card",
+	"full_time":   "full_time",
+}
+
+// handle delivers live engine events to the interested webhooks.
+func (wm *webhookManager) handle(events []matchEvent) {
+	hooks := wm.active()
+	for _, e := range events {
+		kind, ok := webhookKinds[e.Type]
+		if !ok {
+			continue
+		}
+		for _, h := range hooks {
+			if !h.wants(e, kind) {
+				continue
+			}
+			payload := webhookPayload{
+				SubscriptionID: h.ID,
+				Event:          kind,
+				MatchID:        e.MatchID,
+				Home:           e.Home,
+				Away:           e.Away,
+				Score:          e.Score,
+				Status:         e.Status,
+				Detail:         e.Detail,
+				Timestamp:      e.Time.Format(time.RFC3339),
+			}
+			go wm.post(h.URL, payload)
+		}
+	}
+}
+
+func (wm *webhookManager) post(target string, payload webhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest("POST", target, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Webhooks: bad request for %s: %v", target, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LiveScore-MCP/1.0")
+
+	resp, err := wm.client.Do(req)
+	if err != nil {
+		log.Printf("Webhooks: delivery to %s failed: %v", target, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("Webhooks: delivery to %s returned status %d", target, resp.StatusCode)
+	}
+}
+
+func newWebhookID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func registerWebhookTools(s *server.MCPServer, wm *webhookManager) {
+	// Subscribe webhook
+	s.AddTool(
+		mcp.NewTool("subscribe_match_events",
+			mcp.WithDescription("Register a webhook URL that receives POSTed JSON for goals, cards and full-time results of a match or of every match a team plays. Subscriptions expire after 24 hours or when the session ends."),
+			mcp.WithString("url", mcp.Required(), mcp.Description("Public http(s) URL to POST events to")),
+			mcp.WithString("match_id", mcp.Description("Match ID to follow")),
+			mcp.WithString("team_id", mcp.Description("Team ID to follow (used when match_id is not given)")),
+			mcp.WithString("events", mcp.Description("Comma-separated event types: goal, card, full_time. Default: all")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			session := sessionIDFromContext(ctx)
+			if session == "" {
+				return toolErrorResult(codeSessionRequired, "webhooks require an MCP session"), nil
+			}
+			target := getStr(req.Params.Arguments, "url", "")
+			u, err := url.Parse(target)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return toolErrorResult(codeInvalidArgument, "url must be an absolute http(s) URL"), nil
+			}
+			matchID := getStr(req.Params.Arguments, "match_id", "")
+			teamID := getStr(req.Params.Arguments, "team_id", "")
+			if matchID == "" && teamID == "" {
+				return toolErrorResult(codeInvalidArgument, "either match_id or team_id is required"), nil
+			}
+
+			events := make(map[string]bool)
+			for _, e := range strings.Split(getStr(req.Params.Arguments, "events", "goal,card,full_time"), ",") {
+				e = strings.TrimSpace(e)
+				if !webhookEventTypes[e] {
+					return toolErrorResult(codeInvalidArgument, fmt.Sprintf("unknown event type %q (expected goal, card or full_time)", e)), nil
+				}
+				events[e] = true
+			}
+
+			h := &webhook{
+				ID:        newWebhookID(),
+				URL:       target,
+				MatchID:   matchID,
+				TeamID:    teamID,
+				Events:    events,
+				Session:   session,
+				ExpiresAt: time.Now().Add(webhookTTL).UTC(),
+			}
+			if err := wm.add(h); err != nil {
+				te := &toolError{Code: codeRateLimited, Message: err.Error(), Remediation: "Remove a webhook with unsubscribe_match_events first."}
+				return te.result(), nil
+			}
+			return jsonResult("Webhook subscription created", h), nil
+		},
+	)
+
+	// Unsubscribe webhook
+	s.AddTool(
+		mcp.NewTool("unsubscribe_match_events",
+			mcp.WithDescription("Remove a webhook subscription created with subscribe_match_events"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Subscription ID")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := getStr(req.Params.Arguments, "id", "")
+			if !wm.remove(sessionIDFromContext(ctx), id) {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no webhook subscription %s in this session", id)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Removed webhook subscription %s", id)), nil
+		},
+	)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// --- What-if Standings ---
+//
+// get_what_if_standings plays hypothetical results for a league's unfinished
+// matches into the current table and ranks it again by points, goal
+// difference and goals scored, so "if Ajax lose and PSV win, who's top?" is
+// answered from the table rather than by hand. Each result names the match by
+// ID with a score ("5101=2-1") or just the outcome ("5101=home", "draw",
+// "away"), which counts as 1-0, 0-0 or 0-1.
+
+var outcomeScores = map[string][2]int{"home": {1, 0}, "draw": {0, 0}, "away": {0, 1}}
+
+// whatIfRow is a table row with the position it had before the results.
+type whatIfRow struct {
+	standingRow
+	GoalDifference int `json:"goal_difference"`
+	Before         int `json:"previous_position"`
+}
+
+// parseWhatIf reads a "match_id=score" or "match_id=outcome" result.
+func parseWhatIf(s string) (id string, home, away int, err error) {
+	id, result, ok := strings.Cut(s, "=")
+	id, result = strings.TrimSpace(id), strings.ToLower(strings.TrimSpace(result))
+	if !ok || id == "" {
+		return "", 0, 0, fmt.Errorf("result %q must look like match_id=2-1 or match_id=home", s)
+	}
+	if score, ok := outcomeScores[result]; ok {
+		return id, score[0], score[1], nil
+	}
+	if home, away, ok := parseScore(result); ok && home >= 0 && away >= 0 {
+		return id, home, away, nil
+	}
+	return "", 0, 0, fmt.Errorf("result %q: %q is not a score (2-1) or home, draw or away", s, result)
+}
+
+// applyResult counts a result in a team's row.
+func (r *standingRow) applyResult(scored, conceded int) {
+	r.Played++
+	r.GoalsFor += scored
+	r.GoalsAgainst += conceded
+	switch {
+	case scored > conceded:
+		r.Won++
+		r.Points += 3
+	case scored == conceded:
+		r.Drawn++
+		r.Points++
+	default:
+		r.Lost++
+	}
+}
+
+// rankTable orders rows by points, goal difference, goals scored and name,
+// and numbers them.
+func rankTable(rows []whatIfRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch {
+		case a.Points != b.Points:
+			return a.Points > b.Points
+		case a.GoalDifference != b.GoalDifference:
+			return a.GoalDifference > b.GoalDifference
+		case a.GoalsFor != b.GoalsFor:
+			return a.GoalsFor > b.GoalsFor
+		}
+		return a.TeamName < b.TeamName
+	})
+	for i := range rows {
+		rows[i].Position = i + 1
+	}
+}
+
+func registerWhatIfTools(s *server.MCPServer, catalog *competitionCatalog) {
+	s.AddTool(
+		mcp.NewTool("get_what_if_standings",
+			mcp.WithDescription("Recompute a league table with hypothetical results for its unfinished matches (e.g. \"if Ajax lose and PSV win, who's top?\"): returns the new table with each team's previous position and the matches played into it"),
+			mcp.WithString("league_key", mcp.Required(), mcp.Description("League key from search results")),
+			mcp.WithArray("results", mcp.Required(), mcp.WithStringItems(), mcp.Description("Hypothetical results as match_id=score or match_id=outcome, e.g. [\"5101=2-1\", \"5102=away\", \"5103=draw\"]. Match IDs come from get_league_fixtures or get_title_race")),
+			mcp.WithString("language", mcp.Description("Language code (en, nl, de, etc.)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := catalog.canonicalKey(getStr(req.Params.Arguments, "league_key", ""))
+			results := getList(req.Params.Arguments, "results")
+			if len(results) == 0 {
+				return toolErrorResult(codeInvalidArgument, "results is required, e.g. [\"5101=2-1\"]"), nil
+			}
+			data, err := source.Competition(ctx, leagueFeed(key), queryOf(req.Params.Arguments))
+			if err != nil {
+				return leagueErrorResult(err, key, catalog), nil
+			}
+			ri := leagueRunIn(data)
+			if len(ri.rows) == 0 {
+				return toolErrorResult(codeNotFound, fmt.Sprintf("no standings found for %s", key)), nil
+			}
+			open := map[string]feedMatch{}
+			for _, fm := range ri.open {
+				open[fm.ID] = fm
+			}
+			table := make([]whatIfRow, len(ri.rows))
+			byTeam := map[string]*whatIfRow{}
+			for i, row := range ri.rows {
+				table[i] = whatIfRow{standingRow: row, Before: row.Position}
+				byTeam[row.TeamID] = &table[i]
+			}
+			played := []matchScore{}
+			seen := map[string]bool{}
+			for _, r := range results {
+				id, home, away, err := parseWhatIf(r)
+				if err != nil {
+					return toolErrorResult(codeInvalidArgument, err.Error()), nil
+				}
+				fm, ok := open[id]
+				if !ok {
+					return toolErrorResult(codeInvalidArgument, fmt.Sprintf("match %s is not an unfinished match of %s", id, key)), nil
+				}
+				homeRow, awayRow := byTeam[fm.HomeID], byTeam[fm.AwayID]
+				if seen[id] || homeRow == nil || awayRow == nil {
+					return toolErrorResult(codeInvalidArgument, fmt.Sprintf("match %s is given twice or between teams not in the table", id)), nil
+				}
+				seen[id] = true
+				homeRow.applyResult(home, away)
+				awayRow.applyResult(away, home)
+				ms := scoreOf(fm)
+				ms.Score = fmt.Sprintf("%d-%d", home, away)
+				played = append(played, ms)
+			}
+			for i := range table {
+				table[i].GoalDifference = table[i].standingRow.GoalDifference()
+			}
+			rankTable(table)
+			return jsonResult(fmt.Sprintf("Standings for %s after %d hypothetical results (ties on points split by goal difference, then goals scored)", key, len(played)),
+				map[string]interface{}{"results": played, "standings": table}), nil
+		},
+	)
+}
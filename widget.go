@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Score Widgets ---
+//
+// /widget/match/{id} and /widget/league/{key} are small self-refreshing HTML
+// pages meant for an <iframe>, so sites built from agent output can embed
+// real-time scores. Live data comes from the live tracker's latest poll;
+// the page reloads itself while a match is live and slows down or stops once
+// there is nothing left to update. Like the share and lineup images and the
+// team calendars, widgets are limited per IP at the anonymous tier, and
+// match pages they fetch are cached in publicMatches.
+
+const (
+	widgetUpcomingRefresh = 5 * time.Minute
+	widgetIdleRefresh     = 15 * time.Minute // league widgets with no live matches
+	publicMatchTTL        = 5 * time.Minute
+	maxPublicMatches      = 1000
+)
+
+type widgetMatch struct {
+	League   string
+	HomeID   string
+	Home     string
+	AwayID   string
+	Away     string
+	Score    string
+	Status   string
+	Live     bool
+	Finished bool
+}
+
+type widgetPage struct {
+	Title   string
+	Refresh int // seconds, 0 for none
+	OGImage string
+	Empty   string
+	Matches []widgetMatch
+}
+
+var widgetTemplate = template.Must(template.New("widget").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+{{if .Refresh}}<meta http-equiv="refresh" content="{{.Refresh}}">{{end}}
+<title>{{.Title}}</title>
+{{if .OGImage}}<meta property="og:title" content="{{.Title}}">
+<meta property="og:image" content="{{.OGImage}}">
+<meta name="twitter:card" content="summary_large_image">{{end}}
+<style>
+body{margin:0;font:14px/1.4 system-ui,-apple-system,"Segoe UI",Roboto,sans-serif;color:#10231c;background:#fff}
+.w{border:1px solid #d5e3dc;border-radius:8px;overflow:hidden}
+.h{background:#0b3d2e;color:#fff;padding:6px 10px;font-weight:600;font-size:13px}
+.m{display:grid;grid-template-columns:1fr auto 1fr;align-items:center;gap:8px;padding:8px 10px;border-top:1px solid #eef3f0}
+.m:first-of-type{border-top:0}
+.t{display:flex;align-items:center;gap:6px;min-width:0}
+.t span{overflow:hidden;text-overflow:ellipsis;white-space:nowrap}
+.a{flex-direction:row-reverse;text-align:right}
+.t img{width:24px;height:24px;flex:none}
+.s{text-align:center;font-weight:700;font-size:16px}
+.s small{display:block;font-weight:400;font-size:11px;color:#5b7368}
+.live small{color:#c62828;font-weight:600}
+.e{padding:12px 10px;color:#5b7368;text-align:center}
+.f{padding:4px 10px;font-size:11px;color:#5b7368;text-align:right;border-top:1px solid #eef3f0}
+.f a{color:inherit}
+</style>
+</head>
+<body>
+<div class="w">
+<div class="h">{{.Title}}</div>
+{{range .Matches}}<div class="m">
+<div class="t">{{if .HomeID}}<img src="/img/team/{{.HomeID}}.png?size=48" alt="" loading="lazy">{{end}}<span>{{.Home}}</span></div>
+<div class="s{{if .Live}} live{{end}}">{{.Score}}<small>{{.Status}}</small></div>
+<div class="t a">{{if .AwayID}}<img src="/img/team/{{.AwayID}}.png?size=48" alt="" loading="lazy">{{end}}<span>{{.Away}}</span></div>
+</div>
+{{else}}<div class="e">{{.Empty}}</div>
+{{end}}<div class="f"><a href="/" target="_blank" rel="noopener">LiveScore MCP</a></div>
+</div>
+</body>
+</html>
+`))
+
+func newWidgetMatch(fm feedMatch, live bool) widgetMatch {
+	wm := widgetMatch{
+		League:   fm.LeagueName,
+		Home:     fm.HomeName,
+		Away:     fm.AwayName,
+		Score:    "vs",
+		Status:   fm.Status,
+		Live:     live && !fm.finished(),
+		Finished: fm.finished(),
+	}
+	if imageIDPattern.MatchString(fm.HomeID) {
+		wm.HomeID = fm.HomeID
+	}
+	if imageIDPattern.MatchString(fm.AwayID) {
+		wm.AwayID = fm.AwayID
+	}
+	if fm.HasScore {
+		wm.Score = fmt.Sprintf("%d - %d", fm.HomeGoals, fm.AwayGoals)
+	}
+	if kickoff, ok := fm.kickoff(); ok && !fm.HasScore && (wm.Status == "" || strings.EqualFold(wm.Status, "NS")) {
+		wm.Status = kickoff.Format("2 Jan 15:04") + " UTC"
+	}
+	return wm
+}
+
+func writeWidget(w http.ResponseWriter, page widgetPage) {
+	// Widgets are made to be framed by other sites, unlike the rest of the
+	// server (see securityHeaders).
+	w.Header().Del("X-Frame-Options")
+	w.Header().Set("Content-Security-Policy", "frame-ancestors *")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	maxAge := page.Refresh
+	if maxAge == 0 {
+		maxAge = int(time.Hour.Seconds())
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	if err := widgetTemplate.Execute(w, page); err != nil {
+		log.Printf("Widget: %v", err)
+	}
+}
+
+func registerWidgetRoutes(mux *http.ServeMux, lt *liveTracker, publicURL string, limit func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("GET /widget/match/{id}", limit(func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if !imageIDPattern.MatchString(id) {
+			http.NotFound(w, r)
+			return
+		}
+		fm, live := feedMatch{}, false
+		for _, m := range lt.snapshot() {
+			if m.ID == id {
+				fm, live = m, true
+				break
+			}
+		}
+		if !live {
+			data, err := publicMatches.fetch(r.Context(), id)
+			if err != nil {
+				http.Error(w, "match unavailable", http.StatusBadGateway)
+				return
+			}
+			var found bool
+			if fm, found = primaryMatch(data); !found {
+				http.NotFound(w, r)
+				return
+			}
+		}
+
+		wm := newWidgetMatch(fm, live)
+		page := widgetPage{
+			Title:   wm.League,
+			OGImage: publicURL + "/og/match/" + id + ".png",
+			Matches: []widgetMatch{wm},
+		}
+		if page.Title == "" {
+			page.Title = wm.Home + " vs " + wm.Away
+		}
+		switch {
+		case wm.Live:
+			page.Refresh = int(time.Duration(lt.interval.Load()).Seconds())
+		case !wm.Finished:
+			page.Refresh = int(widgetUpcomingRefresh.Seconds())
+		}
+		writeWidget(w, page)
+	}))
+
+	mux.HandleFunc("GET /widget/league/{key}", limit(func(w http.ResponseWriter, r *http.Request) {
+		key := r.PathValue("key")
+		if key == "" {
+			http.NotFound(w, r)
+			return
+		}
+		var matches []widgetMatch
+		title := key
+		for _, fm := range lt.snapshot() {
+			if !fm.inLeague(key) {
+				continue
+			}
+			if fm.LeagueName != "" {
+				title = fm.LeagueName
+			}
+			matches = append(matches, newWidgetMatch(fm, true))
+		}
+		sort.Slice(matches, func(i, j int) bool { return matches[i].Home < matches[j].Home })
+
+		page := widgetPage{
+			Title:   title,
+			Refresh: int(time.Duration(lt.interval.Load()).Seconds()),
+			Empty:   "No live matches right now",
+			Matches: matches,
+		}
+		if len(matches) == 0 {
+			page.Refresh = int(widgetIdleRefresh.Seconds())
+		}
+		writeWidget(w, page)
+	}))
+}
+
+// matchPageCache keeps fetched match pages, at most max of them. In
+// publicMatches, the pages behind the public widget, share image and lineup
+// routes, every embed of a match that is not live shares one upstream
+// request per publicMatchTTL, or per hour once the match is over. Live pages
+// are not kept: they change with every poll.
+type matchPageCache struct {
+	mu      sync.Mutex
+	entries map[string]matchPageEntry
+	max     int
+}
+
+type matchPageEntry struct {
+	data    interface{}
+	expires time.Time
+}
+
+var publicMatches = newMatchPageCache(maxPublicMatches)
+
+func newMatchPageCache(max int) *matchPageCache {
+	return &matchPageCache{entries: make(map[string]matchPageEntry), max: max}
+}
+
+// fetch returns the match page of id, from the cache while it is fresh.
+// The page is shared between callers and must not be modified.
+func (c *matchPageCache) fetch(ctx context.Context, id string) (interface{}, error) {
+	now := time.Now()
+	c.mu.Lock()
+	e, ok := c.entries[id]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.data, nil
+	}
+	data, err := source.Match(ctx, id, false, queryOf(nil))
+	if err != nil {
+		return nil, err
+	}
+	fm, ok := primaryMatch(data)
+	if !ok || (fm.HasScore && !fm.finished()) {
+		return data, nil
+	}
+	ttl := publicMatchTTL
+	if fm.finished() {
+		ttl = time.Hour
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.max {
+		for key, old := range c.entries {
+			if now.After(old.expires) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	if len(c.entries) < c.max {
+		c.entries[id] = matchPageEntry{data: data, expires: now.Add(ttl)}
+	}
+	return data, nil
+}
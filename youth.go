@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// --- Youth and Reserve Football ---
+//
+// Under-19, under-21 and reserve leagues and the second teams of clubs (Jong
+// Ajax, Real Madrid Castilla) appear in the same feeds as first-team
+// football. They are recognised by their names and keys, labelled in the
+// competitions catalog, and the youth argument keeps them in (the default),
+// leaves them out or keeps only them.
+
+const (
+	youthInclude = "include"
+	youthExclude = "exclude"
+	youthOnly    = "only"
+)
+
+var youthOption = mcp.WithString("youth",
+	mcp.Enum(youthInclude, youthExclude, youthOnly),
+	mcp.Description("Youth (U19, U21, U23) and reserve football: include (default), exclude or only"),
+)
+
+var (
+	youthAge    = regexp.MustCompile(`(?i)\b(?:u-?|under[- ]?)(1[5-9]|2[0-3])\b`)
+	youthKeyAge = regexp.MustCompile(`u(1[5-9]|2[0-3])`)
+	youthName   = regexp.MustCompile(`(?i)\b(youth|juniors?|primavera|academy)\b`)
+	reserveName = regexp.MustCompile(`(?i)\b(reserves?|jong|castilla|b team)\b`)
+)
+
+// youthLevel returns the level of a youth or reserve competition or team,
+// such as "U19", "U21" or "reserve", or "" for senior football. key may be
+// empty.
+func youthLevel(key, name string) string {
+	id := strings.ToLower(key)
+	if m := youthAge.FindStringSubmatch(name); m != nil {
+		return "U" + m[1]
+	}
+	if m := youthKeyAge.FindStringSubmatch(id); m != nil {
+		return "U" + m[1]
+	}
+	switch {
+	case reserveName.MatchString(name) || strings.Contains(id, "reserve"):
+		return "reserve"
+	case youthName.MatchString(name) || strings.Contains(id, "youth") || strings.Contains(id, "primavera"):
+		return "youth"
+	}
+	return ""
+}
+
+// youth reports whether a match is youth or reserve football, by its
+// competition or its teams.
+func (fm feedMatch) youth() bool {
+	return youthLevel(fm.LeagueKey, fm.LeagueName) != "" ||
+		youthLevel("", fm.HomeName) != "" || youthLevel("", fm.AwayName) != ""
+}
+
+// youthArg reads the youth argument.
+func youthArg(args any) (string, error) {
+	youth := strings.ToLower(getStr(args, "youth", youthInclude))
+	if youth != youthInclude && youth != youthExclude && youth != youthOnly {
+		return "", fmt.Errorf("invalid youth %q: expected include, exclude or only", youth)
+	}
+	return youth, nil
+}